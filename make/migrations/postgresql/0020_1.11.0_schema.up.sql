@@ -0,0 +1,53 @@
+/*
+The manifests referenced by a manifest list or an OCI image index are pushed by digest,
+so more than one untagged artifact can live in the same repository.
+Tagged artifacts keep being unique by tag, the untagged ones are unique by digest.
+*/
+ALTER TABLE artifact DROP CONSTRAINT unique_artifact;
+CREATE UNIQUE INDEX unique_artifact_tag ON artifact (project_id, repo, tag) WHERE tag != '';
+CREATE UNIQUE INDEX unique_artifact_untagged ON artifact (project_id, repo, digest) WHERE tag = '';
+
+/*
+The status of the blob, the blob being deleted by the online garbage collection is marked as 'deleting'
+and the pushes referencing it are rejected until the deletion completes.
+*/
+ALTER TABLE blob ADD COLUMN IF NOT EXISTS status varchar(255) NOT NULL DEFAULT 'none';
+CREATE INDEX IF NOT EXISTS idx_status ON blob (status);
+
+/*
+The blobs referenced by the pushes in progress, they're recorded before the pushes check the blob status
+and removed after the artifacts are saved, the online garbage collection keeps the blobs recorded here.
+*/
+CREATE TABLE IF NOT EXISTS blob_pending_reference (
+  id SERIAL PRIMARY KEY NOT NULL,
+  digest varchar(255) NOT NULL,
+  creation_time timestamp default CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_pending_reference_digest ON blob_pending_reference (digest);
+
+/* the report of the admin job in JSON, e.g. the reclaimable manifests and blobs reported by the GC dry run */
+ALTER TABLE admin_job ADD COLUMN IF NOT EXISTS report text NOT NULL DEFAULT '';
+
+/* the parameters of the admin job provided by users in JSON, e.g. delete_untagged of GC */
+ALTER TABLE admin_job ADD COLUMN IF NOT EXISTS job_parameters text NOT NULL DEFAULT '';
+
+/* the number of the layers of one image copied concurrently by the replication */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS copy_concurrency int NOT NULL DEFAULT 0;
+
+/* the bandwidth limit in bytes per second and the allowed time windows in JSON of the replication */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS bandwidth_limit bigint NOT NULL DEFAULT 0;
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS time_windows text;
+
+/* the mapping in JSON from the source repositories to the destination ones of the replication */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS namespace_mapping text;
+
+/* the retry count and the resources in JSON used to re-schedule the failed or stopped replication tasks */
+ALTER TABLE replication_task ADD COLUMN IF NOT EXISTS retry_count int NOT NULL DEFAULT 0;
+ALTER TABLE replication_task ADD COLUMN IF NOT EXISTS resources text;
+
+/* the metadata in JSON replicated along with the artifacts, e.g. labels and scan reports */
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS replicate_metadata text;
+
+/* the tags in JSON deleted by the retention task, and whether the finished event of the retention execution is published */
+ALTER TABLE retention_task ADD COLUMN IF NOT EXISTS deleted text NOT NULL DEFAULT '';
+ALTER TABLE retention_execution ADD COLUMN IF NOT EXISTS finished boolean NOT NULL DEFAULT false;
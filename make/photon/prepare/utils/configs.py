@@ -228,6 +228,10 @@ def parse_yaml_config(config_file_path, with_notary, with_clair, with_chartmuseu
       config_dict[proxy_component + '_https_proxy'] = proxy_config.get('https_proxy') or ''
       config_dict[proxy_component + '_no_proxy'] = ','.join(all_no_proxy)
 
+    # Metric configs, optional
+    metric_config = configs.get('metric') or {}
+    config_dict['metric_addr'] = '{}:{}'.format(metric_config.get('host') or '', metric_config['port']) if metric_config.get('port') else ''
+
     # Clair configs, optional
     clair_configs = configs.get("clair") or {}
     config_dict['clair_db'] = 'postgres'
@@ -241,6 +245,7 @@ def parse_yaml_config(config_file_path, with_notary, with_clair, with_chartmuseu
     # jobservice config
     js_config = configs.get('jobservice') or {}
     config_dict['max_job_workers'] = js_config["max_job_workers"]
+    config_dict['job_options'] = js_config.get('job_options') or {}
     config_dict['jobservice_secret'] = generate_random_string(16)
 
     # notification config
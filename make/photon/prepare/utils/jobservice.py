@@ -18,6 +18,9 @@ def prepare_job_service(config_dict):
     # Job log is stored in data dir
     job_log_dir = os.path.join('/data', "job_logs")
     prepare_dir(job_log_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
+    # The OCI image layouts replicated are stored in data dir
+    oci_layout_dir = os.path.join('/data', "oci_layouts")
+    prepare_dir(oci_layout_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
     # Render Jobservice env
     render_jinja(
         job_service_env_template_path,
@@ -31,5 +34,6 @@ def prepare_job_service(config_dict):
         uid=DEFAULT_UID,
         gid=DEFAULT_GID,
         max_job_workers=config_dict['max_job_workers'],
+        job_options=config_dict['job_options'],
         redis_url=config_dict['redis_url_js'],
         level=log_level)
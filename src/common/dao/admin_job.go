@@ -28,10 +28,10 @@ func AddAdminJob(job *models.AdminJob) (int64, error) {
 	if len(job.Status) == 0 {
 		job.Status = models.JobPending
 	}
-	sql := "insert into admin_job (job_name, job_kind, status, job_uuid, cron_str, creation_time, update_time) values (?, ?, ?, ?, ?, ?, ?) RETURNING id"
+	sql := "insert into admin_job (job_name, job_kind, status, job_uuid, cron_str, job_parameters, creation_time, update_time) values (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id"
 	var id int64
 	now := time.Now()
-	err := o.Raw(sql, job.Name, job.Kind, job.Status, job.UUID, job.Cron, now, now).QueryRow(&id)
+	err := o.Raw(sql, job.Name, job.Kind, job.Status, job.UUID, job.Cron, job.Parameters, now, now).QueryRow(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -105,6 +105,20 @@ func SetAdminJobUUID(id int64, uuid string) error {
 	return err
 }
 
+// SetAdminJobReport sets the report of the admin job
+func SetAdminJobReport(id int64, report string) error {
+	o := GetOrmer()
+	j := models.AdminJob{
+		ID:     id,
+		Report: report,
+	}
+	n, err := o.Update(&j, "Report")
+	if n == 0 {
+		log.Warningf("no records are updated when updating admin job %d", id)
+	}
+	return err
+}
+
 // GetTop10AdminJobsOfName ...
 func GetTop10AdminJobsOfName(name string) ([]*models.AdminJob, error) {
 	o := GetOrmer()
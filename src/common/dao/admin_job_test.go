@@ -141,3 +141,13 @@ func (suite *AdminJobSuite) TestAdminJobUpdateStatus() {
 	require.Nil(suite.T(), err)
 	suite.Equal(job6.Status, "testStatus")
 }
+
+// TestAdminJobReport ...
+func (suite *AdminJobSuite) TestAdminJobReport() {
+	err := SetAdminJobReport(suite.job0.ID, `{"dry_run":true}`)
+	require.Nil(suite.T(), err)
+
+	job, err := GetAdminJob(suite.job0.ID)
+	require.Nil(suite.T(), err)
+	suite.Equal(`{"dry_run":true}`, job.Report)
+}
@@ -112,6 +112,82 @@ func GetArtifact(repo, tag string) (*models.Artifact, error) {
 	return artifact, nil
 }
 
+// GetUntaggedArtifact returns the artifact of the repository which was pushed by the digest and has no tag
+func GetUntaggedArtifact(repo, digest string) (*models.Artifact, error) {
+	artifact := &models.Artifact{}
+	err := GetOrmer().QueryTable(&models.Artifact{}).
+		Filter("Repo", repo).
+		Filter("Tag", "").
+		Filter("Digest", digest).One(artifact)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// ListChildArtifacts returns the artifacts referenced by the manifest list or image index with the digest
+func ListChildArtifacts(projectID int64, repo, digest string) ([]*models.Artifact, error) {
+	sql := `SELECT DISTINCT af.* FROM artifact af
+		JOIN artifact_blob afnb ON af.digest = afnb.digest_blob
+		WHERE afnb.digest_af = ? AND afnb.digest_blob != afnb.digest_af
+		AND af.project_id = ? AND af.repo = ?`
+
+	afs := []*models.Artifact{}
+	if _, err := GetOrmer().Raw(sql, digest, projectID, repo).QueryRows(&afs); err != nil {
+		return nil, err
+	}
+	return afs, nil
+}
+
+// ListParentArtifacts returns the manifest lists or image indexes which reference the manifest with the digest
+func ListParentArtifacts(projectID int64, repo, digest string) ([]*models.Artifact, error) {
+	sql := `SELECT DISTINCT af.* FROM artifact af
+		JOIN artifact_blob afnb ON af.digest = afnb.digest_af
+		WHERE afnb.digest_blob = ? AND afnb.digest_af != afnb.digest_blob
+		AND af.kind = ? AND af.project_id = ? AND af.repo = ?`
+
+	afs := []*models.Artifact{}
+	if _, err := GetOrmer().Raw(sql, digest, models.ArtifactKindImageIndex, projectID, repo).QueryRows(&afs); err != nil {
+		return nil, err
+	}
+	return afs, nil
+}
+
+// ListUntaggedArtifacts returns the manifests of the project which are neither tagged
+// nor referenced by any tagged manifest list or image index in the same repository
+func ListUntaggedArtifacts(projectID int64) ([]*models.Artifact, error) {
+	sql := `SELECT af.* FROM artifact af
+		WHERE af.project_id = ? AND af.tag = ''
+		AND NOT EXISTS (
+			SELECT 1 FROM artifact t
+			WHERE t.project_id = af.project_id AND t.repo = af.repo AND t.tag != ''
+			AND (t.digest = af.digest OR EXISTS (
+				SELECT 1 FROM artifact_blob afnb WHERE afnb.digest_af = t.digest AND afnb.digest_blob = af.digest)))
+		ORDER BY af.id`
+
+	afs := []*models.Artifact{}
+	if _, err := GetOrmer().Raw(sql, projectID).QueryRows(&afs); err != nil {
+		return nil, err
+	}
+	return afs, nil
+}
+
+// DeleteUntaggedArtifact deletes the untagged artifact, the relationships between the manifest and its blobs
+// are deleted too if the manifest isn't used by any other artifact
+func DeleteUntaggedArtifact(af *models.Artifact) error {
+	o := GetOrmer()
+	if _, err := o.Raw(`DELETE FROM artifact WHERE id = ? AND tag = ''`, af.ID).Exec(); err != nil {
+		return err
+	}
+
+	_, err := o.Raw(`DELETE FROM artifact_blob WHERE digest_af = ? AND NOT EXISTS (SELECT 1 FROM artifact WHERE digest = ?)`,
+		af.Digest, af.Digest).Exec()
+	return err
+}
+
 // GetTotalOfArtifacts returns total of artifacts
 func GetTotalOfArtifacts(query ...*models.ArtifactQuery) (int64, error) {
 	var qs orm.QuerySeter
@@ -138,5 +214,11 @@ func getArtifactQuerySetter(query *models.ArtifactQuery) orm.QuerySeter {
 	if len(query.Digest) > 0 {
 		qs = qs.Filter("Digest", query.Digest)
 	}
+	if len(query.Kind) > 0 {
+		qs = qs.Filter("Kind", query.Kind)
+	}
+	if query.Tagged {
+		qs = qs.Exclude("Tag", "")
+	}
 	return qs
 }
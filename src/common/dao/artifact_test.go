@@ -182,3 +182,62 @@ func TestGetTotalOfArtifacts(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, int64(1), total)
 }
+
+func TestListChildAndParentArtifacts(t *testing.T) {
+	repo := "TestListChildAndParentArtifacts"
+	index := &models.Artifact{
+		PID:    1,
+		Repo:   repo,
+		Tag:    "latest",
+		Digest: "index_digest",
+		Kind:   models.ArtifactKindImageIndex,
+	}
+	_, err := AddArtifact(index)
+	require.Nil(t, err)
+
+	afnbs := []*models.ArtifactAndBlob{
+		{DigestAF: index.Digest, DigestBlob: index.Digest},
+	}
+	for _, digest := range []string{"amd64_digest", "arm64_digest"} {
+		// platform specific manifests are pushed by digest
+		_, err := AddArtifact(&models.Artifact{
+			PID:    1,
+			Repo:   repo,
+			Digest: digest,
+			Kind:   models.ArtifactKindImage,
+		})
+		require.Nil(t, err)
+		afnbs = append(afnbs, &models.ArtifactAndBlob{DigestAF: index.Digest, DigestBlob: digest})
+	}
+	require.Nil(t, AddArtifactNBlobs(afnbs))
+
+	untagged, err := GetUntaggedArtifact(repo, "arm64_digest")
+	require.Nil(t, err)
+	require.NotNil(t, untagged)
+	assert.Equal(t, "", untagged.Tag)
+
+	children, err := ListChildArtifacts(1, repo, index.Digest)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(children))
+
+	parents, err := ListParentArtifacts(1, repo, "amd64_digest")
+	require.Nil(t, err)
+	require.Equal(t, 1, len(parents))
+	assert.Equal(t, "latest", parents[0].Tag)
+
+	total, err := GetTotalOfArtifacts(&models.ArtifactQuery{
+		PID:    1,
+		Repo:   repo,
+		Tagged: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	total, err = GetTotalOfArtifacts(&models.ArtifactQuery{
+		PID:  1,
+		Repo: repo,
+		Kind: models.ArtifactKindImageIndex,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+}
@@ -230,3 +230,155 @@ FROM
 
 	return exclusive, nil
 }
+
+// the condition to check whether the blob is not referenced by any artifact
+const unreferencedBlobCond = `NOT EXISTS (
+  SELECT 1 FROM artifact_blob afnb JOIN artifact af ON af.digest = afnb.digest_af
+  WHERE afnb.digest_blob = blob.digest)`
+
+// ListUnreferencedBlobs returns the blobs which are not referenced by any artifact and were created before the time,
+// the blobs are ordered by ID and started after the lastID to iterate them in batches
+func ListUnreferencedBlobs(lastID int64, before time.Time, limit int) ([]*models.Blob, error) {
+	sql := fmt.Sprintf(`SELECT * FROM blob WHERE id > ? AND creation_time < ? AND status = ? AND %s ORDER BY id LIMIT ?`,
+		unreferencedBlobCond)
+
+	var blobs []*models.Blob
+	if _, err := GetOrmer().Raw(sql, lastID, before, models.BlobStatusNone, limit).QueryRows(&blobs); err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// MarkBlobDeleting marks the blob as being deleted if it's still not referenced by any artifact,
+// returns false if the blob cannot be marked
+func MarkBlobDeleting(digest string) (bool, error) {
+	sql := fmt.Sprintf(`UPDATE blob SET status = ? WHERE digest = ? AND status = ? AND %s`, unreferencedBlobCond)
+
+	result, err := GetOrmer().Raw(sql, models.BlobStatusDeleting, digest, models.BlobStatusNone).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// IsBlobReferenced returns true if the blob is referenced by any artifact or by any request in progress,
+// both are checked in one statement to see the references saved before the pending records are removed
+func IsBlobReferenced(digest string) (bool, error) {
+	sql := `SELECT EXISTS (SELECT 1 FROM blob_pending_reference WHERE digest = ?) OR EXISTS (
+  SELECT 1 FROM artifact_blob afnb JOIN artifact af ON af.digest = afnb.digest_af WHERE afnb.digest_blob = ?)`
+
+	var referenced bool
+	if err := GetOrmer().Raw(sql, digest, digest).QueryRow(&referenced); err != nil {
+		return false, err
+	}
+	return referenced, nil
+}
+
+// AddPendingBlobReferences records the blobs referenced by a request in progress, e.g. a manifest push,
+// returns the IDs of the records
+func AddPendingBlobReferences(digests ...string) ([]int64, error) {
+	var ids []int64
+	for _, digest := range digests {
+		var id int64
+		if err := GetOrmer().Raw(`INSERT INTO blob_pending_reference (digest) VALUES (?) RETURNING id`, digest).QueryRow(&id); err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// DeletePendingBlobReferences removes the records of the blobs referenced by a request once it's done
+func DeletePendingBlobReferences(ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM blob_pending_reference WHERE id IN (%s)`, ParamPlaceholderForIn(len(ids)))
+	_, err := GetOrmer().Raw(sql, ids).Exec()
+	return err
+}
+
+// DeleteStalePendingBlobReferences removes the records created before the time, they are left by the requests
+// interrupted, e.g. core is restarted during the push
+func DeleteStalePendingBlobReferences(before time.Time) error {
+	_, err := GetOrmer().Raw(`DELETE FROM blob_pending_reference WHERE creation_time < ?`, before).Exec()
+	return err
+}
+
+// ResetBlobStatus sets the status of the blobs back to none, all the blobs being deleted are reset if no digest provided
+func ResetBlobStatus(digests ...string) error {
+	if len(digests) == 0 {
+		_, err := GetOrmer().Raw(`UPDATE blob SET status = ? WHERE status = ?`,
+			models.BlobStatusNone, models.BlobStatusDeleting).Exec()
+		return err
+	}
+
+	sql := fmt.Sprintf(`UPDATE blob SET status = ? WHERE digest IN (%s)`, ParamPlaceholderForIn(len(digests)))
+	_, err := GetOrmer().Raw(sql, models.BlobStatusNone, digests).Exec()
+	return err
+}
+
+// GetDeletingBlobs returns the digests of the blobs being deleted in the digests
+func GetDeletingBlobs(digests ...string) ([]string, error) {
+	if len(digests) == 0 {
+		return nil, nil
+	}
+
+	sql := fmt.Sprintf(`SELECT digest FROM blob WHERE status = ? AND digest IN (%s)`, ParamPlaceholderForIn(len(digests)))
+
+	var deleting []string
+	if _, err := GetOrmer().Raw(sql, models.BlobStatusDeleting, digests).QueryRows(&deleting); err != nil {
+		return nil, err
+	}
+	return deleting, nil
+}
+
+// DeleteBlobAndProjectBlobs deletes the blob and its relationships with the projects
+func DeleteBlobAndProjectBlobs(blob *models.Blob) error {
+	o := GetOrmer()
+	if _, err := o.Raw(`DELETE FROM project_blob WHERE blob_id = ?`, blob.ID).Exec(); err != nil {
+		return err
+	}
+	_, err := o.Raw(`DELETE FROM blob WHERE id = ?`, blob.ID).Exec()
+	return err
+}
+
+// GetExclusiveSizeOfArtifact returns the total size of the blobs which are only referenced by the artifact with the digest,
+// it's the size reclaimable after the artifact is deleted
+func GetExclusiveSizeOfArtifact(digest string) (int64, error) {
+	sql := `SELECT COALESCE(SUM(b.size), 0) FROM blob b
+		WHERE b.digest IN (SELECT digest_blob FROM artifact_blob WHERE digest_af = ?)
+		AND NOT EXISTS (
+			SELECT 1 FROM artifact_blob afnb JOIN artifact af ON af.digest = afnb.digest_af
+			WHERE afnb.digest_blob = b.digest AND afnb.digest_af != ?)`
+
+	var size int64
+	if err := GetOrmer().Raw(sql, digest, digest).QueryRow(&size); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// GetProjectIDsOfBlobs returns the IDs of the projects which the blobs belong to, the key of the map is the blob ID
+func GetProjectIDsOfBlobs(blobIDs ...int64) (map[int64][]int64, error) {
+	result := map[int64][]int64{}
+	if len(blobIDs) == 0 {
+		return result, nil
+	}
+
+	sql := fmt.Sprintf(`SELECT * FROM project_blob WHERE blob_id IN (%s) ORDER BY id`, ParamPlaceholderForIn(len(blobIDs)))
+
+	var projectBlobs []*models.ProjectBlob
+	if _, err := GetOrmer().Raw(sql, blobIDs).QueryRows(&projectBlobs); err != nil {
+		return nil, err
+	}
+	for _, pb := range projectBlobs {
+		result[pb.BlobID] = append(result[pb.BlobID], pb.ProjectID)
+	}
+	return result, nil
+}
@@ -17,6 +17,7 @@ package dao
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
@@ -304,3 +305,127 @@ func (suite *GetExclusiveBlobsSuite) TestInDifferentProjects() {
 func TestRunGetExclusiveBlobsSuite(t *testing.T) {
 	suite.Run(t, new(GetExclusiveBlobsSuite))
 }
+
+func TestMarkBlobDeleting(t *testing.T) {
+	withProject(func(projectID int64, projectName string) {
+		layer := digest.FromString(utils.GenerateRandomString()).String()
+		manifest, err := prepareImage(projectID, projectName, "redis", "latest", layer)
+		require.Nil(t, err)
+
+		// the blob referenced by the artifact cannot be marked
+		marked, err := MarkBlobDeleting(layer)
+		require.Nil(t, err)
+		assert.False(t, marked)
+
+		referenced, err := IsBlobReferenced(layer)
+		require.Nil(t, err)
+		assert.True(t, referenced)
+
+		require.Nil(t, DeleteArtifactByDigest(projectID, projectName+"/redis", manifest))
+
+		blobs, err := ListUnreferencedBlobs(0, time.Now().Add(time.Minute), 1000)
+		require.Nil(t, err)
+		found := false
+		for _, blob := range blobs {
+			if blob.Digest == layer {
+				found = true
+			}
+		}
+		assert.True(t, found)
+
+		marked, err = MarkBlobDeleting(layer)
+		require.Nil(t, err)
+		assert.True(t, marked)
+
+		deleting, err := GetDeletingBlobs(layer, manifest)
+		require.Nil(t, err)
+		assert.Equal(t, []string{layer}, deleting)
+
+		require.Nil(t, ResetBlobStatus(layer))
+		deleting, err = GetDeletingBlobs(layer)
+		require.Nil(t, err)
+		assert.Len(t, deleting, 0)
+
+		blob, err := GetBlob(layer)
+		require.Nil(t, err)
+		require.Nil(t, DeleteBlobAndProjectBlobs(blob))
+		blob, err = GetBlob(layer)
+		require.Nil(t, err)
+		assert.Equal(t, int64(0), blob.ID)
+	})
+}
+
+func TestPendingBlobReferences(t *testing.T) {
+	layer := digest.FromString(utils.GenerateRandomString()).String()
+
+	referenced, err := IsBlobReferenced(layer)
+	require.Nil(t, err)
+	assert.False(t, referenced)
+
+	ids, err := AddPendingBlobReferences(layer)
+	require.Nil(t, err)
+	require.Len(t, ids, 1)
+	referenced, err = IsBlobReferenced(layer)
+	require.Nil(t, err)
+	assert.True(t, referenced)
+
+	require.Nil(t, DeletePendingBlobReferences(ids...))
+	referenced, err = IsBlobReferenced(layer)
+	require.Nil(t, err)
+	assert.False(t, referenced)
+
+	// the stale records left by the interrupted requests
+	_, err = AddPendingBlobReferences(layer)
+	require.Nil(t, err)
+	require.Nil(t, DeleteStalePendingBlobReferences(time.Now().Add(time.Minute)))
+	referenced, err = IsBlobReferenced(layer)
+	require.Nil(t, err)
+	assert.False(t, referenced)
+}
+
+func TestListUntaggedArtifacts(t *testing.T) {
+	withProject(func(projectID int64, projectName string) {
+		shared := digest.FromString(utils.GenerateRandomString()).String()
+		exclusive := digest.FromString(utils.GenerateRandomString()).String()
+		_, err := prepareImage(projectID, projectName, "redis", "latest", shared)
+		require.Nil(t, err)
+		untagged, err := prepareImage(projectID, projectName, "redis", "", shared, exclusive)
+		require.Nil(t, err)
+
+		afs, err := ListUntaggedArtifacts(projectID)
+		require.Nil(t, err)
+		require.Len(t, afs, 1)
+		assert.Equal(t, untagged, afs[0].Digest)
+
+		// the manifest and the exclusive layer, 1 byte for each
+		size, err := GetExclusiveSizeOfArtifact(untagged)
+		require.Nil(t, err)
+		assert.Equal(t, int64(2), size)
+	})
+}
+
+func TestDeleteUntaggedArtifact(t *testing.T) {
+	withProject(func(projectID int64, projectName string) {
+		layer := digest.FromString(utils.GenerateRandomString()).String()
+		untagged, err := prepareImage(projectID, projectName, "redis", "", layer)
+		require.Nil(t, err)
+
+		afs, err := ListUntaggedArtifacts(projectID)
+		require.Nil(t, err)
+		require.Len(t, afs, 1)
+
+		require.Nil(t, DeleteUntaggedArtifact(afs[0]))
+
+		afs, err = ListUntaggedArtifacts(projectID)
+		require.Nil(t, err)
+		assert.Len(t, afs, 0)
+
+		blobs, err := GetBlobsByArtifact(untagged)
+		require.Nil(t, err)
+		assert.Len(t, blobs, 0)
+
+		referenced, err := IsBlobReferenced(layer)
+		require.Nil(t, err)
+		assert.False(t, referenced)
+	})
+}
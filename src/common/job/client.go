@@ -2,8 +2,10 @@ package job
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"regexp"
@@ -28,6 +30,7 @@ var (
 type Client interface {
 	SubmitJob(*models.JobData) (string, error)
 	GetJobLog(uuid string) ([]byte, error)
+	FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error
 	PostAction(uuid, action string) error
 	GetExecutions(uuid string) ([]job.Stats, error)
 	// TODO Redirect joblog when we see there's memory issue.
@@ -136,6 +139,82 @@ func (d *DefaultClient) GetJobLog(uuid string) ([]byte, error) {
 	return data, nil
 }
 
+// FollowJobLog call jobservice API to follow the log of a job from the offset, the log data is
+// written to the writer as the log grows until the job is done or the context is done.
+// The writer is flushed after each write if it's a http.Flusher.
+func (d *DefaultClient) FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error {
+	for {
+		url := fmt.Sprintf("%s/api/v1/jobs/%s/log?follow=true&offset=%d", d.endpoint, uuid, offset)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := d.client.Do(req.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				// Canceled by the caller
+				return nil
+			}
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			data, err := ioutil.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if err != nil {
+				return err
+			}
+			return &commonhttp.Error{
+				Code:    resp.StatusCode,
+				Message: string(data),
+			}
+		}
+
+		n, err := copyAndFlush(w, resp.Body)
+		_ = resp.Body.Close()
+		offset += n
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// The trailer is only available after the body is read to the end.
+		// Jobservice ends each following request in a time window, resume following
+		// if the job is not done yet. The job without status in the trailer is treated as done.
+		status := job.Status(resp.Trailer.Get(JobStatusTrailerKey))
+		if status.Validate() != nil || status.Final() {
+			return nil
+		}
+	}
+}
+
+// copyAndFlush copies the data from the reader to the writer, the writer is flushed after each write
+func copyAndFlush(w io.Writer, r io.Reader) (int64, error) {
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	var written int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			m, er := w.Write(buf[:n])
+			written += int64(m)
+			if er != nil {
+				return written, er
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
 // GetExecutions ...
 func (d *DefaultClient) GetExecutions(periodicJobID string) ([]job.Stats, error) {
 	url := fmt.Sprintf("%s/api/v1/jobs/%s/executions?page_number=1&page_size=100", d.endpoint, periodicJobID)
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"os"
 	"testing"
@@ -49,6 +51,21 @@ func TestGetJobLog(t *testing.T) {
 	assert.Contains(text, "The content in this file is for mocking the get log api.")
 }
 
+func TestFollowJobLog(t *testing.T) {
+	assert := assert.New(t)
+	buf := &bytes.Buffer{}
+	err := testClient.FollowJobLog(context.TODO(), "non", 0, buf)
+	assert.NotNil(err)
+
+	err = testClient.FollowJobLog(context.TODO(), ID, 0, buf)
+	assert.Nil(err)
+	assert.Contains(buf.String(), "The content in this file is for mocking the get log api.")
+
+	all, err := testClient.GetJobLog(ID)
+	assert.Nil(err)
+	assert.Equal(string(all), buf.String())
+}
+
 func TestGetExecutions(t *testing.T) {
 	assert := assert.New(t)
 	exes, err := testClient.GetExecutions(ID)
@@ -32,4 +32,7 @@ const (
 
 	// JobActionStop : the action to stop the job
 	JobActionStop = "stop"
+
+	// JobStatusTrailerKey : the trailer key of the job status when following the job log
+	JobStatusTrailerKey = "Job-Status"
 )
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,9 +40,27 @@ func NewJobServiceServer() *httptest.Server {
 				return
 			}
 			rw.Header().Add("Content-Type", "text/plain")
-			rw.WriteHeader(http.StatusOK)
 			f := path.Join(currPath(), "test.log")
 			b, _ := ioutil.ReadFile(f)
+			if req.URL.Query().Get("follow") == "true" {
+				// Mock the running job: the 1st half of log is returned with running status
+				// and the left is returned with success status
+				offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+				status := job.SuccessStatus
+				end := len(b)
+				if offset < len(b)/2 {
+					status = job.RunningStatus
+					end = len(b) / 2
+				}
+				rw.Header().Set("Trailer", "Job-Status")
+				rw.WriteHeader(http.StatusOK)
+				if _, err := rw.Write(b[offset:end]); err != nil {
+					panic(err)
+				}
+				rw.Header().Set("Job-Status", status.String())
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
 			_, err := rw.Write(b)
 			if err != nil {
 				panic(err)
@@ -35,6 +35,8 @@ type AdminJob struct {
 	Revision     int64     `orm:"column(revision)" json:"-"`
 	StatusCode   uint16    `orm:"column(status_code)" json:"-"`
 	Deleted      bool      `orm:"column(deleted)" json:"deleted"`
+	Parameters   string    `orm:"column(job_parameters)" json:"-"`
+	Report       string    `orm:"column(report)" json:"-"`
 	CreationTime time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
 	UpdateTime   time.Time `orm:"column(update_time);auto_now" json:"update_time"`
 }
@@ -44,6 +46,35 @@ func (a *AdminJob) TableName() string {
 	return AdminJobTable
 }
 
+// GCReport is the report of the garbage collection, the dry run of the garbage collection only generates the report
+type GCReport struct {
+	DryRun         bool               `json:"dry_run"`
+	StartTime      time.Time          `json:"start_time"`
+	EndTime        time.Time          `json:"end_time"`
+	Projects       []*GCProjectReport `json:"projects"`
+	TotalManifests int                `json:"total_manifests"`
+	TotalBlobs     int                `json:"total_blobs"`
+	// the reclaimable size in bytes, the blobs shared by the projects are counted once
+	TotalSize int64 `json:"total_size"`
+}
+
+// GCProjectReport is the report of the garbage collection for one project,
+// the blobs which don't belong to any project are reported with project ID 0
+type GCProjectReport struct {
+	ProjectID         int64           `json:"project_id"`
+	ProjectName       string          `json:"project_name"`
+	UntaggedManifests []*GCReportItem `json:"untagged_manifests"`
+	OrphanBlobs       []*GCReportItem `json:"orphan_blobs"`
+	Size              int64           `json:"size"`
+}
+
+// GCReportItem is the manifest or blob reported by the garbage collection
+type GCReportItem struct {
+	Repository string `json:"repository,omitempty"`
+	Digest     string `json:"digest"`
+	Size       int64  `json:"size"`
+}
+
 // AdminJobQuery : query parameters for adminjob
 type AdminJobQuery struct {
 	ID      int64
@@ -4,6 +4,14 @@ import (
 	"time"
 )
 
+const (
+	// ArtifactKindImage is the kind of the artifact which is a single platform image manifest
+	ArtifactKindImage = "Docker-Image"
+	// ArtifactKindImageIndex is the kind of the artifact which is a manifest list or an OCI image index
+	// that references the platform specific manifests
+	ArtifactKindImageIndex = "Docker-Image-Index"
+)
+
 // Artifact holds the details of a artifact.
 type Artifact struct {
 	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
@@ -22,11 +30,19 @@ func (af *Artifact) TableName() string {
 	return "artifact"
 }
 
+// IsIndex returns true when the artifact is a manifest list or an OCI image index
+func (af *Artifact) IsIndex() bool {
+	return af.Kind == ArtifactKindImageIndex
+}
+
 // ArtifactQuery ...
 type ArtifactQuery struct {
 	PID    int64
 	Repo   string
 	Tag    string
 	Digest string
+	Kind   string
+	// Tagged filters out the artifacts which were pushed by digest only
+	Tagged bool
 	Pagination
 }
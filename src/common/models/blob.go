@@ -6,6 +6,14 @@ import (
 	"github.com/docker/distribution/manifest/schema2"
 )
 
+const (
+	// BlobStatusNone is the status of the blob which can be used by pushes
+	BlobStatusNone = "none"
+	// BlobStatusDeleting is the status of the blob which is being deleted by the online garbage collection,
+	// the pushes referencing the blob are rejected until the deletion completes
+	BlobStatusDeleting = "deleting"
+)
+
 // Blob holds the details of a blob.
 type Blob struct {
 	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
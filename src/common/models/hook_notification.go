@@ -108,4 +108,10 @@ type EventTarget struct {
 	Address        string `json:"address"`
 	AuthHeader     string `json:"auth_header,omitempty"`
 	SkipCertVerify bool   `json:"skip_cert_verify"`
+	// PayloadFormat is "Default" or "CloudEvents", empty means "Default"
+	PayloadFormat string `json:"payload_format,omitempty"`
+	// CloudEventsMode is "structured" or "binary" when the payload format is "CloudEvents", empty means "structured"
+	CloudEventsMode string `json:"cloudevents_mode,omitempty"`
+	// Secret is used to sign the webhook requests, it's encrypted when stored in database
+	Secret string `json:"secret,omitempty"`
 }
@@ -26,8 +26,13 @@ const (
 	ProMetaSeverity             = "severity"
 	ProMetaAutoScan             = "auto_scan"
 	ProMetaReuseSysCVEWhitelist = "reuse_sys_cve_whitelist"
+	ProMetaProxyCacheRegistryID = "proxy_cache_registry_id" // the upstream registry of the proxy cache project
+	ProMetaProxyCacheStaleness  = "proxy_cache_staleness"   // the minutes during which the cached tags are served without checking the upstream
 )
 
+// DefaultProxyCacheStaleness is the staleness window of the cached tags if it isn't set for the proxy cache project
+const DefaultProxyCacheStaleness = 30 * time.Minute
+
 // ProjectMetadata holds the metadata of a project.
 type ProjectMetadata struct {
 	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
@@ -15,6 +15,7 @@
 package models
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -119,6 +120,37 @@ func (p *Project) AutoScan() bool {
 	return isTrue(auto)
 }
 
+// ProxyCacheRegistryID returns the ID of the upstream registry if the project is a proxy cache project, otherwise returns 0
+func (p *Project) ProxyCacheRegistryID() int64 {
+	value, exist := p.GetMetadata(ProMetaProxyCacheRegistryID)
+	if !exist {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
+
+// IsProxyCache returns true if the project is a proxy cache of the upstream registry
+func (p *Project) IsProxyCache() bool {
+	return p.ProxyCacheRegistryID() > 0
+}
+
+// ProxyCacheStaleness returns the window during which the cached tags are served without checking the upstream registry
+func (p *Project) ProxyCacheStaleness() time.Duration {
+	value, exist := p.GetMetadata(ProMetaProxyCacheStaleness)
+	if !exist {
+		return DefaultProxyCacheStaleness
+	}
+	minutes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || minutes < 0 {
+		return DefaultProxyCacheStaleness
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
 func isTrue(value string) bool {
 	return strings.ToLower(value) == "true" ||
 		strings.ToLower(value) == "1"
@@ -0,0 +1,40 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyCache(t *testing.T) {
+	project := &Project{}
+	assert.False(t, project.IsProxyCache())
+	assert.Equal(t, DefaultProxyCacheStaleness, project.ProxyCacheStaleness())
+
+	project.SetMetadata(ProMetaProxyCacheRegistryID, "invalid")
+	assert.False(t, project.IsProxyCache())
+
+	project.SetMetadata(ProMetaProxyCacheRegistryID, "2")
+	project.SetMetadata(ProMetaProxyCacheStaleness, "0")
+	assert.True(t, project.IsProxyCache())
+	assert.Equal(t, int64(2), project.ProxyCacheRegistryID())
+	assert.Equal(t, time.Duration(0), project.ProxyCacheStaleness())
+
+	project.SetMetadata(ProMetaProxyCacheStaleness, "10")
+	assert.Equal(t, 10*time.Minute, project.ProxyCacheStaleness())
+}
@@ -74,6 +74,18 @@ type TagDetail struct {
 	Created       time.Time `json:"created"`
 	Config        *TagCfg   `json:"config"`
 	Immutable     bool      `json:"immutable"`
+	// Manifests are the platform specific manifests when the tag points to a manifest list
+	Manifests []*PlatformManifest `json:"manifests,omitempty"`
+}
+
+// PlatformManifest holds the details of a manifest referenced by a manifest list
+type PlatformManifest struct {
+	Digest       string `json:"digest"`
+	Size         int64  `json:"size"`
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	OSVersion    string `json:"os.version"`
+	Variant      string `json:"variant,omitempty"`
 }
 
 // TagCfg ...
@@ -0,0 +1,91 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	quotaHardDesc = prometheus.NewDesc(
+		"harbor_project_quota_hard",
+		"The hard limits of the project quotas, -1 means unlimited.",
+		[]string{"project", "resource"}, nil)
+	quotaUsageDesc = prometheus.NewDesc(
+		"harbor_project_quota_usage",
+		"The usages of the project quotas.",
+		[]string{"project", "resource"}, nil)
+)
+
+// NewCollector returns a prometheus collector which exports the hard limits and
+// usages of the project quotas, the quotas are read from database when collecting
+func NewCollector() prometheus.Collector {
+	return &collector{
+		list: func() ([]*dao.Quota, error) {
+			return dao.ListQuotas(&models.QuotaQuery{
+				Reference: "project",
+			})
+		},
+	}
+}
+
+type collector struct {
+	list func() ([]*dao.Quota, error)
+}
+
+// Describe ...
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- quotaHardDesc
+	ch <- quotaUsageDesc
+}
+
+// Collect ...
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	quotas, err := c.list()
+	if err != nil {
+		log.Errorf("failed to list the quotas for metrics: %v", err)
+		return
+	}
+	for _, quota := range quotas {
+		project := quota.ReferenceID
+		if name, ok := quota.Ref["name"]; ok {
+			project = fmt.Sprintf("%v", name)
+		}
+		hard, err := types.NewResourceList(quota.Hard)
+		if err != nil {
+			log.Errorf("failed to parse the hard limits of quota %d: %v", quota.ID, err)
+			continue
+		}
+		used, err := types.NewResourceList(quota.Used)
+		if err != nil {
+			log.Errorf("failed to parse the usages of quota %d: %v", quota.ID, err)
+			continue
+		}
+		for resource, value := range hard {
+			ch <- prometheus.MustNewConstMetric(quotaHardDesc, prometheus.GaugeValue,
+				float64(value), project, string(resource))
+		}
+		for resource, value := range used {
+			ch <- prometheus.MustNewConstMetric(quotaUsageDesc, prometheus.GaugeValue,
+				float64(value), project, string(resource))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/quota/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	c := &collector{
+		list: func() ([]*dao.Quota, error) {
+			return []*dao.Quota{
+				{
+					ID:          1,
+					Ref:         driver.RefObject{"name": "library"},
+					ReferenceID: "1",
+					Hard:        `{"count": -1, "storage": 1024}`,
+					Used:        `{"count": 2, "storage": 512}`,
+				},
+				{
+					ID:          2,
+					ReferenceID: "2",
+					Hard:        `{"count": 10}`,
+					Used:        `{"count": 1}`,
+				},
+			}, nil
+		},
+	}
+	registry := prometheus.NewRegistry()
+	require.Nil(t, registry.Register(c))
+	families, err := registry.Gather()
+	require.Nil(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += "," + label.GetValue()
+			}
+			values[key] = metric.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"harbor_project_quota_hard,library,count":    -1,
+		"harbor_project_quota_hard,library,storage":  1024,
+		"harbor_project_quota_hard,2,count":          10,
+		"harbor_project_quota_usage,library,count":   2,
+		"harbor_project_quota_usage,library,storage": 512,
+		"harbor_project_quota_usage,2,count":         1,
+	}, values)
+}
@@ -15,9 +15,60 @@
 package registry
 
 import (
+	"mime"
+
 	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	// register the unmarshal function of the OCI image manifest
+	_ "github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+var (
+	// ImageManifestMediaTypes are the media types of the platform specific image manifests supported by Harbor
+	ImageManifestMediaTypes = []string{
+		schema1.MediaTypeManifest,
+		schema1.MediaTypeSignedManifest,
+		schema2.MediaTypeManifest,
+		v1.MediaTypeImageManifest,
+	}
+
+	// ManifestMediaTypes are the media types of the manifests supported by Harbor
+	ManifestMediaTypes = append([]string{
+		manifestlist.MediaTypeManifestList,
+		v1.MediaTypeImageIndex,
+	}, ImageManifestMediaTypes...)
+)
+
+// IsManifestList returns true if the media type is a Docker manifest list or an OCI image index
+func IsManifestList(mediaType string) bool {
+	mediaType = parseMediaType(mediaType)
+	return mediaType == manifestlist.MediaTypeManifestList || mediaType == v1.MediaTypeImageIndex
+}
+
+// IsSupportedManifest returns true if the media type is one of ManifestMediaTypes,
+// the parameters of the media type are ignored
+func IsSupportedManifest(mediaType string) bool {
+	mediaType = parseMediaType(mediaType)
+	for _, t := range ManifestMediaTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMediaType strips the parameters of the media type, e.g: "; charset=utf-8"
+func parseMediaType(mediaType string) string {
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return mediaType
+	}
+	return mt
+}
+
 // UnMarshal converts []byte to be distribution.Manifest
 func UnMarshal(mediaType string, data []byte) (distribution.Manifest, distribution.Descriptor, error) {
 	return distribution.UnmarshalManifest(mediaType, data)
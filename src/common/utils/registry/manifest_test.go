@@ -17,7 +17,10 @@ package registry
 import (
 	"testing"
 
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestUnMarshal(t *testing.T) {
@@ -58,3 +61,97 @@ func TestUnMarshal(t *testing.T) {
 		t.Errorf("unexpected digest: %s != %s", refs[1].Digest.String(), digest)
 	}
 }
+
+func TestUnMarshalManifestList(t *testing.T) {
+	b := []byte(`{
+   "schemaVersion":2,
+   "mediaType":"application/vnd.docker.distribution.manifest.list.v2+json",
+   "manifests":[
+      {
+         "mediaType":"application/vnd.docker.distribution.manifest.v2+json",
+         "size":527,
+         "digest":"sha256:1b26826f602946860c279fce658f31050cff2c596583af237d971f4629b57792",
+         "platform":{
+            "architecture":"amd64",
+            "os":"linux"
+         }
+      },
+      {
+         "mediaType":"application/vnd.docker.distribution.manifest.v2+json",
+         "size":527,
+         "digest":"sha256:e4c0df75810b953d6717b8f8f28298d73870e8aa2a0d5e77b8391f16fdfbbbe2",
+         "platform":{
+            "architecture":"arm64",
+            "os":"linux",
+            "variant":"v8"
+         }
+      }
+   ]
+}`)
+
+	manifest, _, err := UnMarshal(manifestlist.MediaTypeManifestList, b)
+	if err != nil {
+		t.Fatalf("failed to parse manifest list: %v", err)
+	}
+
+	refs := manifest.References()
+	if len(refs) != 2 {
+		t.Fatalf("unexpected length of reference: %d != %d", len(refs), 2)
+	}
+
+	digest := "sha256:e4c0df75810b953d6717b8f8f28298d73870e8aa2a0d5e77b8391f16fdfbbbe2"
+	if refs[1].Digest.String() != digest {
+		t.Errorf("unexpected digest: %s != %s", refs[1].Digest.String(), digest)
+	}
+}
+
+func TestIsManifestList(t *testing.T) {
+	cases := map[string]bool{
+		schema2.MediaTypeManifest:                  false,
+		schema1.MediaTypeSignedManifest:            false,
+		manifestlist.MediaTypeManifestList:         true,
+		v1.MediaTypeImageIndex:                     true,
+		v1.MediaTypeImageManifest:                  false,
+		v1.MediaTypeImageIndex + "; charset=utf-8": true,
+	}
+	for mediaType, expected := range cases {
+		if IsManifestList(mediaType) != expected {
+			t.Errorf("unexpected result for %s: %v != %v", mediaType, !expected, expected)
+		}
+		if !IsSupportedManifest(mediaType) {
+			t.Errorf("media type %s should be supported", mediaType)
+		}
+	}
+
+	if IsSupportedManifest("application/vnd.oci.image.config.v1+json") {
+		t.Error("image config should not be supported as manifest")
+	}
+}
+
+func TestUnMarshalOCIManifest(t *testing.T) {
+	b := []byte(`{
+   "schemaVersion":2,
+   "config":{
+      "mediaType":"application/vnd.oci.image.config.v1+json",
+      "size":1473,
+      "digest":"sha256:c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc"
+   },
+   "layers":[
+      {
+         "mediaType":"application/vnd.oci.image.layer.v1.tar+gzip",
+         "size":974,
+         "digest":"sha256:c04b14da8d1441880ed3fe6106fb2cc6fa1c9661846ac0266b8a5ec8edf37b7c"
+      }
+   ]
+}`)
+
+	manifest, _, err := UnMarshal(v1.MediaTypeImageManifest, b)
+	if err != nil {
+		t.Fatalf("failed to parse OCI manifest: %v", err)
+	}
+
+	refs := manifest.References()
+	if len(refs) != 2 {
+		t.Fatalf("unexpected length of reference: %d != %d", len(refs), 2)
+	}
+}
@@ -26,8 +26,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/docker/distribution/manifest/schema1"
-	"github.com/docker/distribution/manifest/schema2"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/utils"
 )
@@ -121,8 +119,11 @@ func (r *Repository) ManifestExist(reference string) (digest string, exist bool,
 		return
 	}
 
-	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema1.MediaTypeManifest)
-	req.Header.Add(http.CanonicalHeaderKey("Accept"), schema2.MediaTypeManifest)
+	// accept the manifest list as well, otherwise the registry resolves the
+	// reference of a multi-arch image to the digest of its default platform
+	for _, mediaType := range ManifestMediaTypes {
+		req.Header.Add(http.CanonicalHeaderKey("Accept"), mediaType)
+	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
@@ -281,6 +282,73 @@ func (r *Repository) MountBlob(digest, from string) error {
 	return nil
 }
 
+// TryMountBlob tries to mount the blob from the repository "from" and returns whether the blob is mounted.
+// The registry starts a regular upload session when it cannot mount the blob, e.g. the blob doesn't exist
+// in the repository "from" or the cross repository mount isn't supported, the session is cancelled here
+func (r *Repository) TryMountBlob(digest, from string) (bool, error) {
+	req, err := http.NewRequest("POST", buildMountBlobURL(r.Endpoint.String(), r.Name, digest, from), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(http.CanonicalHeaderKey("Content-Length"), "0")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, parseError(err)
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return true, nil
+	case http.StatusAccepted:
+		location := resp.Header.Get(http.CanonicalHeaderKey("Location"))
+		if len(location) > 0 {
+			// the session expires on the registry side if it fails to be cancelled
+			_ = r.cancelBlobUpload(location)
+		}
+		return false, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	return false, &commonhttp.Error{
+		Code:    resp.StatusCode,
+		Message: string(b),
+	}
+}
+
+func (r *Repository) cancelBlobUpload(location string) error {
+	relative, err := isRelativeURL(location)
+	if err != nil {
+		return err
+	}
+	if relative {
+		location = r.Endpoint.String() + location
+	}
+	req, err := http.NewRequest("DELETE", location, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return parseError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	return &commonhttp.Error{
+		Code: resp.StatusCode,
+	}
+}
+
 // DeleteTag ...
 func (r *Repository) DeleteTag(tag string) error {
 	digest, exist, err := r.ManifestExist(tag)
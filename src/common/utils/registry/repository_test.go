@@ -456,3 +456,51 @@ func TestMountBlob(t *testing.T) {
 		t.Fatalf("failed to mount blob: %v", err)
 	}
 }
+
+func TestTryMountBlob(t *testing.T) {
+	cancelled := false
+	status := http.StatusCreated
+	mountHandler := func(w http.ResponseWriter, r *http.Request) {
+		if status == http.StatusAccepted {
+			w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/uuid", repository))
+		}
+		w.WriteHeader(status)
+	}
+	cancelHandler := func(w http.ResponseWriter, r *http.Request) {
+		cancelled = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  "POST",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/", repository),
+			Handler: mountHandler,
+		},
+		&test.RequestHandlerMapping{
+			Method:  "DELETE",
+			Pattern: fmt.Sprintf("/v2/%s/blobs/uploads/uuid", repository),
+			Handler: cancelHandler,
+		})
+	defer server.Close()
+
+	client, err := newRepository(server.URL)
+	require.Nil(t, err)
+
+	// mounted
+	mounted, err := client.TryMountBlob(digest, "library/hi-world")
+	require.Nil(t, err)
+	assert.True(t, mounted)
+
+	// not mounted, the upload session is cancelled
+	status = http.StatusAccepted
+	mounted, err = client.TryMountBlob(digest, "library/hi-world")
+	require.Nil(t, err)
+	assert.False(t, mounted)
+	assert.True(t, cancelled)
+
+	// error
+	status = http.StatusUnauthorized
+	_, err = client.TryMountBlob(digest, "library/hi-world")
+	assert.NotNil(t, err)
+}
@@ -15,7 +15,10 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
@@ -155,6 +158,7 @@ func (aj *AJAPI) getSchedule(name string) {
 			return
 		}
 		adminJobSchedule.Schedule = adminJobRep.Schedule
+		adminJobSchedule.Parameters = adminJobRep.Parameters
 	}
 
 	aj.Data["json"] = adminJobSchedule
@@ -204,6 +208,13 @@ func (aj *AJAPI) getLog(id int64) {
 		jobID = job.UUID
 	}
 
+	if aj.IsFollowingLog() {
+		aj.FollowLog(func(ctx context.Context, offset int64, w io.Writer) error {
+			return utils_core.GetJobServiceClient().FollowJobLog(ctx, jobID, offset, w)
+		})
+		return
+	}
+
 	logBytes, err := utils_core.GetJobServiceClient().GetJobLog(jobID)
 	if err != nil {
 		if httpErr, ok := err.(*common_http.Error); ok {
@@ -284,10 +295,21 @@ func (aj *AJAPI) submit(ajr *models.AdminJobReq) {
 		}
 	}
 
+	var parameters string
+	if len(ajr.Parameters) > 0 {
+		data, err := json.Marshal(ajr.Parameters)
+		if err != nil {
+			aj.SendInternalServerError(fmt.Errorf("failed to marshal the parameters of admin job: %v", err))
+			return
+		}
+		parameters = string(data)
+	}
+
 	id, err := dao.AddAdminJob(&common_models.AdminJob{
-		Name: ajr.Name,
-		Kind: ajr.JobKind(),
-		Cron: ajr.CronString(),
+		Name:       ajr.Name,
+		Kind:       ajr.JobKind(),
+		Cron:       ajr.CronString(),
+		Parameters: parameters,
 	})
 	if err != nil {
 		aj.SendInternalServerError(err)
@@ -356,6 +378,12 @@ func convertToAdminJobRep(job *common_models.AdminJob) (models.AdminJobRep, erro
 		}
 		AdminJobRep.Schedule = &schedule
 	}
+
+	if len(job.Parameters) > 0 {
+		if err := json.Unmarshal([]byte(job.Parameters), &AdminJobRep.Parameters); err != nil {
+			return models.AdminJobRep{}, err
+		}
+	}
 	return AdminJobRep, nil
 }
 
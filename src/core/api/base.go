@@ -15,14 +15,17 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/goharbor/harbor/src/common/models"
+	"io"
 	"net/http"
 
 	"github.com/ghodss/yaml"
 	"github.com/goharbor/harbor/src/common/api"
+	common_http "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/common/utils"
@@ -39,6 +42,9 @@ import (
 const (
 	yamlFileContentType = "application/x-yaml"
 	userSessionKey      = "user"
+
+	logFollowParam = "follow"
+	logOffsetParam = "offset"
 )
 
 // the managers/controllers used globally
@@ -181,6 +187,42 @@ func (b *BaseController) WriteYamlData(object interface{}) {
 	_, _ = w.Write(yData)
 }
 
+// IsFollowingLog returns whether the log is requested to be followed by the query parameter "follow"
+func (b *BaseController) IsFollowingLog() bool {
+	follow, _ := b.GetBool(logFollowParam, false)
+	return follow
+}
+
+// FollowLog streams the log text to the client as the log grows until the job is done.
+// The log is read from the offset specified by the query parameter "offset", the follow
+// func returns errNotFound or a 404 http error if the log does not exist.
+func (b *BaseController) FollowLog(follow func(ctx context.Context, offset int64, w io.Writer) error) {
+	offset, err := b.GetInt64(logOffsetParam, 0)
+	if err != nil || offset < 0 {
+		b.SendBadRequestError(fmt.Errorf("invalid log offset: %s", b.GetString(logOffsetParam)))
+		return
+	}
+
+	w := b.Ctx.ResponseWriter
+	w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "text/plain")
+	// Disable the response buffering of the nginx proxy to deliver the log in time
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	if err := follow(b.Ctx.Request.Context(), offset, w); err != nil {
+		if w.Started {
+			// The status has been sent, nothing more can be told to the client
+			log.Errorf("failed to follow log: %v", err)
+			return
+		}
+
+		if httpErr, ok := err.(*common_http.Error); err == errNotFound || (ok && httpErr.Code == http.StatusNotFound) {
+			b.SendNotFoundError(errors.New("log not found"))
+			return
+		}
+		b.SendInternalServerError(fmt.Errorf("failed to follow log: %v", err))
+	}
+}
+
 // PopulateUserSession generates a new session ID and fill the user model in parm to the session
 func (b *BaseController) PopulateUserSession(u models.User) {
 	b.SessionRegenerateID()
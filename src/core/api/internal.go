@@ -120,7 +120,8 @@ func (ia *InternalAPI) ensureQuota() error {
 			continue
 		}
 		afQuery := &models.ArtifactQuery{
-			PID: project.ProjectID,
+			PID:    project.ProjectID,
+			Tagged: true,
 		}
 		afs, err := dao.ListArtifacts(afQuery)
 		if err != nil {
@@ -33,22 +33,26 @@ func (lra *LabelResourceAPI) getLabelsOfResource(rType string, rIDOrName interfa
 	lra.ServeJSON()
 }
 
-func (lra *LabelResourceAPI) markLabelToResource(rl *models.ResourceLabel) {
+// returns whether the label is marked to the resource
+func (lra *LabelResourceAPI) markLabelToResource(rl *models.ResourceLabel) bool {
 	labelID, err := lra.labelManager.MarkLabelToResource(rl)
 	if err != nil {
 		lra.handleErrors(err)
-		return
+		return false
 	}
 
 	// return the ID of label and return status code 200 rather than 201 as the label is not created
 	lra.Redirect(http.StatusOK, strconv.FormatInt(labelID, 10))
+	return true
 }
 
-func (lra *LabelResourceAPI) removeLabelFromResource(rType string, rIDOrName interface{}, labelID int64) {
+// returns whether the label is removed from the resource
+func (lra *LabelResourceAPI) removeLabelFromResource(rType string, rIDOrName interface{}, labelID int64) bool {
 	if err := lra.labelManager.RemoveLabelFromResource(rType, rIDOrName, labelID); err != nil {
 		lra.handleErrors(err)
-		return
+		return false
 	}
+	return true
 }
 
 // eat the error of validate method of label manager
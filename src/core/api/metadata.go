@@ -134,6 +134,11 @@ func (m *MetadataAPI) Post() {
 		return
 	}
 
+	if err := checkProxyCacheRegistryUnchanged(m.project, ms); err != nil {
+		m.SendBadRequestError(err)
+		return
+	}
+
 	if len(ms) != 1 {
 		m.SendBadRequestError(errors.New("invalid request: has no valid key/value pairs or has more than one valid key/value pairs"))
 		return
@@ -185,6 +190,11 @@ func (m *MetadataAPI) Put() {
 		return
 	}
 
+	if err := checkProxyCacheRegistryUnchanged(m.project, ms); err != nil {
+		m.SendBadRequestError(err)
+		return
+	}
+
 	if err := m.metaMgr.Update(m.project.ProjectID, map[string]string{
 		m.name: ms[m.name],
 	}); err != nil {
@@ -205,6 +215,20 @@ func (m *MetadataAPI) Delete() {
 	}
 }
 
+// checkProxyCacheRegistryUnchanged returns error if the metas change the upstream registry of the project,
+// a project can be set as the proxy cache only when it's created
+func checkProxyCacheRegistryUnchanged(project *models.Project, metas map[string]string) error {
+	value, exist := metas[models.ProMetaProxyCacheRegistryID]
+	if !exist {
+		return nil
+	}
+	current, _ := project.GetMetadata(models.ProMetaProxyCacheRegistryID)
+	if value != current {
+		return errors.New("the upstream registry of the proxy cache can only be set when creating the project")
+	}
+	return nil
+}
+
 // validate metas and return a new map which contains the valid key/value pairs only
 func validateProjectMetadata(metas map[string]string) (map[string]string, error) {
 	if len(metas) == 0 {
@@ -228,6 +252,24 @@ func validateProjectMetadata(metas map[string]string) (map[string]string, error)
 		}
 	}
 
+	intMetas := map[string]int64{
+		models.ProMetaProxyCacheRegistryID: 1,
+		models.ProMetaProxyCacheStaleness:  0,
+	}
+	for intMeta, min := range intMetas {
+		value, exist := metas[intMeta]
+		if exist {
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s to int: %v", value, err)
+			}
+			if i < min {
+				return nil, fmt.Errorf("invalid %s %d, it must not be less than %d", intMeta, i, min)
+			}
+			metas[intMeta] = strconv.FormatInt(i, 10)
+		}
+	}
+
 	value, exist := metas[models.ProMetaSeverity]
 	if exist {
 		severity := vuln.ParseSeverityVersion3(strings.ToLower(value))
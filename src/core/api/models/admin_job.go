@@ -48,15 +48,19 @@ const (
 // AdminJobReq holds request information for admin job
 type AdminJobReq struct {
 	AdminJobSchedule
-	Name       string                 `json:"name"`
-	Status     string                 `json:"status"`
-	ID         int64                  `json:"id"`
-	Parameters map[string]interface{} `json:"parameters"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+	// InternalParameters are passed to the job together with the parameters,
+	// but they are neither accepted from nor returned to the users
+	InternalParameters map[string]interface{} `json:"-"`
 }
 
 // AdminJobSchedule ...
 type AdminJobSchedule struct {
 	Schedule *ScheduleParam `json:"schedule"`
+	// Parameters are the options of the job provided by users, e.g. delete_untagged of GC
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
 }
 
 // ScheduleParam defines the parameter of schedule trigger
@@ -106,18 +110,23 @@ func (ar *AdminJobReq) ToJob() *models.JobData {
 		IsUnique: true,
 	}
 
+	parameters := make(models.Parameters)
+	for k, v := range ar.Parameters {
+		parameters[k] = v
+	}
+	for k, v := range ar.InternalParameters {
+		parameters[k] = v
+	}
+
 	jobData := &models.JobData{
 		Name:       ar.Name,
-		Parameters: ar.Parameters,
+		Parameters: parameters,
 		Metadata:   metadata,
 		StatusHook: fmt.Sprintf("%s/service/notifications/jobs/adminjob/%d",
 			config.InternalCoreURL(), ar.ID),
 	}
 
 	// Append admin job ID as job parameter
-	if jobData.Parameters == nil {
-		jobData.Parameters = make(models.Parameters)
-	}
 	// As string
 	jobData.Parameters["admin_job_id"] = fmt.Sprintf("%d", ar.ID)
 
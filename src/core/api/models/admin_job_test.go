@@ -77,6 +77,34 @@ func TestToJobManual(t *testing.T) {
 	assert.Equal(t, job.Metadata.JobKind, common_job.JobKindGeneric)
 }
 
+func TestToJobWithParameters(t *testing.T) {
+
+	adminJobSchedule := AdminJobSchedule{
+		Schedule: &ScheduleParam{
+			Type: "Manual",
+		},
+		Parameters: map[string]interface{}{
+			"delete_untagged": true,
+		},
+	}
+
+	adminjob := &AdminJobReq{
+		AdminJobSchedule: adminJobSchedule,
+		Name:             common_job.ImageGC,
+		ID:               1,
+		InternalParameters: map[string]interface{}{
+			"redis_url_reg": "redis://redis:6379/1",
+		},
+	}
+
+	job := adminjob.ToJob()
+	assert.Equal(t, true, job.Parameters["delete_untagged"])
+	assert.Equal(t, "redis://redis:6379/1", job.Parameters["redis_url_reg"])
+	assert.Equal(t, "1", job.Parameters["admin_job_id"])
+	// the internal parameters are not added into the parameters of the request
+	assert.NotContains(t, adminjob.Parameters, "redis_url_reg")
+}
+
 func TestIsPeriodic(t *testing.T) {
 
 	adminJobSchedule := AdminJobSchedule{
@@ -13,8 +13,12 @@ import (
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
 )
 
+// the secrets of the targets are hidden as the mask in the responses
+const secretMask = "*****"
+
 // NotificationPolicyAPI ...
 type NotificationPolicyAPI struct {
 	BaseController
@@ -86,6 +90,7 @@ func (w *NotificationPolicyAPI) Get() {
 		return
 	}
 
+	hideSecrets(policy)
 	w.WriteJSONData(policy)
 }
 
@@ -115,6 +120,14 @@ func (w *NotificationPolicyAPI) Post() {
 		return
 	}
 
+	// the mask can't be used as a secret as it can't be told from a hidden secret
+	for _, target := range policy.Targets {
+		if target.Secret == secretMask {
+			w.SendBadRequestError(fmt.Errorf("invalid secret of target %s with policy %s", target.Address, policy.Name))
+			return
+		}
+	}
+
 	policy.Creator = w.SecurityCtx.GetUsername()
 	policy.ProjectID = w.project.ProjectID
 
@@ -170,6 +183,7 @@ func (w *NotificationPolicyAPI) Put() {
 
 	policy.ID = id
 	policy.ProjectID = w.project.ProjectID
+	restoreSecrets(policy, oriPolicy)
 
 	if err = notification.PolicyMgr.Update(policy); err != nil {
 		w.SendInternalServerError(fmt.Errorf("failed to update the notification policy: %v", err))
@@ -193,6 +207,7 @@ func (w *NotificationPolicyAPI) List() {
 	policies := []*models.NotificationPolicy{}
 	if res != nil {
 		for _, policy := range res {
+			hideSecrets(policy)
 			policies = append(policies, policy)
 		}
 	}
@@ -274,6 +289,18 @@ func (w *NotificationPolicyAPI) Test() {
 		return
 	}
 
+	// test the existing policy with the hidden secrets
+	if policy.ID > 0 {
+		oriPolicy, err := notification.PolicyMgr.Get(policy.ID)
+		if err != nil {
+			w.SendInternalServerError(fmt.Errorf("failed to get the notification policy %d: %v", policy.ID, err))
+			return
+		}
+		if oriPolicy != nil && oriPolicy.ProjectID == projectID {
+			restoreSecrets(policy, oriPolicy)
+		}
+	}
+
 	if err := notification.PolicyMgr.Test(policy); err != nil {
 		log.Errorf("notification policy %s test failed: %v", policy.Name, err)
 		w.SendBadRequestError(fmt.Errorf("notification policy %s test failed", policy.Name))
@@ -309,6 +336,19 @@ func (w *NotificationPolicyAPI) validateTargets(policy *models.NotificationPolic
 			w.SendBadRequestError(fmt.Errorf("unsupport target type %s with policy %s", target.Type, policy.Name))
 			return false
 		}
+
+		switch target.PayloadFormat {
+		case "", notifyModel.PayloadFormatDefault:
+		case notifyModel.PayloadFormatCloudEvents:
+			if target.CloudEventsMode != "" && target.CloudEventsMode != notifyModel.CloudEventsModeStructured &&
+				target.CloudEventsMode != notifyModel.CloudEventsModeBinary {
+				w.SendBadRequestError(fmt.Errorf("unsupport CloudEvents mode %s with policy %s", target.CloudEventsMode, policy.Name))
+				return false
+			}
+		default:
+			w.SendBadRequestError(fmt.Errorf("unsupport payload format %s with policy %s", target.PayloadFormat, policy.Name))
+			return false
+		}
 	}
 
 	return true
@@ -374,3 +414,35 @@ func constructPolicyWithTriggerTime(policies []*models.NotificationPolicy) ([]*n
 	}
 	return res, nil
 }
+
+// hideSecrets hides the secrets of the targets in the policy returned to the clients
+func hideSecrets(policy *models.NotificationPolicy) {
+	for i := range policy.Targets {
+		if len(policy.Targets[i].Secret) > 0 {
+			policy.Targets[i].Secret = secretMask
+		}
+	}
+}
+
+// restoreSecrets restores the hidden secrets of the targets from the original policy by the target addresses,
+// the target at the same position in the original policy is used if the address is changed. The mask is never
+// kept as a secret
+func restoreSecrets(policy, oriPolicy *models.NotificationPolicy) {
+	for i, target := range policy.Targets {
+		if target.Secret != secretMask {
+			continue
+		}
+		policy.Targets[i].Secret = ""
+		restored := false
+		for _, oriTarget := range oriPolicy.Targets {
+			if oriTarget.Address == target.Address {
+				policy.Targets[i].Secret = oriTarget.Secret
+				restored = true
+				break
+			}
+		}
+		if !restored && i < len(oriPolicy.Targets) {
+			policy.Targets[i].Secret = oriPolicy.Targets[i].Secret
+		}
+	}
+}
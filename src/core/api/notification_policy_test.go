@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/goharbor/harbor/src/pkg/notification/model"
 
@@ -231,6 +232,25 @@ func TestNotificationPolicyAPI_Post(t *testing.T) {
 			},
 			code: http.StatusBadRequest,
 		},
+		// 400 the mask as the secret
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/projects/1/webhook/policies",
+				credential: sysAdmin,
+				bodyJSON: &models.NotificationPolicy{
+					EventTypes: []string{"pushImage"},
+					Targets: []models.EventTarget{
+						{
+							Type:    "http",
+							Address: "http://10.173.32.58:9009",
+							Secret:  secretMask,
+						},
+					},
+				},
+			},
+			code: http.StatusBadRequest,
+		},
 		// 201
 		{
 			request: &testingRequest{
@@ -635,3 +655,39 @@ func TestNotificationPolicyAPI_Delete(t *testing.T) {
 	}
 	runCodeCheckingCases(t, cases...)
 }
+
+func TestRestoreSecrets(t *testing.T) {
+	oriPolicy := &models.NotificationPolicy{
+		Targets: []models.EventTarget{
+			{Address: "http://127.0.0.1:8080", Secret: "secret1"},
+			{Address: "http://127.0.0.1:8081", Secret: "secret2"},
+		},
+	}
+	hidden := &models.NotificationPolicy{
+		Targets: []models.EventTarget{
+			{Address: "http://127.0.0.1:8080", Secret: "secret1"},
+			{Address: "http://127.0.0.1:8081"},
+		},
+	}
+	hideSecrets(hidden)
+	assert.Equal(t, secretMask, hidden.Targets[0].Secret)
+	assert.Equal(t, "", hidden.Targets[1].Secret)
+
+	policy := &models.NotificationPolicy{
+		Targets: []models.EventTarget{
+			// reordered
+			{Address: "http://127.0.0.1:8081", Secret: secretMask},
+			// address changed
+			{Address: "http://127.0.0.1:8082", Secret: secretMask},
+			// new secret
+			{Address: "http://127.0.0.1:8083", Secret: "secret3"},
+			// the mask without any original target
+			{Address: "http://127.0.0.1:8084", Secret: secretMask},
+		},
+	}
+	restoreSecrets(policy, oriPolicy)
+	assert.Equal(t, "secret2", policy.Targets[0].Secret)
+	assert.Equal(t, "secret2", policy.Targets[1].Secret)
+	assert.Equal(t, "secret3", policy.Targets[2].Secret)
+	assert.Equal(t, "", policy.Targets[3].Secret)
+}
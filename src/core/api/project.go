@@ -35,6 +35,7 @@ import (
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/pkg/types"
+	"github.com/goharbor/harbor/src/replication"
 	"github.com/pkg/errors"
 )
 
@@ -151,6 +152,11 @@ func (p *ProjectAPI) Post() {
 		}
 	}
 
+	if err := validateProxyCacheRegistry(pro.Metadata); err != nil {
+		p.SendBadRequestError(fmt.Errorf("invalid request: %v", err))
+		return
+	}
+
 	exist, err := p.ProjectMgr.Exists(pro.Name)
 	if err != nil {
 		p.ParseAndHandleError(fmt.Sprintf("failed to check the existence of project %s",
@@ -506,6 +512,11 @@ func (p *ProjectAPI) Put() {
 		return
 	}
 
+	if err := checkProxyCacheRegistryUnchanged(p.project, req.Metadata); err != nil {
+		p.SendBadRequestError(err)
+		return
+	}
+
 	if err := p.ProjectMgr.Update(p.project.ProjectID,
 		&models.Project{
 			Metadata:     req.Metadata,
@@ -641,6 +652,24 @@ func validateProjectReq(req *models.ProjectRequest) error {
 	return nil
 }
 
+// validateProxyCacheRegistry checks the existence of the upstream registry if the project is created as proxy cache
+func validateProxyCacheRegistry(metas map[string]string) error {
+	value, exist := metas[models.ProMetaProxyCacheRegistryID]
+	if !exist {
+		return nil
+	}
+	// the value is validated by validateProjectMetadata
+	id, _ := strconv.ParseInt(value, 10, 64)
+	registry, err := replication.RegistryMgr.Get(id)
+	if err != nil {
+		return fmt.Errorf("failed to get the registry %d: %v", id, err)
+	}
+	if registry == nil {
+		return fmt.Errorf("registry %d not found", id)
+	}
+	return nil
+}
+
 func projectQuotaHardLimits(req *models.ProjectRequest, setting *models.QuotaSetting) (types.ResourceList, error) {
 	hardLimits := types.ResourceList{}
 	if req.CountLimit != nil {
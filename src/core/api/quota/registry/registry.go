@@ -19,8 +19,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/docker/distribution/manifest/schema1"
-	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution"
 	"github.com/goharbor/harbor/src/common"
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
@@ -177,7 +176,12 @@ func (rm *Migrator) Usage(projects []quota.ProjectInfo) ([]quota.ProjectUsage, e
 
 		// usage count
 		for _, repo := range project.Repos {
-			count = count + int64(len(repo.Afs))
+			// the manifests pushed by digest are not counted
+			for _, af := range repo.Afs {
+				if af.Tag != "" {
+					count++
+				}
+			}
 			// Because that there are some shared blobs between repositories, it needs to remove the duplicate items.
 			for _, blob := range repo.Blobs {
 				_, exist := blobs[blob.Digest]
@@ -400,16 +404,14 @@ func infoOfRepo(pid int64, repo string) (quota.RepoData, error) {
 	if err != nil {
 		return quota.RepoData{}, err
 	}
-	var afnbs []*models.ArtifactAndBlob
-	var afs []*models.Artifact
-	var blobs []*models.Blob
+	data := &quota.RepoData{
+		Name: repo,
+	}
+	// the platform specific manifests already collected, they may be referenced by several manifest lists
+	children := map[string]struct{}{}
 
 	for _, tag := range tags {
-		_, mediaType, payload, err := repoClient.PullManifest(tag, []string{
-			schema1.MediaTypeManifest,
-			schema1.MediaTypeSignedManifest,
-			schema2.MediaTypeManifest,
-		})
+		_, mediaType, payload, err := repoClient.PullManifest(tag, registry.ManifestMediaTypes)
 		if err != nil {
 			log.Error(err)
 			// To workaround issue: https://github.com/goharbor/harbor/issues/9299, just log the error and do not raise it.
@@ -417,55 +419,85 @@ func infoOfRepo(pid int64, repo string) (quota.RepoData, error) {
 			// User still can view there images with size 0 in harbor.
 			continue
 		}
-		manifest, desc, err := registry.UnMarshal(mediaType, payload)
+		manifest, err := collectManifest(data, pid, repo, tag, mediaType, payload)
 		if err != nil {
 			log.Error(err)
 			return quota.RepoData{}, err
 		}
-		// self
-		afnb := &models.ArtifactAndBlob{
-			DigestAF:   desc.Digest.String(),
-			DigestBlob: desc.Digest.String(),
-		}
-		afnbs = append(afnbs, afnb)
-		// add manifest as a blob.
-		blob := &models.Blob{
-			Digest:       desc.Digest.String(),
-			ContentType:  desc.MediaType,
-			Size:         desc.Size,
-			CreationTime: time.Now(),
+		if !registry.IsManifestList(mediaType) {
+			continue
 		}
-		blobs = append(blobs, blob)
-		for _, layer := range manifest.References() {
-			afnb := &models.ArtifactAndBlob{
-				DigestAF:   desc.Digest.String(),
-				DigestBlob: layer.Digest.String(),
+
+		for _, ref := range manifest.References() {
+			if _, exist := children[ref.Digest.String()]; exist {
+				continue
+			}
+			children[ref.Digest.String()] = struct{}{}
+
+			_, childMediaType, childPayload, err := repoClient.PullManifest(ref.Digest.String(), registry.ImageManifestMediaTypes)
+			if err != nil {
+				log.Error(err)
+				continue
 			}
-			afnbs = append(afnbs, afnb)
-			blob := &models.Blob{
-				Digest:       layer.Digest.String(),
-				ContentType:  layer.MediaType,
-				Size:         layer.Size,
-				CreationTime: time.Now(),
+			// the platform specific manifest is pushed by digest, so it has no tag
+			if _, err := collectManifest(data, pid, repo, "", childMediaType, childPayload); err != nil {
+				log.Error(err)
+				return quota.RepoData{}, err
 			}
-			blobs = append(blobs, blob)
 		}
-		af := &models.Artifact{
-			PID:          pid,
-			Repo:         repo,
-			Tag:          tag,
-			Digest:       desc.Digest.String(),
-			Kind:         "Docker-Image",
+	}
+	return *data, nil
+}
+
+// collectManifest appends the artifact, the artifact&blob relationships and the blobs of the manifest into the repo data
+func collectManifest(data *quota.RepoData, pid int64, repo, tag, mediaType string, payload []byte) (distribution.Manifest, error) {
+	manifest, desc, err := registry.UnMarshal(mediaType, payload)
+	if err != nil {
+		return nil, err
+	}
+	// self
+	afnb := &models.ArtifactAndBlob{
+		DigestAF:   desc.Digest.String(),
+		DigestBlob: desc.Digest.String(),
+	}
+	data.Afnbs = append(data.Afnbs, afnb)
+	// add manifest as a blob.
+	blob := &models.Blob{
+		Digest:       desc.Digest.String(),
+		ContentType:  desc.MediaType,
+		Size:         desc.Size,
+		CreationTime: time.Now(),
+	}
+	data.Blobs = append(data.Blobs, blob)
+	// the references are the layers of the image, or the platform specific manifests of the manifest list
+	for _, layer := range manifest.References() {
+		afnb := &models.ArtifactAndBlob{
+			DigestAF:   desc.Digest.String(),
+			DigestBlob: layer.Digest.String(),
+		}
+		data.Afnbs = append(data.Afnbs, afnb)
+		blob := &models.Blob{
+			Digest:       layer.Digest.String(),
+			ContentType:  layer.MediaType,
+			Size:         layer.Size,
 			CreationTime: time.Now(),
 		}
-		afs = append(afs, af)
+		data.Blobs = append(data.Blobs, blob)
 	}
-	return quota.RepoData{
-		Name:  repo,
-		Afs:   afs,
-		Afnbs: afnbs,
-		Blobs: blobs,
-	}, nil
+	af := &models.Artifact{
+		PID:          pid,
+		Repo:         repo,
+		Tag:          tag,
+		Digest:       desc.Digest.String(),
+		Kind:         models.ArtifactKindImage,
+		CreationTime: time.Now(),
+	}
+	if registry.IsManifestList(mediaType) {
+		af.Kind = models.ArtifactKindImageIndex
+	}
+	data.Afs = append(data.Afs, af)
+
+	return manifest, nil
 }
 
 func init() {
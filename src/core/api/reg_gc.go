@@ -15,12 +15,16 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
 
+	"github.com/goharbor/harbor/src/common/dao"
 	common_job "github.com/goharbor/harbor/src/common/job"
+	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/core/api/models"
 )
 
@@ -56,6 +60,25 @@ func (gc *GCAPI) Prepare() {
 //    "type": "Manual"
 //  }
 //	}
+// create a manual trigger for online GC which doesn't set the system to read only
+// 	{
+//  "schedule": {
+//    "type": "Manual"
+//  },
+//  "parameters": {
+//    "online": true,
+//    "time_window": 2
+//  }
+//	}
+// create a manual trigger for GC dry run which only reports the reclaimable manifests and blobs
+// 	{
+//  "schedule": {
+//    "type": "Manual"
+//  },
+//  "parameters": {
+//    "dry_run": true
+//  }
+//	}
 func (gc *GCAPI) Post() {
 	ajr := models.AdminJobReq{}
 	isValid, err := gc.DecodeJSONReqAndValidate(&ajr)
@@ -64,7 +87,13 @@ func (gc *GCAPI) Post() {
 		return
 	}
 	ajr.Name = common_job.ImageGC
-	ajr.Parameters = map[string]interface{}{
+	params, err := gcParameters(ajr.Parameters)
+	if err != nil {
+		gc.SendBadRequestError(err)
+		return
+	}
+	ajr.Parameters = params
+	ajr.InternalParameters = map[string]interface{}{
 		"redis_url_reg": os.Getenv("_REDIS_URL_REG"),
 	}
 	gc.submit(&ajr)
@@ -72,6 +101,16 @@ func (gc *GCAPI) Post() {
 }
 
 // Put handles GC cron schedule update/delete.
+// Request: update the schedule of GC to delete the untagged manifests
+// 	{
+//  "schedule": {
+//    "type": "Daily",
+//    "cron": "0 0 0 * * *"
+//  },
+//  "parameters": {
+//    "delete_untagged": true
+//  }
+//	}
 // Request: delete the schedule of GC
 // 	{
 //  "schedule": {
@@ -87,7 +126,13 @@ func (gc *GCAPI) Put() {
 		return
 	}
 	ajr.Name = common_job.ImageGC
-	ajr.Parameters = map[string]interface{}{
+	params, err := gcParameters(ajr.Parameters)
+	if err != nil {
+		gc.SendBadRequestError(err)
+		return
+	}
+	ajr.Parameters = params
+	ajr.InternalParameters = map[string]interface{}{
 		"redis_url_reg": os.Getenv("_REDIS_URL_REG"),
 	}
 	gc.updateSchedule(ajr)
@@ -113,6 +158,38 @@ func (gc *GCAPI) Get() {
 	gc.getSchedule(common_job.ImageGC)
 }
 
+// GetReport returns the report of the GC execution, e.g. the reclaimable manifests and blobs of the dry run
+func (gc *GCAPI) GetReport() {
+	id, err := gc.GetInt64FromPath(":id")
+	if err != nil {
+		gc.SendBadRequestError(errors.New("invalid ID"))
+		return
+	}
+
+	job, err := dao.GetAdminJob(id)
+	if err != nil {
+		gc.SendInternalServerError(fmt.Errorf("failed to get admin job %d: %v", id, err))
+		return
+	}
+	if job == nil || job.Name != common_job.ImageGC {
+		gc.SendNotFoundError(fmt.Errorf("GC %d not found", id))
+		return
+	}
+	if len(job.Report) == 0 {
+		gc.SendNotFoundError(fmt.Errorf("no report found for GC %d", id))
+		return
+	}
+
+	report := &common_models.GCReport{}
+	if err := json.Unmarshal([]byte(job.Report), report); err != nil {
+		gc.SendInternalServerError(fmt.Errorf("failed to parse the report of GC %d: %v", id, err))
+		return
+	}
+
+	gc.Data["json"] = report
+	gc.ServeJSON()
+}
+
 // GetLog ...
 func (gc *GCAPI) GetLog() {
 	id, err := gc.GetInt64FromPath(":id")
@@ -122,3 +199,25 @@ func (gc *GCAPI) GetLog() {
 	}
 	gc.getLog(id)
 }
+
+// gcParameters returns the parameters of the GC job provided by users, the unknown parameters are dropped
+func gcParameters(reqParams map[string]interface{}) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+
+	for _, name := range []string{"online", "dry_run", "delete_untagged"} {
+		if v, ok := reqParams[name]; ok {
+			if _, ok := v.(bool); !ok {
+				return nil, fmt.Errorf("invalid parameter %s: %v", name, v)
+			}
+			params[name] = v
+		}
+	}
+	if v, ok := reqParams["time_window"]; ok {
+		if w, ok := v.(float64); !ok || w < 0 {
+			return nil, fmt.Errorf("invalid parameter time_window: %v", v)
+		}
+		params["time_window"] = v
+	}
+
+	return params, nil
+}
@@ -36,3 +36,31 @@ func TestGCGet(t *testing.T) {
 		assert.Equal(200, code, "Get adminjob status should be 200")
 	}
 }
+
+func TestGCParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	params, err := gcParameters(map[string]interface{}{
+		"online":          true,
+		"time_window":     float64(1),
+		"dry_run":         false,
+		"delete_untagged": true,
+		"unknown":         "ignored",
+	})
+	assert.Nil(err)
+	assert.Equal(true, params["online"])
+	assert.Equal(float64(1), params["time_window"])
+	assert.Equal(false, params["dry_run"])
+	assert.Equal(true, params["delete_untagged"])
+	assert.NotContains(params, "unknown")
+	assert.NotContains(params, "redis_url_reg")
+
+	_, err = gcParameters(map[string]interface{}{"online": "yes"})
+	assert.NotNil(err)
+
+	_, err = gcParameters(map[string]interface{}{"dry_run": 1})
+	assert.NotNil(err)
+
+	_, err = gcParameters(map[string]interface{}{"time_window": float64(-1)})
+	assert.NotNil(err)
+}
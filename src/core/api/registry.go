@@ -7,12 +7,15 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/goharbor/harbor/src/common/dao"
 	common_http "github.com/goharbor/harbor/src/common/http"
+	common_models "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/core/api/models"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/adapter/ocilayout"
 	"github.com/goharbor/harbor/src/replication/event"
 	"github.com/goharbor/harbor/src/replication/model"
 	"github.com/goharbor/harbor/src/replication/policy"
@@ -75,14 +78,23 @@ func (t *RegistryAPI) Ping() {
 		reg.Type = model.RegistryType(*req.Type)
 	}
 	if req.URL != nil {
-		url, err := utils.ParseEndpoint(*req.URL)
-		if err != nil {
-			t.SendBadRequestError(err)
-			return
-		}
+		if reg.Type == model.RegistryTypeOCILayout {
+			// the URL of the OCI layout is a local path, it must be under the root directory of the layouts
+			if err := ocilayout.ValidateURL(*req.URL); err != nil {
+				t.SendBadRequestError(err)
+				return
+			}
+			reg.URL = *req.URL
+		} else {
+			url, err := utils.ParseEndpoint(*req.URL)
+			if err != nil {
+				t.SendBadRequestError(err)
+				return
+			}
 
-		// Prevent SSRF security issue #3755
-		reg.URL = url.Scheme + "://" + url.Host + url.Path
+			// Prevent SSRF security issue #3755
+			reg.URL = url.Scheme + "://" + url.Host + url.Path
+		}
 	}
 	if req.CredentialType != nil {
 		if reg.Credential == nil {
@@ -210,7 +222,17 @@ func (t *RegistryAPI) Post() {
 	}
 	i := strings.Index(r.URL, "://")
 	if i == -1 {
-		r.URL = fmt.Sprintf("http://%s", r.URL)
+		if r.Type == model.RegistryTypeOCILayout {
+			r.URL = fmt.Sprintf("file://%s", r.URL)
+		} else {
+			r.URL = fmt.Sprintf("http://%s", r.URL)
+		}
+	}
+	if r.Type == model.RegistryTypeOCILayout {
+		if err := ocilayout.ValidateURL(r.URL); err != nil {
+			t.SendBadRequestError(err)
+			return
+		}
 	}
 
 	status, err := registry.CheckHealthStatus(r)
@@ -285,6 +307,13 @@ func (t *RegistryAPI) Put() {
 
 	t.Validate(r)
 
+	if r.Type == model.RegistryTypeOCILayout {
+		if err := ocilayout.ValidateURL(r.URL); err != nil {
+			t.SendBadRequestError(err)
+			return
+		}
+	}
+
 	if r.Name != originalName {
 		reg, err := t.manager.GetByName(r.Name)
 		if err != nil {
@@ -371,6 +400,19 @@ func (t *RegistryAPI) Delete() {
 		return
 	}
 
+	// Check whether there are proxy cache projects that use this registry as upstream registry.
+	metas, err := dao.ListProjectMetadata(common_models.ProMetaProxyCacheRegistryID, strconv.FormatInt(id, 10))
+	if err != nil {
+		t.SendInternalServerError(fmt.Errorf("List proxy cache projects with registry %d error: %v", id, err))
+		return
+	}
+	if len(metas) > 0 {
+		msg := fmt.Sprintf("Can't delete registry %d,  %d proxy cache projects use it as upstream registry", id, len(metas))
+		log.Error(msg)
+		t.SendPreconditionFailedError(errors.New(msg))
+		return
+	}
+
 	if err := t.manager.Remove(id); err != nil {
 		msg := fmt.Sprintf("Delete registry %d error: %v", id, err)
 		log.Error(msg)
@@ -128,6 +128,16 @@ func (suite *RegistrySuite) TestPing() {
 	assert.Nil(err)
 	assert.Equal(http.StatusBadRequest, code)
 
+	// the OCI layout out of the root directory of the layouts
+	ociLayout := string(model.RegistryTypeOCILayout)
+	url := "file:///etc/bundle"
+	code, err = suite.testAPI.RegistryPing(*admin, &pingReq{
+		Type: &ociLayout,
+		URL:  &url,
+	})
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, code)
+
 	code, err = suite.testAPI.RegistryPing(*testUser, &pingReq{
 		ID: &suite.defaultRegistry.ID,
 	})
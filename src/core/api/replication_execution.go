@@ -15,12 +15,15 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
+	utils_core "github.com/goharbor/harbor/src/core/utils"
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/dao/models"
 	"github.com/goharbor/harbor/src/replication/event"
@@ -199,6 +202,49 @@ func (r *ReplicationOperationAPI) StopExecution() {
 	}
 }
 
+// OperateExecution applies the action in the request body to the execution, the action
+// "retry" re-schedules the failed and stopped tasks of the finished execution
+func (r *ReplicationOperationAPI) OperateExecution() {
+	action := &struct {
+		Action string `json:"action" valid:"Required;Match(/^(stop|retry)$/)"`
+	}{}
+	isValid, err := r.DecodeJSONReqAndValidate(action)
+	if !isValid {
+		r.SendBadRequestError(err)
+		return
+	}
+	if action.Action == "stop" {
+		r.StopExecution()
+		return
+	}
+
+	if r.execution.Status == models.ExecutionStatusInProgress {
+		r.SendPreconditionFailedError(fmt.Errorf("the execution %d is in progress", r.execution.ID))
+		return
+	}
+	if r.execution.Failed == 0 && r.execution.Stopped == 0 {
+		r.SendPreconditionFailedError(fmt.Errorf("the execution %d has no failed or stopped tasks", r.execution.ID))
+		return
+	}
+	policy, err := replication.PolicyCtl.Get(r.execution.PolicyID)
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to get policy %d: %v", r.execution.PolicyID, err))
+		return
+	}
+	if policy == nil {
+		r.SendNotFoundError(fmt.Errorf("policy %d not found", r.execution.PolicyID))
+		return
+	}
+	if err = event.PopulateRegistries(replication.RegistryMgr, policy); err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to populate registries for policy %d: %v", r.execution.PolicyID, err))
+		return
+	}
+	if _, err = replication.OperationCtl.RetryReplication(policy, r.execution.ID); err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to retry the execution %d: %v", r.execution.ID, err))
+		return
+	}
+}
+
 // ListTasks ...
 func (r *ReplicationOperationAPI) ListTasks() {
 	query := &models.TaskQuery{
@@ -227,6 +273,13 @@ func (r *ReplicationOperationAPI) ListTasks() {
 
 // GetTaskLog ...
 func (r *ReplicationOperationAPI) GetTaskLog() {
+	if r.IsFollowingLog() {
+		r.FollowLog(func(ctx context.Context, offset int64, w io.Writer) error {
+			return utils_core.GetJobServiceClient().FollowJobLog(ctx, r.task.JobID, offset, w)
+		})
+		return
+	}
+
 	logBytes, err := replication.OperationCtl.GetTaskLog(r.task.ID)
 	if err != nil {
 		if httpErr, ok := err.(*common_http.Error); ok {
@@ -15,6 +15,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -31,6 +32,9 @@ func (f *fakedOperationController) StartReplication(policy *model.Policy, resour
 func (f *fakedOperationController) StopReplication(int64) error {
 	return nil
 }
+func (f *fakedOperationController) RetryReplication(policy *model.Policy, executionID int64) (int, error) {
+	return 1, nil
+}
 func (f *fakedOperationController) ListExecutions(...*models.ExecutionQuery) (int64, []*models.Execution, error) {
 	return 1, []*models.Execution{
 		{
@@ -46,6 +50,14 @@ func (f *fakedOperationController) GetExecution(id int64) (*models.Execution, er
 			PolicyID: 1,
 		}, nil
 	}
+	if id == 3 {
+		return &models.Execution{
+			ID:       3,
+			PolicyID: 1,
+			Status:   models.ExecutionStatusFailed,
+			Failed:   1,
+		}, nil
+	}
 	return nil, nil
 }
 func (f *fakedOperationController) ListTasks(...*models.TaskQuery) (int64, []*models.Task, error) {
@@ -77,6 +89,25 @@ func (f *fakedOperationController) UpdateTaskStatus(id int64, status string, sta
 func (f *fakedOperationController) GetTaskLog(int64) ([]byte, error) {
 	return []byte("success"), nil
 }
+func (f *fakedOperationController) PreviewReplication(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error) {
+	for _, filter := range policy.Filters {
+		if filter.Type != model.FilterTypeLabel && filter.Type != model.FilterTypeLabelExclude {
+			continue
+		}
+		if _, ok := filter.Value.([]string); !ok {
+			return 0, nil, fmt.Errorf("invalid value of label filter: %v", filter.Value)
+		}
+	}
+	return 1, []*model.PreviewItem{
+		{
+			Type:        model.ResourceTypeImage,
+			Source:      "library/hello-world:latest",
+			Destination: "library/hello-world:latest",
+			Size:        1000,
+			Status:      model.PreviewStatusNew,
+		},
+	}, nil
+}
 
 type fakedPolicyManager struct{}
 
@@ -328,6 +359,102 @@ func TestStopExecution(t *testing.T) {
 	runCodeCheckingCases(t, cases...)
 }
 
+func TestOperateExecution(t *testing.T) {
+	operationCtl := replication.OperationCtl
+	policyMgr := replication.PolicyCtl
+	registryMgr := replication.RegistryMgr
+	defer func() {
+		replication.OperationCtl = operationCtl
+		replication.PolicyCtl = policyMgr
+		replication.RegistryMgr = registryMgr
+	}()
+	replication.OperationCtl = &fakedOperationController{}
+	replication.PolicyCtl = &fakedPolicyManager{}
+	replication.RegistryMgr = &fakedRegistryManager{}
+
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPatch,
+				url:    "/api/replication/executions/1",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/1",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/2",
+				credential: sysAdmin,
+				bodyJSON: map[string]string{
+					"action": "retry",
+				},
+			},
+			code: http.StatusNotFound,
+		},
+		// 400, invalid action
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/1",
+				credential: sysAdmin,
+				bodyJSON: map[string]string{
+					"action": "pause",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 412, no failed or stopped tasks
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/1",
+				credential: sysAdmin,
+				bodyJSON: map[string]string{
+					"action": "retry",
+				},
+			},
+			code: http.StatusPreconditionFailed,
+		},
+		// 200, retry
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/3",
+				credential: sysAdmin,
+				bodyJSON: map[string]string{
+					"action": "retry",
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 200, stop
+		{
+			request: &testingRequest{
+				method:     http.MethodPatch,
+				url:        "/api/replication/executions/1",
+				credential: sysAdmin,
+				bodyJSON: map[string]string{
+					"action": "stop",
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
+
 func TestListTasks(t *testing.T) {
 	operationCtl := replication.OperationCtl
 	defer func() {
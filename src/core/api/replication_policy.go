@@ -22,6 +22,7 @@ import (
 
 	common_model "github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/dao/models"
 	"github.com/goharbor/harbor/src/replication/event"
 	"github.com/goharbor/harbor/src/replication/model"
@@ -94,6 +95,9 @@ func (r *ReplicationPolicyAPI) Create() {
 	if !r.validateRegistry(policy) {
 		return
 	}
+	if !r.validateFilters(policy) {
+		return
+	}
 
 	policy.Creator = r.SecurityCtx.GetUsername()
 	id, err := replication.PolicyCtl.Create(policy)
@@ -138,6 +142,58 @@ func (r *ReplicationPolicyAPI) validateRegistry(policy *model.Policy) bool {
 	return true
 }
 
+// make sure the filters are supported by the source registry, as they are
+// applied by the adapter of the source registry when fetching the resources
+func (r *ReplicationPolicyAPI) validateFilters(policy *model.Policy) bool {
+	if len(policy.Filters) == 0 {
+		return true
+	}
+	var registry *model.Registry
+	if policy.SrcRegistry != nil && policy.SrcRegistry.ID > 0 {
+		reg, err := replication.RegistryMgr.Get(policy.SrcRegistry.ID)
+		if err != nil {
+			r.SendInternalServerError(fmt.Errorf("failed to get registry %d: %v", policy.SrcRegistry.ID, err))
+			return false
+		}
+		if reg == nil {
+			r.SendBadRequestError(fmt.Errorf("registry %d not found", policy.SrcRegistry.ID))
+			return false
+		}
+		registry = reg
+	} else {
+		registry = event.GetLocalRegistry()
+	}
+	factory, err := adapter.GetFactory(registry.Type)
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to get the adapter factory for registry type %s: %v", registry.Type, err))
+		return false
+	}
+	adp, err := factory.Create(registry)
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to create the adapter for registry %d: %v", registry.ID, err))
+		return false
+	}
+	info, err := adp.Info()
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to get registry info %d: %v", registry.ID, err))
+		return false
+	}
+	// the resource filter is checked against the supported resource types
+	supported := map[model.FilterType]bool{
+		model.FilterTypeResource: true,
+	}
+	for _, filter := range info.SupportedResourceFilters {
+		supported[filter.Type] = true
+	}
+	for _, filter := range policy.Filters {
+		if !supported[filter.Type] {
+			r.SendBadRequestError(fmt.Errorf("the filter %s isn't supported by the source registry", filter.Type))
+			return false
+		}
+	}
+	return true
+}
+
 // Get the specified replication policy
 func (r *ReplicationPolicyAPI) Get() {
 	id, err := r.GetInt64FromPath(":id")
@@ -196,6 +252,9 @@ func (r *ReplicationPolicyAPI) Update() {
 	if !r.validateRegistry(policy) {
 		return
 	}
+	if !r.validateFilters(policy) {
+		return
+	}
 
 	policy.ID = id
 	if err := replication.PolicyCtl.Update(policy); err != nil {
@@ -239,6 +298,83 @@ func (r *ReplicationPolicyAPI) Delete() {
 	}
 }
 
+// Preview the resources that the saved replication policy would replicate
+func (r *ReplicationPolicyAPI) Preview() {
+	id, err := r.GetInt64FromPath(":id")
+	if id <= 0 || err != nil {
+		r.SendBadRequestError(errors.New("invalid policy ID"))
+		return
+	}
+
+	policy, err := replication.PolicyCtl.Get(id)
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to get the policy %d: %v", id, err))
+		return
+	}
+	if policy == nil {
+		r.SendNotFoundError(fmt.Errorf("policy %d not found", id))
+		return
+	}
+	r.preview(policy)
+}
+
+// PreviewUnsaved previews the resources that the replication policy in the request body
+// would replicate, the policy isn't saved
+func (r *ReplicationPolicyAPI) PreviewUnsaved() {
+	policy := &model.Policy{}
+	isValid, err := r.DecodeJSONReqAndValidate(policy)
+	if !isValid {
+		r.SendBadRequestError(err)
+		return
+	}
+	if !r.validateRegistry(policy) {
+		return
+	}
+	if !r.validateFilters(policy) {
+		return
+	}
+	if err = convertLabelFilters(policy); err != nil {
+		r.SendBadRequestError(err)
+		return
+	}
+	r.preview(policy)
+}
+
+func (r *ReplicationPolicyAPI) preview(policy *model.Policy) {
+	page, size, err := r.GetPaginationParams()
+	if err != nil {
+		r.SendBadRequestError(err)
+		return
+	}
+	if err := event.PopulateRegistries(replication.RegistryMgr, policy); err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to populate registries for policy %s: %v", policy.Name, err))
+		return
+	}
+	total, items, err := replication.OperationCtl.PreviewReplication(policy, page, size)
+	if err != nil {
+		r.SendInternalServerError(fmt.Errorf("failed to preview the replication of policy %s: %v", policy.Name, err))
+		return
+	}
+	r.SetPaginationHeader(total, page, size)
+	r.WriteJSONData(items)
+}
+
+// the values of the label filters in the request body are decoded as []interface{},
+// convert them to []string as the ones of the policies loaded from the database
+func convertLabelFilters(policy *model.Policy) error {
+	for _, filter := range policy.Filters {
+		if filter.Type != model.FilterTypeLabel && filter.Type != model.FilterTypeLabelExclude {
+			continue
+		}
+		labels, err := model.LabelsOf(filter.Value)
+		if err != nil {
+			return err
+		}
+		filter.Value = labels
+	}
+	return nil
+}
+
 func hasRunningExecutions(policyID int64) (bool, error) {
 	_, executions, err := replication.OperationCtl.ListExecutions(&models.ExecutionQuery{
 		PolicyID: policyID,
@@ -19,7 +19,10 @@ import (
 	"testing"
 
 	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO rename the file to "replication.go"
@@ -53,6 +56,53 @@ func (f *fakedRegistryManager) HealthCheck() error {
 	return nil
 }
 
+type fakedRegistryAdapterFactory struct{}
+
+func (fakedRegistryAdapterFactory) Create(*model.Registry) (adapter.Adapter, error) {
+	return &fakedRegistryAdapter{}, nil
+}
+
+func (fakedRegistryAdapterFactory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+type fakedRegistryAdapter struct{}
+
+func (f *fakedRegistryAdapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: "faked_registry",
+		SupportedResourceTypes: []model.ResourceType{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeLabel,
+				Style: model.FilterStyleTypeList,
+			},
+			{
+				Type:  model.FilterTypeLabelExclude,
+				Style: model.FilterStyleTypeList,
+			},
+		},
+	}, nil
+}
+func (f *fakedRegistryAdapter) PrepareForPush([]*model.Resource) error {
+	return nil
+}
+func (f *fakedRegistryAdapter) HealthCheck() (model.HealthStatus, error) {
+	return model.Healthy, nil
+}
+
+func init() {
+	if err := adapter.RegisterFactory("faked_registry", new(fakedRegistryAdapterFactory)); err != nil {
+		panic(err)
+	}
+}
+
 func TestReplicationPolicyAPIList(t *testing.T) {
 	policyMgr := replication.PolicyCtl
 	defer func() {
@@ -174,6 +224,27 @@ func TestReplicationPolicyAPICreate(t *testing.T) {
 			},
 			code: http.StatusBadRequest,
 		},
+		// 400, the filter isn't supported by the source registry
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies",
+				credential: sysAdmin,
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+					SrcRegistry: &model.Registry{
+						ID: 1,
+					},
+					Filters: []*model.Filter{
+						{
+							Type:  model.FilterTypeTagRegex,
+							Value: "^v1",
+						},
+					},
+				},
+			},
+			code: http.StatusBadRequest,
+		},
 		// 201
 		{
 			request: &testingRequest{
@@ -185,6 +256,12 @@ func TestReplicationPolicyAPICreate(t *testing.T) {
 					SrcRegistry: &model.Registry{
 						ID: 1,
 					},
+					Filters: []*model.Filter{
+						{
+							Type:  model.FilterTypeName,
+							Value: "library/*",
+						},
+					},
 				},
 			},
 			code: http.StatusCreated,
@@ -340,11 +417,77 @@ func TestReplicationPolicyAPIUpdate(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		// 200, the unsaved policy with label filters
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/preview",
+				credential: sysAdmin,
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+					SrcRegistry: &model.Registry{
+						ID: 1,
+					},
+					Filters: []*model.Filter{
+						{
+							Type:  model.FilterTypeLabel,
+							Value: []string{"prod"},
+						},
+						{
+							Type:  model.FilterTypeLabelExclude,
+							Value: []string{"deprecated"},
+						},
+					},
+				},
+			},
+			code: http.StatusOK,
+		},
+		// 400, invalid page size
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/preview",
+				credential: sysAdmin,
+				queryStruct: struct {
+					PageSize int64 `url:"page_size"`
+				}{
+					PageSize: -1,
+				},
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+					SrcRegistry: &model.Registry{
+						ID: 1,
+					},
+				},
+			},
+			code: http.StatusBadRequest,
+		},
 	}
 
 	runCodeCheckingCases(t, cases...)
 }
 
+func TestConvertLabelFilters(t *testing.T) {
+	policy := &model.Policy{
+		Filters: []*model.Filter{
+			{
+				Type:  model.FilterTypeName,
+				Value: "library/*",
+			},
+			{
+				Type:  model.FilterTypeLabel,
+				Value: []interface{}{"prod", "stable"},
+			},
+		},
+	}
+	require.Nil(t, convertLabelFilters(policy))
+	assert.Equal(t, "library/*", policy.Filters[0].Value)
+	assert.Equal(t, []string{"prod", "stable"}, policy.Filters[1].Value)
+
+	policy.Filters[1].Value = []interface{}{1}
+	assert.NotNil(t, convertLabelFilters(policy))
+}
+
 func TestReplicationPolicyAPIDelete(t *testing.T) {
 	policyMgr := replication.PolicyCtl
 	defer func() {
@@ -391,3 +534,98 @@ func TestReplicationPolicyAPIDelete(t *testing.T) {
 
 	runCodeCheckingCases(t, cases...)
 }
+
+func TestReplicationPolicyAPIPreview(t *testing.T) {
+	policyMgr := replication.PolicyCtl
+	registryMgr := replication.RegistryMgr
+	operationCtl := replication.OperationCtl
+	defer func() {
+		replication.PolicyCtl = policyMgr
+		replication.RegistryMgr = registryMgr
+		replication.OperationCtl = operationCtl
+	}()
+	replication.PolicyCtl = &fakedPolicyManager{}
+	replication.RegistryMgr = &fakedRegistryManager{}
+	replication.OperationCtl = &fakedOperationController{}
+	cases := []*codeCheckingCase{
+		// 401
+		{
+			request: &testingRequest{
+				method: http.MethodPost,
+				url:    "/api/replication/policies/1/preview",
+			},
+			code: http.StatusUnauthorized,
+		},
+		// 403
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/1/preview",
+				credential: nonSysAdmin,
+			},
+			code: http.StatusForbidden,
+		},
+		// 404, policy not found
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/3/preview",
+				credential: sysAdmin,
+			},
+			code: http.StatusNotFound,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/1/preview",
+				credential: sysAdmin,
+			},
+			code: http.StatusOK,
+		},
+		// 400, empty registry of the unsaved policy
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/preview",
+				credential: sysAdmin,
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 400, registry of the unsaved policy not found
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/preview",
+				credential: sysAdmin,
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+					SrcRegistry: &model.Registry{
+						ID: 2,
+					},
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200, the unsaved policy
+		{
+			request: &testingRequest{
+				method:     http.MethodPost,
+				url:        "/api/replication/policies/preview",
+				credential: sysAdmin,
+				bodyJSON: &model.Policy{
+					Name: "policy01",
+					SrcRegistry: &model.Registry{
+						ID: 1,
+					},
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	runCodeCheckingCases(t, cases...)
+}
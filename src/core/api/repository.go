@@ -25,6 +25,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common"
@@ -48,6 +51,7 @@ import (
 	"github.com/goharbor/harbor/src/replication"
 	"github.com/goharbor/harbor/src/replication/event"
 	"github.com/goharbor/harbor/src/replication/model"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // RepositoryAPI handles request to /api/repositories /api/repositories/tags /api/repositories/manifests, the parm has to be put
@@ -289,6 +293,12 @@ func (ra *RepositoryAPI) Delete() {
 
 	for _, t := range tags {
 		image := fmt.Sprintf("%s:%s", repoName, t)
+		// the labels are carried by the deletion event for the label filters of replication policies
+		labels, err := getImageLabelNames(repoName, t)
+		if err != nil {
+			ra.SendInternalServerError(fmt.Errorf("failed to get labels of image %s: %v", image, err))
+			return
+		}
 		if err = dao.DeleteLabelsOfResource(common.ResourceTypeImage, image); err != nil {
 			ra.SendInternalServerError(fmt.Errorf("failed to delete labels of image %s: %v", image, err))
 			return
@@ -311,7 +321,7 @@ func (ra *RepositoryAPI) Delete() {
 		}
 		log.Infof("delete tag: %s:%s", repoName, t)
 
-		go func(tag string) {
+		go func(tag, digest string, labels []string) {
 			e := &event.Event{
 				Type: event.EventTypeImageDelete,
 				Resource: &model.Resource{
@@ -320,7 +330,9 @@ func (ra *RepositoryAPI) Delete() {
 						Repository: &model.Repository{
 							Name: repoName,
 						},
-						Vtags: []string{tag},
+						Vtags:  []string{tag},
+						Labels: labels,
+						Digest: digest,
 					},
 					Deleted: true,
 				},
@@ -328,7 +340,7 @@ func (ra *RepositoryAPI) Delete() {
 			if err := replication.EventHandler.Handle(e); err != nil {
 				log.Errorf("failed to handle event: %v", err)
 			}
-		}(t)
+		}(t, digests[t], labels)
 
 		go func(tag string) {
 			if err := dao.AddAccessLog(models.AccessLog{
@@ -738,18 +750,91 @@ func getTagDetail(client *registry.Repository, tag string) (*models.TagDetail, e
 		Name: tag,
 	}
 
-	digest, mediaType, payload, err := client.PullManifest(tag, []string{schema2.MediaTypeManifest})
+	digest, mediaType, payload, err := client.PullManifest(tag, []string{
+		schema2.MediaTypeManifest,
+		ocispec.MediaTypeImageManifest,
+		manifestlist.MediaTypeManifestList,
+		ocispec.MediaTypeImageIndex,
+	})
 	if err != nil {
 		return detail, err
 	}
 	detail.Digest = digest
 
+	if registry.IsManifestList(mediaType) {
+		return detail, populateManifestListDetail(client, detail, mediaType, payload)
+	}
+
+	_, err = populateManifestDetail(client, detail, mediaType, payload)
+	return detail, err
+}
+
+// populateManifestListDetail populates the detail of the manifest list with the details of the
+// platform specific manifests it references, the size counts the shared layers only once
+func populateManifestListDetail(client *registry.Repository, detail *models.TagDetail, mediaType string, payload []byte) error {
+	manifest, _, err := registry.UnMarshal(mediaType, payload)
+	if err != nil {
+		return err
+	}
+	list, ok := manifest.(*manifestlist.DeserializedManifestList)
+	if !ok {
+		return fmt.Errorf("the manifest of %s cannot be converted to manifest list", detail.Name)
+	}
+
+	detail.Size = int64(len(payload))
+	sized := map[string]struct{}{}
+	for _, m := range list.Manifests {
+		_, childMediaType, childPayload, err := client.PullManifest(m.Digest.String(), registry.ImageManifestMediaTypes)
+		if err != nil {
+			return err
+		}
+		child := &models.TagDetail{
+			Name:   detail.Name,
+			Digest: m.Digest.String(),
+		}
+		childManifest, err := populateManifestDetail(client, child, childMediaType, childPayload)
+		if err != nil {
+			return err
+		}
+
+		detail.Size += int64(len(childPayload))
+		for _, ref := range childManifest.References() {
+			if _, exist := sized[ref.Digest.String()]; exist {
+				continue
+			}
+			sized[ref.Digest.String()] = struct{}{}
+			detail.Size += ref.Size
+		}
+
+		detail.Manifests = append(detail.Manifests, &models.PlatformManifest{
+			Digest:       m.Digest.String(),
+			Size:         child.Size,
+			Architecture: m.Platform.Architecture,
+			OS:           m.Platform.OS,
+			OSVersion:    m.Platform.OSVersion,
+			Variant:      m.Platform.Variant,
+		})
+
+		// the build information of the manifest list is the one of the latest built platform
+		if child.Created.After(detail.Created) {
+			detail.Created = child.Created
+			detail.Author = child.Author
+			detail.DockerVersion = child.DockerVersion
+			detail.Config = child.Config
+		}
+	}
+
+	return nil
+}
+
+// populateManifestDetail populates the detail of the image manifest and returns the parsed manifest
+func populateManifestDetail(client *registry.Repository, detail *models.TagDetail, mediaType string, payload []byte) (distribution.Manifest, error) {
 	if strings.Contains(mediaType, "application/json") {
 		mediaType = schema1.MediaTypeManifest
 	}
 	manifest, _, err := registry.UnMarshal(mediaType, payload)
 	if err != nil {
-		return detail, err
+		return nil, err
 	}
 
 	// size of manifest + size of layers
@@ -758,37 +843,38 @@ func getTagDetail(client *registry.Repository, tag string) (*models.TagDetail, e
 		detail.Size += ref.Size
 	}
 
-	// if the media type of the manifest isn't v2, doesn't parse image config
+	// if the media type of the manifest isn't v2 or OCI, doesn't parse image config
 	// and return directly
 	// this impacts that some detail information(os, arch, ...) of old images
 	// cannot be got
-	if mediaType != schema2.MediaTypeManifest {
-		log.Debugf("the media type of the manifest is %s, not v2, skip", mediaType)
-		return detail, nil
-	}
-	v2Manifest, ok := manifest.(*schema2.DeserializedManifest)
-	if !ok {
-		log.Debug("the manifest cannot be convert to DeserializedManifest, skip")
-		return detail, nil
-	}
-
-	_, reader, err := client.PullBlob(v2Manifest.Target().Digest.String())
+	var config distribution.Descriptor
+	switch m := manifest.(type) {
+	case *schema2.DeserializedManifest:
+		config = m.Target()
+	case *ocischema.DeserializedManifest:
+		config = m.Target()
+	default:
+		log.Debugf("the media type of the manifest is %s, not v2 or OCI, skip", mediaType)
+		return manifest, nil
+	}
+
+	_, reader, err := client.PullBlob(config.Digest.String())
 	if err != nil {
-		return detail, err
+		return manifest, err
 	}
 
 	configData, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return detail, err
+		return manifest, err
 	}
 
 	if err = json.Unmarshal(configData, detail); err != nil {
-		return detail, err
+		return manifest, err
 	}
 
 	populateAuthor(detail)
 
-	return detail, nil
+	return manifest, nil
 }
 
 func populateAuthor(detail *models.TagDetail) {
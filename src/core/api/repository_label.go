@@ -23,7 +23,11 @@ import (
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/utils/log"
 	coreutils "github.com/goharbor/harbor/src/core/utils"
+	"github.com/goharbor/harbor/src/replication"
+	"github.com/goharbor/harbor/src/replication/event"
+	"github.com/goharbor/harbor/src/replication/model"
 )
 
 // RepositoryLabelAPI handles requests for adding/removing label to/from repositories and images
@@ -137,7 +141,9 @@ func (r *RepositoryLabelAPI) AddToImage() {
 		ResourceType: common.ResourceTypeImage,
 		ResourceName: fmt.Sprintf("%s:%s", r.repository.Name, r.tag),
 	}
-	r.markLabelToResource(rl)
+	if r.markLabelToResource(rl) {
+		go handleImageLabelEvent(event.EventTypeImageLabelAdd, r.SecurityCtx.GetUsername(), r.repository.Name, r.tag, r.label.Name)
+	}
 }
 
 // RemoveFromImage removes the label from an image
@@ -146,8 +152,10 @@ func (r *RepositoryLabelAPI) RemoveFromImage() {
 		return
 	}
 
-	r.removeLabelFromResource(common.ResourceTypeImage,
-		fmt.Sprintf("%s:%s", r.repository.Name, r.tag), r.label.ID)
+	if r.removeLabelFromResource(common.ResourceTypeImage,
+		fmt.Sprintf("%s:%s", r.repository.Name, r.tag), r.label.ID) {
+		go handleImageLabelEvent(event.EventTypeImageLabelRemove, r.SecurityCtx.GetUsername(), r.repository.Name, r.tag, r.label.Name)
+	}
 }
 
 // GetOfRepository returns labels of a repository
@@ -182,6 +190,63 @@ func (r *RepositoryLabelAPI) RemoveFromRepository() {
 	r.removeLabelFromResource(common.ResourceTypeRepository, r.repository.RepositoryID, r.label.ID)
 }
 
+// handleImageLabelEvent triggers the replication policies which have label filters
+// after the label is added to or removed from the image
+func handleImageLabelEvent(eventType, username, repository, tag, label string) {
+	labels, err := getImageLabelNames(repository, tag)
+	if err != nil {
+		log.Errorf("failed to get the labels of image %s:%s: %v", repository, tag, err)
+		return
+	}
+	// the digest is carried for the digest filters of replication policies
+	digest, err := imageDigest(username, repository, tag)
+	if err != nil {
+		log.Errorf("failed to get the digest of image %s:%s: %v", repository, tag, err)
+		return
+	}
+	e := &event.Event{
+		Type: eventType,
+		Resource: &model.Resource{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: repository,
+				},
+				Vtags:  []string{tag},
+				Labels: labels,
+				Digest: digest,
+			},
+		},
+		Label: label,
+	}
+	if err := replication.EventHandler.Handle(e); err != nil {
+		log.Errorf("failed to handle event: %v", err)
+	}
+}
+
+// getImageLabelNames returns the names of the labels that the image has
+func getImageLabelNames(repository, tag string) ([]string, error) {
+	labels, err := dao.GetLabelsOfResource(common.ResourceTypeImage, fmt.Sprintf("%s:%s", repository, tag))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names, nil
+}
+
+func imageDigest(username, repository, tag string) (string, error) {
+	client, err := coreutils.NewRepositoryClientForUI(username, repository)
+	if err != nil {
+		return "", err
+	}
+
+	digest, _, err := client.ManifestExist(tag)
+	return digest, err
+}
+
 func imageExist(username, repository, tag string) (bool, error) {
 	client, err := coreutils.NewRepositoryClientForUI(username, repository)
 	if err != nil {
@@ -15,6 +15,9 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -161,6 +164,49 @@ func (sa *ScanAPI) Report() {
 	sa.ServeJSON()
 }
 
+// Import the reports generated somewhere else, e.g. the reports replicated from a
+// remote Harbor. The request body is the same as the response of getting reports:
+// the reports are indexed by their mime types.
+func (sa *ScanAPI) Import() {
+	// Check access permissions
+	if !sa.RequireProjectAccess(sa.pro.ProjectID, rbac.ActionCreate, rbac.ResourceScan) {
+		return
+	}
+
+	reports := make(map[string]json.RawMessage)
+	if err := sa.DecodeJSONReq(&reports); err != nil {
+		sa.SendBadRequestError(errors.Wrap(err, "scan API: import"))
+		return
+	}
+
+	for mimeType, rp := range reports {
+		// Make sure the report data can be resolved
+		if _, err := report.ResolveData(mimeType, rp); err != nil {
+			sa.SendBadRequestError(errors.Wrapf(err, "scan API: import report with mime type %s", mimeType))
+			return
+		}
+	}
+
+	for mimeType, rp := range reports {
+		if err := scan.DefaultController.ImportReport(sa.artifact, mimeType, string(rp)); err != nil {
+			e := errors.Wrap(err, "scan API: import")
+
+			if errs.AsError(err, errs.PreconditionFailed) {
+				sa.SendPreconditionFailedError(e)
+				return
+			}
+
+			if errs.AsError(err, errs.Conflict) {
+				sa.SendConflictError(e)
+				return
+			}
+
+			sa.SendInternalServerError(e)
+			return
+		}
+	}
+}
+
 // Log returns the log stream
 func (sa *ScanAPI) Log() {
 	// Check access permissions
@@ -169,6 +215,17 @@ func (sa *ScanAPI) Log() {
 	}
 
 	uuid := sa.GetString(":uuid")
+	if sa.IsFollowingLog() {
+		sa.FollowLog(func(ctx context.Context, offset int64, w io.Writer) error {
+			found, err := scan.DefaultController.FollowScanLog(ctx, uuid, offset, w)
+			if err == nil && !found {
+				return errNotFound
+			}
+			return err
+		})
+		return
+	}
+
 	bytes, err := scan.DefaultController.GetScanLog(uuid)
 	if err != nil {
 		sa.SendInternalServerError(errors.Wrap(err, "scan API: log"))
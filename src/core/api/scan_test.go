@@ -15,7 +15,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
@@ -145,6 +147,56 @@ func (suite *ScanAPITestSuite) TestScanAPIReport() {
 	require.NoError(suite.T(), err)
 }
 
+// TestScanAPIImport ...
+func (suite *ScanAPITestSuite) TestScanAPIImport() {
+	suite.c.On("ImportReport", suite.artifact, v1.MimeTypeNativeReport, `{"severity":"High"}`).Return(nil)
+
+	cases := []*codeCheckingCase{
+		// 403
+		{
+			request: &testingRequest{
+				url:        scanBaseURL,
+				method:     http.MethodPut,
+				credential: projGuest,
+				bodyJSON: map[string]interface{}{
+					v1.MimeTypeNativeReport: map[string]interface{}{
+						"severity": "High",
+					},
+				},
+			},
+			code: http.StatusForbidden,
+		},
+		// 400
+		{
+			request: &testingRequest{
+				url:        scanBaseURL,
+				method:     http.MethodPut,
+				credential: projAdmin,
+				bodyJSON: map[string]interface{}{
+					v1.MimeTypeNativeReport: "invalid",
+				},
+			},
+			code: http.StatusBadRequest,
+		},
+		// 200
+		{
+			request: &testingRequest{
+				url:        scanBaseURL,
+				method:     http.MethodPut,
+				credential: projAdmin,
+				bodyJSON: map[string]interface{}{
+					v1.MimeTypeNativeReport: map[string]interface{}{
+						"severity": "High",
+					},
+				},
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	runCodeCheckingCases(suite.T(), cases...)
+}
+
 // TestScanAPILog ...
 func (suite *ScanAPITestSuite) TestScanAPILog() {
 	suite.c.On("GetScanLog", "the-uuid-001").Return([]byte(`{"log": "this is my log"}`), nil)
@@ -207,6 +259,17 @@ func (msc *MockScanAPIController) GetScanLog(uuid string) ([]byte, error) {
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (msc *MockScanAPIController) FollowScanLog(ctx context.Context, uuid string, offset int64, w io.Writer) (bool, error) {
+	args := msc.Called(uuid, offset)
+	if args.Get(0) != nil {
+		if _, err := w.Write(args.Get(0).([]byte)); err != nil {
+			return false, err
+		}
+	}
+
+	return args.Bool(1), args.Error(2)
+}
+
 func (msc *MockScanAPIController) HandleJobHooks(trackID string, change *job.StatusChange) error {
 	args := msc.Called(trackID, change)
 
@@ -217,6 +280,12 @@ func (msc *MockScanAPIController) DeleteReports(digests ...string) error {
 	return nil
 }
 
+func (msc *MockScanAPIController) ImportReport(artifact *v1.Artifact, mimeType string, rawReport string) error {
+	args := msc.Called(artifact, mimeType, rawReport)
+
+	return args.Error(0)
+}
+
 func (msc *MockScanAPIController) GetStats(requester string) (*all.Stats, error) {
 	args := msc.Called(requester)
 
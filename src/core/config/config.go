@@ -464,6 +464,11 @@ func GetRegistryCtlURL() string {
 	return url
 }
 
+// MetricAddr returns the listener address of the metrics, the metrics aren't served if it's empty
+func MetricAddr() string {
+	return os.Getenv("METRIC_ADDR")
+}
+
 // HTTPAuthProxySetting returns the setting of HTTP Auth proxy.  the settings are only meaningful when the auth_mode is
 // set to http_auth
 func HTTPAuthProxySetting() (*models.HTTPAuthProxy, error) {
@@ -0,0 +1,27 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	beegoctx "github.com/astaxie/beego/context"
+	"github.com/goharbor/harbor/src/pkg/metrics"
+)
+
+// MetricsFilter sets the route pattern that the request matches as the route of the metrics
+func MetricsFilter(ctx *beegoctx.Context) {
+	if pattern, ok := ctx.Input.GetData("RouterPattern").(string); ok {
+		metrics.SetRoute(ctx.Request, pattern)
+	}
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"encoding/gob"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -45,7 +46,9 @@ import (
 	"github.com/goharbor/harbor/src/core/middlewares"
 	_ "github.com/goharbor/harbor/src/core/notifier/topic"
 	"github.com/goharbor/harbor/src/core/service/token"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/proxy"
 	"github.com/goharbor/harbor/src/pkg/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
 	"github.com/goharbor/harbor/src/pkg/scan/event"
@@ -241,6 +244,7 @@ func main() {
 	if err := replication.Init(closing, done); err != nil {
 		log.Fatalf("failed to init for replication: %v", err)
 	}
+	proxy.DefaultController = proxy.NewController(replication.RegistryMgr)
 
 	log.Info("initializing notification...")
 	notification.Init()
@@ -251,6 +255,7 @@ func main() {
 	beego.InsertFilter("/api/*", beego.BeforeStatic, filter.SessionCheck)
 	beego.InsertFilter("/*", beego.BeforeRouter, filter.SecurityFilter)
 	beego.InsertFilter("/*", beego.BeforeRouter, filter.ReadonlyFilter)
+	beego.InsertFilter("/*", beego.BeforeExec, filter.MetricsFilter)
 
 	initRouters()
 
@@ -288,7 +293,11 @@ func main() {
 		log.Infof("Because SYNC_QUOTA set false , no need to sync quota \n")
 	}
 
+	metrics.Serve(config.MetricAddr(), common_quota.NewCollector())
+
 	log.Infof("Version: %s, Git commit: %s", version.ReleaseVersion, version.GitCommit)
-	beego.Run()
+	beego.RunWithMiddleWares("", func(handler http.Handler) http.Handler {
+		return metrics.Instrument("core", handler)
+	})
 
 }
@@ -27,13 +27,15 @@ const (
 	COUNTQUOTA       = "countquota"
 	IMMUTABLE        = "immutable"
 	REGTOKEN         = "regtoken"
+	PROXYCACHE       = "proxycache"
+	DELETINGGUARD    = "deletingguard"
 )
 
 // ChartMiddlewares middlewares for chart server
 var ChartMiddlewares = []string{CHART}
 
 // Middlewares with sequential organization
-var Middlewares = []string{READONLY, URL, REGTOKEN, MUITIPLEMANIFEST, LISTREPO, CONTENTTRUST, VULNERABLE, SIZEQUOTA, IMMUTABLE, COUNTQUOTA}
+var Middlewares = []string{READONLY, DELETINGGUARD, URL, REGTOKEN, PROXYCACHE, MUITIPLEMANIFEST, LISTREPO, CONTENTTRUST, VULNERABLE, SIZEQUOTA, IMMUTABLE, COUNTQUOTA}
 
 // MiddlewaresLocal ...
-var MiddlewaresLocal = []string{SIZEQUOTA, IMMUTABLE, COUNTQUOTA}
+var MiddlewaresLocal = []string{DELETINGGUARD, SIZEQUOTA, IMMUTABLE, COUNTQUOTA}
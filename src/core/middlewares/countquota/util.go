@@ -36,8 +36,9 @@ func computeResourcesForManifestCreation(req *http.Request) (types.ResourceList,
 		return nil, errors.New("manifest info missing")
 	}
 
-	// only count quota required when push new tag
-	if info.IsNewTag() {
+	// only count quota required when push new tag,
+	// the manifests pushed by digest (e.g. the platform manifests of a manifest list) are not counted
+	if info.Tag != "" && info.IsNewTag() {
 		return quota.ResourceList{quota.ResourceCount: 1}, nil
 	}
 
@@ -56,6 +57,7 @@ func computeResourcesForManifestDeletion(req *http.Request) (types.ResourceList,
 		PID:    info.ProjectID,
 		Repo:   info.Repository,
 		Digest: info.Digest,
+		Tagged: true,
 	})
 
 	if err != nil {
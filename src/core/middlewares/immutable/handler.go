@@ -16,11 +16,16 @@ package immutable
 
 import (
 	"fmt"
+	"net/http"
+	"time"
+
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/filter"
 	"github.com/goharbor/harbor/src/core/middlewares/interceptor"
 	"github.com/goharbor/harbor/src/core/middlewares/util"
 	middlerware_err "github.com/goharbor/harbor/src/core/middlewares/util/error"
-	"net/http"
+	"github.com/goharbor/harbor/src/core/notifier/event"
 )
 
 type immutableHandler struct {
@@ -59,6 +64,9 @@ func (rh *immutableHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 	if err := interceptor.HandleRequest(req); err != nil {
 		log.Warningf("Error occurred when to handle request in immutable handler: %v", err)
 		if _, ok := err.(middlerware_err.ErrImmutable); ok {
+			if match, _, _ := util.MatchPushManifest(req); match {
+				notifyRejected(req)
+			}
 			http.Error(rw, util.MarshalError("DENIED",
 				fmt.Sprintf("%v", err)), http.StatusPreconditionFailed)
 			return
@@ -87,3 +95,38 @@ func (rh *immutableHandler) getInterceptor(req *http.Request) (interceptor.Inter
 
 	return nil, nil
 }
+
+// notifyRejected publishes the event of the manifest pushing rejected by the immutable tag rules
+func notifyRejected(req *http.Request) {
+	info, ok := util.ManifestInfoFromContext(req.Context())
+	if !ok {
+		log.Debug("no manifest info found in the request context, skip publishing the immutable tag rejected event")
+		return
+	}
+	project, err := config.GlobalProjectMgr.Get(info.ProjectID)
+	if err != nil || project == nil {
+		log.Errorf("failed to get the project %d: %v", info.ProjectID, err)
+		return
+	}
+	operator := ""
+	if secCtx, err := filter.GetSecurityContext(req); err == nil {
+		operator = secCtx.GetUsername()
+	}
+
+	e := &event.Event{}
+	metaData := &event.ImmutableTagRejectedMetaData{
+		Project:  project,
+		RepoName: info.Repository,
+		Tag:      info.Tag,
+		Digest:   info.Digest,
+		OccurAt:  time.Now(),
+		Operator: operator,
+	}
+	if err := e.Build(metaData); err != nil {
+		log.Errorf("failed to build the immutable tag rejected event: %v", err)
+		return
+	}
+	if err := e.Publish(); err != nil {
+		log.Errorf("failed to publish the immutable tag rejected event: %v", err)
+	}
+}
@@ -38,11 +38,22 @@ func (dmf *delmfInterceptor) HandleRequest(req *http.Request) (err error) {
 		log.Error(err)
 		return
 	}
+	// the manifest referenced by a manifest list is protected by the tags of the manifest list as well
+	var parents []*models.Artifact
+	parents, err = dao.ListParentArtifacts(dmf.mf.ProjectID, dmf.mf.Repository, dmf.mf.Digest)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	afs = append(afs, parents...)
 	if len(afs) == 0 {
 		return
 	}
 
 	for _, af := range afs {
+		if af.Tag == "" {
+			continue
+		}
 		_, repoName := common_util.ParseRepository(dmf.mf.Repository)
 		var matched bool
 		matched, err = rule.NewRuleMatcher(dmf.mf.ProjectID).Match(art.Candidate{
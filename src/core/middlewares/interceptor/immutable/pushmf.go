@@ -26,6 +26,10 @@ type pushmfInterceptor struct {
 
 // HandleRequest ...
 func (pmf *pushmfInterceptor) HandleRequest(req *http.Request) (err error) {
+	// the manifest pushed by digest doesn't move any tag
+	if pmf.mf.Tag == "" {
+		return
+	}
 
 	_, repoName := common_util.ParseRepository(pmf.mf.Repository)
 	var matched bool
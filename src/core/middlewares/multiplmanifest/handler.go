@@ -15,10 +15,12 @@
 package multiplmanifest
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/core/middlewares/util"
-	"net/http"
-	"strings"
 )
 
 type multipleManifestHandler struct {
@@ -32,15 +34,15 @@ func New(next http.Handler) http.Handler {
 	}
 }
 
-// ServeHTTP The handler is responsible for blocking request to upload manifest list by docker client, which is not supported so far by Harbor.
+// ServeHTTP The handler is responsible for blocking request to upload manifest whose media type is not supported by Harbor,
+// the image manifests, the Docker manifest lists and the OCI image indexes are accepted.
 func (mh multipleManifestHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	match, _, _ := util.MatchPushManifest(req)
 	if match {
 		contentType := req.Header.Get("Content-type")
-		// application/vnd.docker.distribution.manifest.list.v2+json
-		if strings.Contains(contentType, "manifest.list.v2") {
+		if !registry.IsSupportedManifest(contentType) {
 			log.Debugf("Content-type: %s is not supported, failing the response.", contentType)
-			http.Error(rw, util.MarshalError("UNSUPPORTED_MEDIA_TYPE", "Manifest.list is not supported."), http.StatusUnsupportedMediaType)
+			http.Error(rw, util.MarshalError("UNSUPPORTED_MEDIA_TYPE", fmt.Sprintf("Manifest with media type %s is not supported.", contentType)), http.StatusUnsupportedMediaType)
 			return
 		}
 	}
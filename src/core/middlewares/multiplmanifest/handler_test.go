@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiplmanifest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func doPutManifestRequest(contentType string) int {
+	req, _ := http.NewRequest(http.MethodPut, "/v2/library/photon/manifests/latest", nil)
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+
+	next := func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}
+	New(http.HandlerFunc(next)).ServeHTTP(rr, req)
+
+	return rr.Code
+}
+
+func TestServeHTTP(t *testing.T) {
+	assert.Equal(t, http.StatusCreated, doPutManifestRequest(schema2.MediaTypeManifest))
+	assert.Equal(t, http.StatusCreated, doPutManifestRequest(manifestlist.MediaTypeManifestList))
+	assert.Equal(t, http.StatusCreated, doPutManifestRequest(v1.MediaTypeImageIndex))
+	assert.Equal(t, http.StatusCreated, doPutManifestRequest(v1.MediaTypeImageManifest))
+	assert.Equal(t, http.StatusCreated, doPutManifestRequest(schema2.MediaTypeManifest+"; charset=utf-8"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, doPutManifestRequest("application/json"))
+}
@@ -0,0 +1,162 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxycache
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/docker/distribution/registry/auth"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/middlewares/util"
+	"github.com/goharbor/harbor/src/pkg/proxy"
+	pkg_token "github.com/goharbor/harbor/src/pkg/token"
+	"github.com/goharbor/harbor/src/pkg/token/claims/registry"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	repositoryURLRe = regexp.MustCompile(`^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)(?:manifests|blobs|tags)/`)
+	blobURLRe       = regexp.MustCompile(`^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/([a-zA-Z0-9-_+.]+:[a-fA-F0-9]+)$`)
+)
+
+type proxyCacheHandler struct {
+	next        http.Handler
+	getProject  func(name string) (*models.Project, error)
+	controller  func() proxy.Controller
+	validSecret func(secret string) bool
+	canPull     func(req *http.Request, repository string) bool
+}
+
+// New ...
+func New(next http.Handler) http.Handler {
+	return &proxyCacheHandler{
+		next: next,
+		getProject: func(name string) (*models.Project, error) {
+			return config.GlobalProjectMgr.Get(name)
+		},
+		controller: func() proxy.Controller {
+			return proxy.DefaultController
+		},
+		validSecret: func(secret string) bool {
+			return config.SecretStore.IsValid(secret)
+		},
+		canPull: canPull,
+	}
+}
+
+// ServeHTTP fetches the manifests and blobs pulled from the proxy cache projects from the upstream registry
+// if they're missing or out of date, and rejects the pushes to the proxy cache projects.
+// The requests of the proxy cache controller storing the fetched content into the local registry bypass it.
+func (p *proxyCacheHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if secret := req.Header.Get(proxy.SecretHeader); len(secret) > 0 {
+		// don't pass the secret to the registry
+		req.Header.Del(proxy.SecretHeader)
+		if p.validSecret(secret) {
+			p.next.ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	repository := matchRepository(req)
+	if len(repository) == 0 {
+		p.next.ServeHTTP(rw, req)
+		return
+	}
+
+	project, err := p.getProject(strings.SplitN(repository, "/", 2)[0])
+	if err != nil {
+		log.Errorf("failed to get the project of repository %s: %v", repository, err)
+		http.Error(rw, util.MarshalError("INTERNAL_ERROR", fmt.Sprintf("failed to get the project of repository %s", repository)), http.StatusInternalServerError)
+		return
+	}
+	if project == nil || !project.IsProxyCache() {
+		p.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(rw, util.MarshalError("DENIED",
+			fmt.Sprintf("the project %s is a proxy cache project, the pushes and deletions are not allowed", project.Name)), http.StatusForbidden)
+		return
+	}
+
+	ctl := p.controller()
+	if ctl == nil || !p.canPull(req, repository) {
+		// the unauthorized requests are rejected by the local registry without fetching anything
+		p.next.ServeHTTP(rw, req)
+		return
+	}
+	if match, _, reference := util.MatchManifestURL(req); match {
+		if err := ctl.EnsureManifest(project, repository, reference); err != nil {
+			// let the local registry respond, the cached content is served if it exists
+			log.Errorf("failed to fetch the manifest %s:%s from upstream registry: %v", repository, reference, err)
+		}
+	} else if dgt := matchBlob(req); len(dgt) > 0 {
+		if err := ctl.EnsureBlob(project, repository, dgt); err != nil {
+			log.Errorf("failed to fetch the blob %s of %s from upstream registry: %v", dgt, repository, err)
+		}
+	}
+
+	p.next.ServeHTTP(rw, req)
+}
+
+// canPull returns whether the registry token of the request grants the pull of the repository
+func canPull(req *http.Request, repository string) bool {
+	parts := strings.Split(req.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return false
+	}
+
+	regTK, err := pkg_token.Parse(pkg_token.DefaultTokenOptions(), parts[1], &registry.Claim{})
+	if err != nil {
+		log.Debugf("failed to decode the registry token of the request: %v", err)
+		return false
+	}
+
+	return regTK.Claims.(*registry.Claim).GetAccess().Contains(auth.Access{
+		Resource: auth.Resource{
+			Type: rbac.ResourceRepository.String(),
+			Name: repository,
+		},
+		Action: rbac.ActionPull.String(),
+	})
+}
+
+// matchRepository returns the repository of the registry request which reads or writes the content of repository
+func matchRepository(req *http.Request) string {
+	s := repositoryURLRe.FindStringSubmatch(req.URL.Path)
+	if len(s) != 2 {
+		return ""
+	}
+	return strings.TrimSuffix(s[1], "/")
+}
+
+// matchBlob returns the digest of the blob in the request GET/HEAD /v2/<name>/blobs/<digest>
+func matchBlob(req *http.Request) string {
+	s := blobURLRe.FindStringSubmatch(req.URL.Path)
+	if len(s) != 3 {
+		return ""
+	}
+	if _, err := digest.Parse(s[2]); err != nil {
+		return ""
+	}
+	return s[2]
+}
@@ -0,0 +1,151 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxycache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/pkg/proxy"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:4ab4c602aa5eed5528a6620ff18a1dc4faef0e1ab3a5eddeddb410714478c67f"
+
+type fakeController struct {
+	manifests []string
+	blobs     []string
+}
+
+func (f *fakeController) EnsureManifest(project *models.Project, repository, reference string) error {
+	f.manifests = append(f.manifests, repository+":"+reference)
+	return nil
+}
+
+func (f *fakeController) EnsureBlob(project *models.Project, repository, digest string) error {
+	f.blobs = append(f.blobs, repository+"@"+digest)
+	return nil
+}
+
+func newTestHandler(ctl *fakeController) http.Handler {
+	return &proxyCacheHandler{
+		next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		getProject: func(name string) (*models.Project, error) {
+			project := &models.Project{Name: name}
+			if name == "dockerhub" {
+				project.Metadata = map[string]string{models.ProMetaProxyCacheRegistryID: "1"}
+			}
+			return project, nil
+		},
+		controller: func() proxy.Controller { return ctl },
+		validSecret: func(secret string) bool {
+			return secret == "secret"
+		},
+		canPull: func(req *http.Request, repository string) bool {
+			return req.Header.Get("Authorization") != ""
+		},
+	}
+}
+
+func TestMatchBlob(t *testing.T) {
+	cases := []struct {
+		path   string
+		digest string
+	}{
+		{"/v2/library/hello-world/blobs/" + testDigest, testDigest},
+		{"/v2/library/hello-world/blobs/uploads/", ""},
+		{"/v2/library/hello-world/blobs/sha256:invalid", ""},
+		{"/v2/library/hello-world/manifests/latest", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		assert.Equal(t, c.digest, matchBlob(req), c.path)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	ctl := &fakeController{}
+	handler := newTestHandler(ctl)
+
+	cases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/v2/dockerhub/library/hello-world/manifests/latest", http.StatusOK},
+		{http.MethodHead, "/v2/dockerhub/library/hello-world/blobs/" + testDigest, http.StatusOK},
+		{http.MethodGet, "/v2/library/hello-world/manifests/latest", http.StatusOK},
+		{http.MethodGet, "/v2/_catalog", http.StatusOK},
+		{http.MethodPut, "/v2/dockerhub/library/hello-world/manifests/latest", http.StatusForbidden},
+		{http.MethodPost, "/v2/dockerhub/library/hello-world/blobs/uploads/", http.StatusForbidden},
+		{http.MethodDelete, "/v2/dockerhub/library/hello-world/manifests/" + testDigest, http.StatusForbidden},
+		{http.MethodPut, "/v2/library/hello-world/manifests/latest", http.StatusOK},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(c.method, c.path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, c.status, rec.Code, "%s %s", c.method, c.path)
+	}
+
+	assert.Equal(t, []string{"dockerhub/library/hello-world:latest"}, ctl.manifests)
+	assert.Equal(t, []string{"dockerhub/library/hello-world@" + testDigest}, ctl.blobs)
+}
+
+func TestServeHTTPUnauthorized(t *testing.T) {
+	ctl := &fakeController{}
+	handler := newTestHandler(ctl)
+
+	// nothing is fetched from the upstream registry, the local registry rejects the request
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/dockerhub/library/hello-world/manifests/latest", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, ctl.manifests, 0)
+}
+
+func TestServeHTTPWithSecret(t *testing.T) {
+	ctl := &fakeController{}
+	handler := newTestHandler(ctl)
+
+	cases := []struct {
+		secret string
+		status int
+	}{
+		{"secret", http.StatusOK},
+		{"invalid", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/v2/dockerhub/library/hello-world/manifests/latest", nil)
+		req.Header.Set(proxy.SecretHeader, c.secret)
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, c.status, rec.Code, c.secret)
+		assert.Empty(t, req.Header.Get(proxy.SecretHeader))
+	}
+
+	// the requests of the controller don't re-enter it
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodHead, "/v2/dockerhub/library/hello-world/manifests/latest", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set(proxy.SecretHeader, "secret")
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, ctl.manifests, 0)
+}
@@ -0,0 +1,140 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+
+	"github.com/docker/distribution"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/core/middlewares/util"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	blobUploadURLRe         = regexp.MustCompile(`^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/uploads/([a-zA-Z0-9-_.=]+)/?$`)
+	initiateBlobUploadURLRe = regexp.MustCompile(`^/v2/((?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)+)blobs/uploads/?$`)
+)
+
+// referencedBlobs returns the digests of the blobs which the request will reference,
+// the requests are blob upload complete, blob mount and manifest push
+func referencedBlobs(req *http.Request) ([]string, error) {
+	switch {
+	case req.Method == http.MethodPut && blobUploadURLRe.MatchString(req.URL.Path):
+		// PUT /v2/<name>/blobs/uploads/<uuid>?digest=<digest>
+		if dgt, err := digest.Parse(req.FormValue("digest")); err == nil {
+			return []string{dgt.String()}, nil
+		}
+	case req.Method == http.MethodPost && initiateBlobUploadURLRe.MatchString(req.URL.Path):
+		// POST /v2/<name>/blobs/uploads/?mount=<digest>&from=<repository name>
+		if dgt, err := digest.Parse(req.FormValue("mount")); err == nil {
+			return []string{dgt.String()}, nil
+		}
+	default:
+		if match, _, _ := util.MatchPushManifest(req); match {
+			return manifestReferences(req)
+		}
+	}
+
+	return nil, nil
+}
+
+// manifestReferences returns the digests of the blobs referenced by the manifest in the request body
+func manifestReferences(req *http.Request) ([]string, error) {
+	mediaType := req.Header.Get("Content-Type")
+	if req.Body == nil || !registry.IsSupportedManifest(mediaType) {
+		// let the registry report the invalid manifest
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, body)
+	if err != nil {
+		return nil, nil
+	}
+
+	var digests []string
+	for _, ref := range manifest.References() {
+		digests = append(digests, ref.Digest.String())
+	}
+	return digests, nil
+}
+
+// NewDeletingGuard returns the handler rejecting the requests which reference the blobs being deleted by the online
+// garbage collection, it's applied to the requests from the solution users too
+func NewDeletingGuard(next http.Handler) http.Handler {
+	return &deletingGuard{
+		next: next,
+	}
+}
+
+type deletingGuard struct {
+	next http.Handler
+}
+
+// ServeHTTP records the blobs referenced by the request as pending before checking whether they are being deleted,
+// and removes the records after the request is handled, e.g. the artifact of the manifest is saved. As the gc marks
+// the blobs as deleting before checking the pending records, either the request sees the mark and is rejected or
+// the gc sees the records and keeps the blobs
+func (d *deletingGuard) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	digests, err := referencedBlobs(req)
+	if err != nil {
+		log.Errorf("Failed to parse the blobs referenced by the request, url is: %s, error: %v", req.URL.Path, err)
+		http.Error(rw, util.MarshalError("UNKNOWN", "Failed to check the blobs being deleted."), http.StatusInternalServerError)
+		return
+	}
+	if len(digests) == 0 {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	ids, err := dao.AddPendingBlobReferences(digests...)
+	defer func() {
+		if err := dao.DeletePendingBlobReferences(ids...); err != nil {
+			log.Errorf("Failed to remove the blobs referenced by the request, url is: %s, error: %v", req.URL.Path, err)
+		}
+	}()
+	if err != nil {
+		log.Errorf("Failed to record the blobs referenced by the request, url is: %s, error: %v", req.URL.Path, err)
+		http.Error(rw, util.MarshalError("UNKNOWN", "Failed to check the blobs being deleted."), http.StatusInternalServerError)
+		return
+	}
+
+	deleting, err := dao.GetDeletingBlobs(digests...)
+	if err != nil {
+		log.Errorf("Failed to check the blobs being deleted, url is: %s, error: %v", req.URL.Path, err)
+		http.Error(rw, util.MarshalError("UNKNOWN", "Failed to check the blobs being deleted."), http.StatusInternalServerError)
+		return
+	}
+	if len(deleting) > 0 {
+		log.Warningf("The request references the blobs %v being deleted by garbage collection, url is: %s", deleting, req.URL.Path)
+		rw.Header().Set("Retry-After", "60")
+		http.Error(rw, util.MarshalError("UNAVAILABLE", fmt.Sprintf("The blob %s is being deleted by garbage collection, please retry later.", deleting[0])), http.StatusServiceUnavailable)
+		return
+	}
+
+	d.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,77 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	configDigest = "sha256:c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc"
+	layerDigest  = "sha256:c04b14da8d1441880ed3fe6106fb2cc6fa1c9661846ac0266b8a5ec8edf37b7c"
+)
+
+func TestReferencedBlobs(t *testing.T) {
+	assert := assert.New(t)
+
+	req := httptest.NewRequest(http.MethodPut, "/v2/library/photon/blobs/uploads/uuid?digest="+layerDigest, nil)
+	digests, err := referencedBlobs(req)
+	assert.Nil(err)
+	assert.Equal([]string{layerDigest}, digests)
+
+	req = httptest.NewRequest(http.MethodPost, "/v2/library/photon/blobs/uploads/?mount="+layerDigest+"&from=library/hello", nil)
+	digests, err = referencedBlobs(req)
+	assert.Nil(err)
+	assert.Equal([]string{layerDigest}, digests)
+
+	req = httptest.NewRequest(http.MethodPatch, "/v2/library/photon/blobs/uploads/uuid", nil)
+	digests, err = referencedBlobs(req)
+	assert.Nil(err)
+	assert.Len(digests, 0)
+
+	body := []byte(`{
+   "schemaVersion":2,
+   "mediaType":"application/vnd.docker.distribution.manifest.v2+json",
+   "config":{
+      "mediaType":"application/vnd.docker.container.image.v1+json",
+      "size":1473,
+      "digest":"` + configDigest + `"
+   },
+   "layers":[
+      {
+         "mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip",
+         "size":974,
+         "digest":"` + layerDigest + `"
+      }
+   ]
+}`)
+	req = httptest.NewRequest(http.MethodPut, "/v2/library/photon/manifests/latest", bytes.NewReader(body))
+	req.Header.Set("Content-Type", schema2.MediaTypeManifest)
+	digests, err = referencedBlobs(req)
+	assert.Nil(err)
+	assert.Equal([]string{configDigest, layerDigest}, digests)
+
+	// the body is still readable by the next handlers
+	data, err := ioutil.ReadAll(req.Body)
+	assert.Nil(err)
+	assert.Equal(body, data)
+}
@@ -29,13 +29,12 @@ import (
 	"time"
 
 	"github.com/docker/distribution"
-	"github.com/docker/distribution/manifest/schema1"
-	"github.com/docker/distribution/manifest/schema2"
 	"github.com/garyburd/redigo/redis"
 	"github.com/goharbor/harbor/src/common/dao"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/core/config"
 	"github.com/goharbor/harbor/src/core/filter"
 	notifierEvt "github.com/goharbor/harbor/src/core/notifier/event"
@@ -136,7 +135,10 @@ type ManifestInfo struct {
 	Repository string
 	Tag        string
 	Digest     string
+	MediaType  string
 
+	// References are the layers of an image manifest,
+	// or the platform specific manifests of a manifest list
 	References []distribution.Descriptor
 	Descriptor distribution.Descriptor
 
@@ -207,6 +209,11 @@ func (info *ManifestInfo) GetBlobsNotInProject() ([]*models.Blob, error) {
 
 func (info *ManifestInfo) fetchArtifact() (*models.Artifact, error) {
 	info.artifactOnce.Do(func() {
+		if info.Tag == "" {
+			// pushed by digest, e.g. the manifests referenced by a manifest list
+			info.artifact, info.artifactErr = dao.GetUntaggedArtifact(info.Repository, info.Digest)
+			return
+		}
 		info.artifact, info.artifactErr = dao.GetArtifact(info.Repository, info.Tag)
 	})
 
@@ -220,6 +227,11 @@ func (info *ManifestInfo) IsNewTag() bool {
 	return artifact == nil
 }
 
+// IsManifestList returns true if the manifest is a manifest list or an OCI image index
+func (info *ManifestInfo) IsManifestList() bool {
+	return registry.IsManifestList(info.MediaType)
+}
+
 // Artifact returns artifact of the manifest
 func (info *ManifestInfo) Artifact() *models.Artifact {
 	result := &models.Artifact{
@@ -227,7 +239,10 @@ func (info *ManifestInfo) Artifact() *models.Artifact {
 		Repo:   info.Repository,
 		Tag:    info.Tag,
 		Digest: info.Digest,
-		Kind:   "Docker-Image",
+		Kind:   models.ArtifactKindImage,
+	}
+	if info.IsManifestList() {
+		result.Kind = models.ArtifactKindImageIndex
 	}
 
 	if artifact, _ := info.fetchArtifact(); artifact != nil {
@@ -492,9 +507,7 @@ func ParseManifestInfoFromReq(req *http.Request) (*ManifestInfo, error) {
 	}
 
 	mediaType := req.Header.Get("Content-Type")
-	if mediaType != schema1.MediaTypeManifest &&
-		mediaType != schema1.MediaTypeSignedManifest &&
-		mediaType != schema2.MediaTypeManifest {
+	if !registry.IsSupportedManifest(mediaType) {
 		return nil, fmt.Errorf("unsupported content type for manifest: %s", mediaType)
 	}
 
@@ -529,6 +542,7 @@ func ParseManifestInfoFromReq(req *http.Request) (*ManifestInfo, error) {
 		Repository: repository,
 		Tag:        tag,
 		Digest:     desc.Digest.String(),
+		MediaType:  mediaType,
 		References: manifest.References(),
 		Descriptor: desc,
 	}, nil
@@ -305,3 +305,94 @@ func (e *Event) Publish() error {
 	}
 	return nil
 }
+
+// ImmutableTagRejectedMetaData defines the push rejected by the immutable tag rules event data
+type ImmutableTagRejectedMetaData struct {
+	Project  *models.Project
+	Tag      string
+	Digest   string
+	OccurAt  time.Time
+	Operator string
+	RepoName string
+}
+
+// Resolve the rejected pushing metadata into common image event
+func (i *ImmutableTagRejectedMetaData) Resolve(evt *Event) error {
+	data := &model.ImageEvent{
+		EventType: notifyModel.EventTypeImmutableTagRejected,
+		Project:   i.Project,
+		OccurAt:   i.OccurAt,
+		Operator:  i.Operator,
+		RepoName:  i.RepoName,
+		Resource: []*model.ImgResource{
+			{
+				Tag:    i.Tag,
+				Digest: i.Digest,
+			},
+		},
+	}
+
+	evt.Topic = model.ImmutableTagRejectedTopic
+	evt.Data = data
+	return nil
+}
+
+// ReplicationMetaData defines replication execution finished event data
+type ReplicationMetaData struct {
+	ExecutionID int64
+	OccurAt     time.Time
+}
+
+// Resolve replication metadata into replication event
+func (r *ReplicationMetaData) Resolve(evt *Event) error {
+	evt.Topic = model.ReplicationTopic
+	evt.Data = &model.ReplicationEvent{
+		EventType:   notifyModel.EventTypeReplication,
+		ExecutionID: r.ExecutionID,
+		OccurAt:     r.OccurAt,
+	}
+	return nil
+}
+
+// RetentionMetaData defines tag retention finished event data of a retention execution
+type RetentionMetaData struct {
+	ExecutionID int64
+	OccurAt     time.Time
+}
+
+// Resolve retention metadata into retention event
+func (r *RetentionMetaData) Resolve(evt *Event) error {
+	evt.Topic = model.TagRetentionTopic
+	evt.Data = &model.RetentionEvent{
+		EventType:   notifyModel.EventTypeTagRetention,
+		ExecutionID: r.ExecutionID,
+		OccurAt:     r.OccurAt,
+	}
+	return nil
+}
+
+// GCMetaData defines system garbage collection finished event data
+type GCMetaData struct {
+	JobID   int64
+	Status  string
+	OccurAt time.Time
+}
+
+// Resolve gc metadata into gc event
+func (g *GCMetaData) Resolve(evt *Event) error {
+	switch g.Status {
+	case models.JobFinished, models.JobError, models.JobStopped:
+	default:
+		return errors.New("not supported gc hook status")
+	}
+
+	evt.Topic = model.SystemGCTopic
+	evt.Data = &model.GCEvent{
+		EventType: notifyModel.EventTypeSystemGC,
+		JobID:     g.JobID,
+		Status:    g.Status,
+		OccurAt:   g.OccurAt,
+		Operator:  autoTriggeredOperator,
+	}
+	return nil
+}
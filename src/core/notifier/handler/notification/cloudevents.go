@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/google/uuid"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// the content type of the structured mode request
+	cloudEventsContentType = "application/cloudevents+json"
+	// the content type of the data
+	jsonContentType = "application/json"
+)
+
+// cloudEventTypes maps the Harbor event types to the "type" attributes of the CloudEvents,
+// the "type" attributes must be kept stable as the consumers route the events by them
+var cloudEventTypes = map[string]string{
+	notifyModel.EventTypePushImage:            "io.goharbor.image.pushed",
+	notifyModel.EventTypePullImage:            "io.goharbor.image.pulled",
+	notifyModel.EventTypeDeleteImage:          "io.goharbor.image.deleted",
+	notifyModel.EventTypeUploadChart:          "io.goharbor.chart.uploaded",
+	notifyModel.EventTypeDeleteChart:          "io.goharbor.chart.deleted",
+	notifyModel.EventTypeDownloadChart:        "io.goharbor.chart.downloaded",
+	notifyModel.EventTypeScanningCompleted:    "io.goharbor.scanning.completed",
+	notifyModel.EventTypeScanningFailed:       "io.goharbor.scanning.failed",
+	notifyModel.EventTypeTestEndpoint:         "io.goharbor.endpoint.tested",
+	notifyModel.EventTypeProjectQuota:         "io.goharbor.project.quota",
+	notifyModel.EventTypeReplication:          "io.goharbor.replication.finished",
+	notifyModel.EventTypeTagRetention:         "io.goharbor.retention.finished",
+	notifyModel.EventTypeImmutableTagRejected: "io.goharbor.image.rejected",
+	notifyModel.EventTypeSystemGC:             "io.goharbor.gc.finished",
+}
+
+// cloudEvent is the CloudEvents 1.0 event in the JSON format
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// cloudEventType returns the "type" attribute of the event type
+func cloudEventType(eventType string) string {
+	if t, ok := cloudEventTypes[eventType]; ok {
+		return t
+	}
+	return "io.goharbor." + eventType
+}
+
+// newCloudEvent wraps the payload as the data of the CloudEvent,
+// the "source" is the project and the "subject" is the repository and the tag if there is only one
+func newCloudEvent(payload *model.Payload) (*cloudEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ce := &cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              uuid.New().String(),
+		Type:            cloudEventType(payload.Type),
+		Source:          "/projects",
+		Time:            time.Unix(payload.OccurAt, 0).UTC().Format(time.RFC3339),
+		DataContentType: jsonContentType,
+		Data:            data,
+	}
+	if payload.EventData != nil && payload.EventData.Repository != nil {
+		repo := payload.EventData.Repository
+		ce.Source = fmt.Sprintf("/projects/%s", repo.Namespace)
+		ce.Subject = repo.RepoFullName
+		if resources := payload.EventData.Resources; len(resources) == 1 && len(resources[0].Tag) > 0 {
+			ce.Subject = fmt.Sprintf("%s:%s", repo.RepoFullName, resources[0].Tag)
+		}
+	}
+	return ce, nil
+}
+
+// httpMessage returns the body, the content type and the "ce-" headers of the HTTP request in the mode
+func (ce *cloudEvent) httpMessage(mode string) (string, string, map[string]string, error) {
+	switch mode {
+	case "", notifyModel.CloudEventsModeStructured:
+		body, err := json.Marshal(ce)
+		if err != nil {
+			return "", "", nil, err
+		}
+		return string(body), cloudEventsContentType, nil, nil
+	case notifyModel.CloudEventsModeBinary:
+		headers := map[string]string{
+			"ce-specversion": ce.SpecVersion,
+			"ce-id":          ce.ID,
+			"ce-type":        ce.Type,
+			"ce-source":      ce.Source,
+			"ce-time":        ce.Time,
+		}
+		if len(ce.Subject) > 0 {
+			headers["ce-subject"] = ce.Subject
+		}
+		return string(ce.Data), ce.DataContentType, headers, nil
+	default:
+		return "", "", nil, fmt.Errorf("unsupported CloudEvents mode %s", mode)
+	}
+}
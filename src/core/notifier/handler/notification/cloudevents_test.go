@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	cModels "github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPayload() *model.Payload {
+	return &model.Payload{
+		Type:     notifyModel.EventTypePushImage,
+		OccurAt:  time.Date(2019, 12, 1, 8, 0, 0, 0, time.UTC).Unix(),
+		Operator: "admin",
+		EventData: &model.EventData{
+			Resources: []*model.Resource{
+				{Tag: "latest", Digest: "sha256:abc"},
+			},
+			Repository: &model.Repository{
+				Name:         "hello-world",
+				Namespace:    "library",
+				RepoFullName: "library/hello-world",
+			},
+		},
+	}
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	ce, err := newCloudEvent(newTestPayload())
+	require.Nil(t, err)
+	assert.Equal(t, "1.0", ce.SpecVersion)
+	assert.NotEmpty(t, ce.ID)
+	assert.Equal(t, "io.goharbor.image.pushed", ce.Type)
+	assert.Equal(t, "/projects/library", ce.Source)
+	assert.Equal(t, "library/hello-world:latest", ce.Subject)
+	assert.Equal(t, "2019-12-01T08:00:00Z", ce.Time)
+
+	payload := &model.Payload{}
+	require.Nil(t, json.Unmarshal(ce.Data, payload))
+	assert.Equal(t, "admin", payload.Operator)
+
+	ce, err = newCloudEvent(&model.Payload{Type: notifyModel.EventTypeTestEndpoint})
+	require.Nil(t, err)
+	assert.Equal(t, "/projects", ce.Source)
+	assert.Empty(t, ce.Subject)
+	assert.Equal(t, "io.goharbor.unknownEvent", cloudEventType("unknownEvent"))
+}
+
+func TestSetPayload(t *testing.T) {
+	event := &model.HookEvent{
+		Target:  &cModels.EventTarget{Type: "http"},
+		Payload: newTestPayload(),
+	}
+
+	params := map[string]interface{}{}
+	require.Nil(t, setPayload(params, event))
+	assert.Nil(t, params["content_type"])
+	assert.Contains(t, params["payload"], `"type":"pushImage"`)
+
+	// structured mode
+	event.Target.PayloadFormat = notifyModel.PayloadFormatCloudEvents
+	params = map[string]interface{}{}
+	require.Nil(t, setPayload(params, event))
+	assert.Equal(t, "application/cloudevents+json", params["content_type"])
+	assert.Nil(t, params["headers"])
+	ce := &cloudEvent{}
+	require.Nil(t, json.Unmarshal([]byte(params["payload"].(string)), ce))
+	assert.Equal(t, "io.goharbor.image.pushed", ce.Type)
+
+	// binary mode
+	event.Target.CloudEventsMode = notifyModel.CloudEventsModeBinary
+	params = map[string]interface{}{}
+	require.Nil(t, setPayload(params, event))
+	assert.Equal(t, "application/json", params["content_type"])
+	headers := params["headers"].(map[string]string)
+	assert.Equal(t, "io.goharbor.image.pushed", headers["ce-type"])
+	assert.Equal(t, "library/hello-world:latest", headers["ce-subject"])
+	assert.Contains(t, params["payload"], `"type":"pushImage"`)
+
+	event.Target.CloudEventsMode = "unknown"
+	assert.NotNil(t, setPayload(map[string]interface{}{}, event))
+	event.Target.PayloadFormat = "unknown"
+	assert.NotNil(t, setPayload(map[string]interface{}{}, event))
+}
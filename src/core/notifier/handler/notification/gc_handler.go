@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/goharbor/harbor/src/pkg/notification"
+)
+
+// GCPreprocessHandler preprocess system gc event data
+type GCPreprocessHandler struct {
+}
+
+// Handle sends the system gc event to the policies of the projects which subscribe it, as the gc
+// is a system level job, each project only receives the part of the report about itself
+func (g *GCPreprocessHandler) Handle(value interface{}) error {
+	if !config.NotificationEnable() {
+		log.Debug("notification feature is not enabled")
+		return nil
+	}
+
+	gcEvent, ok := value.(*model.GCEvent)
+	if !ok || gcEvent == nil {
+		return errors.New("invalid system gc event")
+	}
+
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		return err
+	}
+	report, err := getGCReport(gcEvent.JobID)
+	if err != nil {
+		return err
+	}
+	errRet := false
+	for _, project := range projects {
+		policies, err := notification.PolicyMgr.GetRelatedPolices(project.ProjectID, gcEvent.EventType)
+		if err != nil {
+			log.Errorf("failed to find policy for %s event: %v", gcEvent.EventType, err)
+			return err
+		}
+		if len(policies) == 0 {
+			continue
+		}
+		payload, err := constructGCPayload(gcEvent, filterGCReport(report, project.ProjectID))
+		if err != nil {
+			return err
+		}
+		if err = sendHookWithPolicies(policies, payload, gcEvent.EventType); err != nil {
+			errRet = true
+		}
+	}
+	if errRet {
+		return errors.New("failed to trigger some of the gc events")
+	}
+	return nil
+}
+
+// IsStateful ...
+func (g *GCPreprocessHandler) IsStateful() bool {
+	return false
+}
+
+// getGCReport returns the report saved by the gc job, nil if there is no valid one
+func getGCReport(jobID int64) (*models.GCReport, error) {
+	job, err := dao.GetAdminJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || len(job.Report) == 0 {
+		return nil, nil
+	}
+	report := &models.GCReport{}
+	if err := json.Unmarshal([]byte(job.Report), report); err != nil {
+		log.Warningf("failed to parse the report of gc job %d: %v", jobID, err)
+		return nil, nil
+	}
+	return report, nil
+}
+
+// filterGCReport returns the report only containing the part of the given project
+func filterGCReport(report *models.GCReport, projectID int64) *models.GCReport {
+	if report == nil {
+		return nil
+	}
+	filtered := &models.GCReport{
+		DryRun:    report.DryRun,
+		StartTime: report.StartTime,
+		EndTime:   report.EndTime,
+		Projects:  []*models.GCProjectReport{},
+	}
+	for _, pr := range report.Projects {
+		if pr.ProjectID != projectID {
+			continue
+		}
+		filtered.Projects = append(filtered.Projects, pr)
+		filtered.TotalManifests += len(pr.UntaggedManifests)
+		filtered.TotalBlobs += len(pr.OrphanBlobs)
+		filtered.TotalSize += pr.Size
+	}
+	return filtered
+}
+
+func constructGCPayload(event *model.GCEvent, report *models.GCReport) (*model.Payload, error) {
+	gc := &model.GC{
+		JobID:  event.JobID,
+		Status: event.Status,
+	}
+	if report != nil {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, err
+		}
+		gc.Report = json.RawMessage(data)
+	}
+
+	return &model.Payload{
+		Type:     event.EventType,
+		OccurAt:  event.OccurAt.Unix(),
+		Operator: event.Operator,
+		EventData: &model.EventData{
+			GC: gc,
+		},
+	}, nil
+}
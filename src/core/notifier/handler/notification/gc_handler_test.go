@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterGCReport(t *testing.T) {
+	assert.Nil(t, filterGCReport(nil, 1))
+
+	report := &models.GCReport{
+		DryRun: true,
+		Projects: []*models.GCProjectReport{
+			{
+				ProjectID:         1,
+				UntaggedManifests: []*models.GCReportItem{{Repository: "library/hello-world", Digest: "sha256:1", Size: 1}},
+				OrphanBlobs:       []*models.GCReportItem{{Digest: "sha256:2", Size: 2}, {Digest: "sha256:3", Size: 3}},
+				Size:              6,
+			},
+			{
+				ProjectID:         2,
+				UntaggedManifests: []*models.GCReportItem{{Repository: "other/busybox", Digest: "sha256:4", Size: 4}},
+				OrphanBlobs:       []*models.GCReportItem{},
+				Size:              4,
+			},
+		},
+		TotalManifests: 2,
+		TotalBlobs:     2,
+		TotalSize:      10,
+	}
+
+	filtered := filterGCReport(report, 1)
+	require.Equal(t, 1, len(filtered.Projects))
+	assert.Equal(t, int64(1), filtered.Projects[0].ProjectID)
+	assert.True(t, filtered.DryRun)
+	assert.Equal(t, 1, filtered.TotalManifests)
+	assert.Equal(t, 2, filtered.TotalBlobs)
+	assert.Equal(t, int64(6), filtered.TotalSize)
+
+	// nothing about the project
+	filtered = filterGCReport(report, 3)
+	assert.Equal(t, 0, len(filtered.Projects))
+	assert.Equal(t, int64(0), filtered.TotalSize)
+
+	payload, err := constructGCPayload(&model.GCEvent{
+		EventType: "SYSTEM_GC",
+		JobID:     1,
+		OccurAt:   time.Now(),
+	}, filterGCReport(report, 2))
+	require.Nil(t, err)
+	r := &models.GCReport{}
+	require.Nil(t, json.Unmarshal(payload.EventData.GC.Report, r))
+	require.Equal(t, 1, len(r.Projects))
+	assert.Equal(t, "other/busybox", r.Projects[0].UntaggedManifests[0].Repository)
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/goharbor/harbor/src/common/config/encrypt"
 	"github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/core/notifier/model"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
 )
 
 // HTTPHandler preprocess http event data and start the hook processing
@@ -42,18 +44,54 @@ func (h *HTTPHandler) process(event *model.HookEvent) error {
 	}
 	j.Name = job.WebhookJob
 
-	payload, err := json.Marshal(event.Payload)
-	if err != nil {
-		return fmt.Errorf("marshal from payload %v failed: %v", event.Payload, err)
-	}
-
 	j.Parameters = map[string]interface{}{
-		"payload": string(payload),
 		"address": event.Target.Address,
 		// Users can define a auth header in http statement in notification(webhook) policy.
 		// So it will be sent in header in http request.
 		"auth_header":      event.Target.AuthHeader,
 		"skip_cert_verify": event.Target.SkipCertVerify,
 	}
+	// the secret to sign the request is passed encrypted as it is persisted with the job,
+	// the job decrypts it and computes the signature when sending the request
+	if len(event.Target.Secret) > 0 {
+		secret, err := encrypt.Instance().Encrypt(event.Target.Secret)
+		if err != nil {
+			return fmt.Errorf("encrypt the secret of policy %d failed: %v", event.PolicyID, err)
+		}
+		j.Parameters["secret"] = secret
+	}
+	if err := setPayload(j.Parameters, event); err != nil {
+		return err
+	}
 	return notification.HookManager.StartHook(event, j)
 }
+
+// setPayload sets the payload, the content type and the extra headers of the webhook request
+// in the payload format of the target to the job parameters
+func setPayload(params map[string]interface{}, event *model.HookEvent) error {
+	switch event.Target.PayloadFormat {
+	case "", notifyModel.PayloadFormatDefault:
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			return fmt.Errorf("marshal from payload %v failed: %v", event.Payload, err)
+		}
+		params["payload"] = string(payload)
+	case notifyModel.PayloadFormatCloudEvents:
+		ce, err := newCloudEvent(event.Payload)
+		if err != nil {
+			return fmt.Errorf("build cloud event from payload %v failed: %v", event.Payload, err)
+		}
+		body, contentType, headers, err := ce.httpMessage(event.Target.CloudEventsMode)
+		if err != nil {
+			return err
+		}
+		params["payload"] = body
+		params["content_type"] = contentType
+		if len(headers) > 0 {
+			params["headers"] = headers
+		}
+	default:
+		return fmt.Errorf("unsupported payload format %s", event.Target.PayloadFormat)
+	}
+	return nil
+}
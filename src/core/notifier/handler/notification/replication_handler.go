@@ -0,0 +1,138 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/replication"
+	rep_models "github.com/goharbor/harbor/src/replication/dao/models"
+	rep_model "github.com/goharbor/harbor/src/replication/model"
+)
+
+// ReplicationPreprocessHandler preprocess replication event data
+type ReplicationPreprocessHandler struct {
+}
+
+// Handle sends the replication event to the policies of the local projects involved in the execution
+func (r *ReplicationPreprocessHandler) Handle(value interface{}) error {
+	if !config.NotificationEnable() {
+		log.Debug("notification feature is not enabled")
+		return nil
+	}
+
+	repEvent, ok := value.(*model.ReplicationEvent)
+	if !ok || repEvent == nil {
+		return errors.New("invalid replication event")
+	}
+
+	execution, err := replication.OperationCtl.GetExecution(repEvent.ExecutionID)
+	if err != nil {
+		return err
+	}
+	if execution == nil {
+		return fmt.Errorf("replication execution %d not found", repEvent.ExecutionID)
+	}
+	policy, err := replication.PolicyCtl.Get(execution.PolicyID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("replication policy %d not found", execution.PolicyID)
+	}
+	_, tasks, err := replication.OperationCtl.ListTasks(&rep_models.TaskQuery{
+		ExecutionID: execution.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	payload := constructReplicationPayload(repEvent, execution, policy)
+	errRet := false
+	for _, namespace := range localNamespaces(policy, tasks) {
+		project, err := config.GlobalProjectMgr.Get(namespace)
+		if err != nil {
+			log.Errorf("failed to get project %s: %v", namespace, err)
+			errRet = true
+			continue
+		}
+		if project == nil {
+			log.Debugf("project %s of replication execution %d not found", namespace, execution.ID)
+			continue
+		}
+		policies, err := notification.PolicyMgr.GetRelatedPolices(project.ProjectID, repEvent.EventType)
+		if err != nil {
+			log.Errorf("failed to find policy for %s event: %v", repEvent.EventType, err)
+			errRet = true
+			continue
+		}
+		if len(policies) == 0 {
+			log.Debugf("cannot find policy for %s event of project %s", repEvent.EventType, namespace)
+			continue
+		}
+		if err := sendHookWithPolicies(policies, payload, repEvent.EventType); err != nil {
+			errRet = true
+		}
+	}
+	if errRet {
+		return errors.New("failed to send some of the replication events")
+	}
+	return nil
+}
+
+// IsStateful ...
+func (r *ReplicationPreprocessHandler) IsStateful() bool {
+	return false
+}
+
+func constructReplicationPayload(event *model.ReplicationEvent, execution *rep_models.Execution, policy *rep_model.Policy) *model.Payload {
+	return &model.Payload{
+		Type:     event.EventType,
+		OccurAt:  event.OccurAt.Unix(),
+		Operator: string(execution.Trigger),
+		EventData: &model.EventData{
+			Replication: &model.Replication{
+				ExecutionID: execution.ID,
+				PolicyID:    policy.ID,
+				PolicyName:  policy.Name,
+				Trigger:     string(execution.Trigger),
+				Status:      execution.Status,
+				Total:       execution.Total,
+				Succeed:     execution.Succeed,
+				Failed:      execution.Failed,
+				Stopped:     execution.Stopped,
+				StartTime:   execution.StartTime.Unix(),
+				EndTime:     execution.EndTime.Unix(),
+			},
+		},
+	}
+}
+
+// localNamespaces returns the namespaces of local Harbor involved in the replication execution,
+// they're the source namespaces of the push-based replication or the destination namespaces of the pull-based one
+func localNamespaces(policy *rep_model.Policy, tasks []*rep_models.Task) []string {
+	pushBased := policy.SrcRegistry == nil || policy.SrcRegistry.ID == 0
+	var namespaces []string
+	exist := map[string]bool{}
+	for _, task := range tasks {
+		resource := task.DstResource
+		if pushBased {
+			resource = task.SrcResource
+		}
+		i := strings.Index(resource, "/")
+		if i <= 0 {
+			continue
+		}
+		namespace := resource[:i]
+		if exist[namespace] {
+			continue
+		}
+		exist[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
+	rep_models "github.com/goharbor/harbor/src/replication/dao/models"
+	rep_model "github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalNamespaces(t *testing.T) {
+	tasks := []*rep_models.Task{
+		{SrcResource: "library/hello-world:[latest]", DstResource: "dst/hello-world:[latest]"},
+		{SrcResource: "library/busybox:[1.0]", DstResource: "dst/busybox:[1.0]"},
+		{SrcResource: "other/alpine:[3.9]", DstResource: "dst/alpine:[3.9]"},
+	}
+
+	// push-based
+	policy := &rep_model.Policy{
+		DestRegistry: &rep_model.Registry{ID: 1},
+	}
+	assert.Equal(t, []string{"library", "other"}, localNamespaces(policy, tasks))
+
+	// pull-based
+	policy = &rep_model.Policy{
+		SrcRegistry: &rep_model.Registry{ID: 1},
+	}
+	assert.Equal(t, []string{"dst"}, localNamespaces(policy, tasks))
+}
+
+func TestConstructReplicationPayload(t *testing.T) {
+	now := time.Now()
+	event := &model.ReplicationEvent{
+		EventType:   notifyModel.EventTypeReplication,
+		ExecutionID: 1,
+		OccurAt:     now,
+	}
+	execution := &rep_models.Execution{
+		ID:        1,
+		PolicyID:  2,
+		Status:    rep_models.ExecutionStatusSucceed,
+		Trigger:   rep_model.TriggerTypeManual,
+		Total:     3,
+		Succeed:   3,
+		StartTime: now,
+		EndTime:   now,
+	}
+	policy := &rep_model.Policy{
+		ID:   2,
+		Name: "rule",
+	}
+	payload := constructReplicationPayload(event, execution, policy)
+	assert.Equal(t, notifyModel.EventTypeReplication, payload.Type)
+	assert.Equal(t, "manual", payload.Operator)
+	require.NotNil(t, payload.EventData.Replication)
+	assert.Equal(t, "rule", payload.EventData.Replication.PolicyName)
+	assert.Equal(t, 3, payload.EventData.Replication.Succeed)
+	assert.Equal(t, rep_models.ExecutionStatusSucceed, payload.EventData.Replication.Status)
+}
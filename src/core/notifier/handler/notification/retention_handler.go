@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/retention"
+	"github.com/goharbor/harbor/src/pkg/retention/q"
+)
+
+// RetentionPreprocessHandler preprocess tag retention event data
+type RetentionPreprocessHandler struct {
+}
+
+// Handle sends the tag retention event of an execution to the policies of the project
+func (r *RetentionPreprocessHandler) Handle(value interface{}) error {
+	if !config.NotificationEnable() {
+		log.Debug("notification feature is not enabled")
+		return nil
+	}
+
+	retentionEvent, ok := value.(*model.RetentionEvent)
+	if !ok || retentionEvent == nil {
+		return errors.New("invalid tag retention event")
+	}
+
+	mgr := retention.NewManager()
+	execution, err := mgr.GetExecution(retentionEvent.ExecutionID)
+	if err != nil {
+		return err
+	}
+	policy, err := mgr.GetPolicy(execution.PolicyID)
+	if err != nil {
+		return err
+	}
+	if policy == nil || policy.Scope == nil {
+		return fmt.Errorf("retention policy %d not found", execution.PolicyID)
+	}
+
+	project, err := config.GlobalProjectMgr.Get(policy.Scope.Reference)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project %d of retention policy %d not found", policy.Scope.Reference, policy.ID)
+	}
+
+	policies, err := notification.PolicyMgr.GetRelatedPolices(project.ProjectID, retentionEvent.EventType)
+	if err != nil {
+		log.Errorf("failed to find policy for %s event: %v", retentionEvent.EventType, err)
+		return err
+	}
+	if len(policies) == 0 {
+		log.Debugf("cannot find policy for %s event: %v", retentionEvent.EventType, retentionEvent)
+		return nil
+	}
+
+	tasks, err := mgr.ListTasks(&q.TaskQuery{
+		ExecutionID: execution.ID,
+	})
+	if err != nil {
+		return err
+	}
+	extURL, err := config.ExtURL()
+	if err != nil {
+		return err
+	}
+	payload, err := constructRetentionPayload(retentionEvent, project, execution, tasks, extURL)
+	if err != nil {
+		return err
+	}
+	return sendHookWithPolicies(policies, payload, retentionEvent.EventType)
+}
+
+// IsStateful ...
+func (r *RetentionPreprocessHandler) IsStateful() bool {
+	return false
+}
+
+// constructRetentionPayload constructs the payload with the summary of all the tasks of the execution,
+// the deleted tags of all the repositories are in the resources
+func constructRetentionPayload(event *model.RetentionEvent, project *models.Project, execution *retention.Execution,
+	tasks []*retention.Task, extURL string) (*model.Payload, error) {
+	summary := &model.Retention{
+		ExecutionID: execution.ID,
+		PolicyID:    execution.PolicyID,
+		Trigger:     execution.Trigger,
+		DryRun:      execution.DryRun,
+		Status:      execution.Status,
+	}
+	payload := &model.Payload{
+		Type:     event.EventType,
+		OccurAt:  event.OccurAt.Unix(),
+		Operator: execution.Trigger,
+		EventData: &model.EventData{
+			Retention: summary,
+		},
+	}
+	for _, task := range tasks {
+		summary.Total += task.Total
+		summary.Retained += task.Retained
+		summary.Deleted += len(task.Deleted)
+		repoName := fmt.Sprintf("%s/%s", project.Name, task.Repository)
+		for _, tag := range task.Deleted {
+			resURL, err := buildImageResourceURL(extURL, repoName, tag.Tag)
+			if err != nil {
+				return nil, err
+			}
+			payload.EventData.Resources = append(payload.EventData.Resources, &model.Resource{
+				Tag:         tag.Tag,
+				Digest:      tag.Digest,
+				ResourceURL: resURL,
+			})
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/goharbor/harbor/src/pkg/retention"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructRetentionPayload(t *testing.T) {
+	execution := &retention.Execution{
+		ID:       1,
+		PolicyID: 2,
+		Trigger:  retention.ExecutionTriggerManual,
+		Status:   retention.ExecutionStatusSucceed,
+	}
+	tasks := []*retention.Task{
+		{
+			Repository: "hello-world",
+			Total:      3,
+			Retained:   1,
+			Deleted: []*retention.DeletedTag{
+				{Tag: "1.0", Digest: "sha256:1"},
+				{Tag: "2.0", Digest: "sha256:2"},
+			},
+		},
+		{
+			Repository: "busybox",
+			Total:      1,
+			Retained:   1,
+		},
+	}
+	payload, err := constructRetentionPayload(&model.RetentionEvent{
+		EventType:   "TAG_RETENTION",
+		ExecutionID: 1,
+		OccurAt:     time.Now(),
+	}, &models.Project{Name: "library"}, execution, tasks, "harbor.com")
+	require.Nil(t, err)
+	assert.Equal(t, retention.ExecutionTriggerManual, payload.Operator)
+
+	summary := payload.EventData.Retention
+	require.NotNil(t, summary)
+	assert.Equal(t, int64(1), summary.ExecutionID)
+	assert.Equal(t, int64(2), summary.PolicyID)
+	assert.Equal(t, retention.ExecutionStatusSucceed, summary.Status)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 2, summary.Retained)
+	assert.Equal(t, 2, summary.Deleted)
+
+	require.Equal(t, 2, len(payload.EventData.Resources))
+	assert.Equal(t, "1.0", payload.EventData.Resources[0].Tag)
+	assert.Equal(t, "harbor.com/library/hello-world:1.0", payload.EventData.Resources[0].ResourceURL)
+	assert.Equal(t, "sha256:2", payload.EventData.Resources[1].Digest)
+}
@@ -1,6 +1,8 @@
 package notification
 
 import (
+	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -143,6 +145,12 @@ func (msc *MockScanAPIController) GetScanLog(uuid string) ([]byte, error) {
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (msc *MockScanAPIController) FollowScanLog(ctx context.Context, uuid string, offset int64, w io.Writer) (bool, error) {
+	args := msc.Called(uuid, offset)
+
+	return args.Bool(0), args.Error(1)
+}
+
 func (msc *MockScanAPIController) HandleJobHooks(trackID string, change *job.StatusChange) error {
 	args := msc.Called(trackID, change)
 
@@ -180,3 +188,9 @@ func (m *MockHTTPHandler) Handle(value interface{}) error {
 func (m *MockHTTPHandler) IsStateful() bool {
 	return false
 }
+
+func (msc *MockScanAPIController) ImportReport(artifact *v1.Artifact, mimeType string, rawReport string) error {
+	args := msc.Called(artifact, mimeType, rawReport)
+
+	return args.Error(0)
+}
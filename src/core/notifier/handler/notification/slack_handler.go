@@ -0,0 +1,293 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// the messages sent to a chat webhook address per minute, and the burst of the messages
+	slackRateLimitPerMinute = 20
+	slackRateLimitBurst     = 10
+)
+
+// slackLimiters are the rate limiters of the chat webhook addresses, a bulk replication or push may trigger
+// hundreds of events in a short time, the messages exceeding the limit are dropped and counted in the next message
+var slackLimiters = &chatLimiters{
+	limiters: map[string]*chatLimiter{},
+	every:    time.Minute / slackRateLimitPerMinute,
+	burst:    slackRateLimitBurst,
+}
+
+// SlackHandler renders the events into the chat messages and sends them to the Slack
+// or the Slack compatible incoming webhooks
+type SlackHandler struct {
+}
+
+// Handle handles the slack event
+func (s *SlackHandler) Handle(value interface{}) error {
+	if value == nil {
+		return errors.New("SlackHandler cannot handle nil value")
+	}
+
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil {
+		return errors.New("invalid notification slack event")
+	}
+
+	return s.process(event)
+}
+
+// IsStateful ...
+func (s *SlackHandler) IsStateful() bool {
+	return false
+}
+
+func (s *SlackHandler) process(event *model.HookEvent) error {
+	allowed, suppressed := slackLimiters.allow(event.Target.Address, time.Now())
+	if !allowed {
+		log.Debugf("the %s event to %s is dropped by the rate limiting", event.EventType, event.Target.Address)
+		return nil
+	}
+
+	msg := renderSlackMessage(event.Payload)
+	if suppressed > 0 {
+		msg.Text = fmt.Sprintf("%s\n_%d more events were suppressed by the rate limiting_", msg.Text, suppressed)
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal from slack message %v failed: %v", msg, err)
+	}
+
+	j := &models.JobData{
+		Metadata: &models.JobMetadata{
+			JobKind: job.KindGeneric,
+		},
+	}
+	j.Name = job.WebhookJob
+	j.Parameters = map[string]interface{}{
+		"payload":          string(payload),
+		"address":          event.Target.Address,
+		"skip_cert_verify": event.Target.SkipCertVerify,
+	}
+	return notification.HookManager.StartHook(event, j)
+}
+
+// slackMessage is the message of the Slack incoming webhook
+type slackMessage struct {
+	Text        string             `json:"text"`
+	Attachments []*slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string        `json:"color,omitempty"`
+	Fields []*slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackTitles are the message texts of the event types
+var slackTitles = map[string]string{
+	notifyModel.EventTypePushImage:            "Image pushed",
+	notifyModel.EventTypePullImage:            "Image pulled",
+	notifyModel.EventTypeDeleteImage:          "Image deleted",
+	notifyModel.EventTypeUploadChart:          "Chart uploaded",
+	notifyModel.EventTypeDeleteChart:          "Chart deleted",
+	notifyModel.EventTypeDownloadChart:        "Chart downloaded",
+	notifyModel.EventTypeScanningCompleted:    "Image scanning completed",
+	notifyModel.EventTypeScanningFailed:       "Image scanning failed",
+	notifyModel.EventTypeProjectQuota:         "Project quota exceeded",
+	notifyModel.EventTypeTestEndpoint:         "Test message from Harbor",
+	notifyModel.EventTypeReplication:          "Replication finished",
+	notifyModel.EventTypeTagRetention:         "Tag retention finished",
+	notifyModel.EventTypeImmutableTagRejected: "Image push rejected by immutable tag rule",
+	notifyModel.EventTypeSystemGC:             "Garbage collection finished",
+}
+
+// renderSlackMessage renders the payload into the text and the fields of repository, tags, operator,
+// the vulnerability summary of the scanning events, the details of the quota events
+// and the summaries of the replication, retention and gc events
+func renderSlackMessage(payload *model.Payload) *slackMessage {
+	title, ok := slackTitles[payload.Type]
+	if !ok {
+		title = payload.Type
+	}
+	msg := &slackMessage{
+		Text: fmt.Sprintf("*[Harbor] %s*", title),
+	}
+
+	data := payload.EventData
+	if data == nil {
+		return msg
+	}
+
+	attachment := &slackAttachment{}
+	if data.Repository != nil {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Repository",
+			Value: data.Repository.RepoFullName,
+			Short: true,
+		})
+	}
+	var tags []string
+	for _, res := range data.Resources {
+		if len(res.Tag) > 0 {
+			tags = append(tags, res.Tag)
+		}
+	}
+	if len(tags) > 0 {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Tag",
+			Value: strings.Join(tags, ", "),
+			Short: true,
+		})
+	}
+	if len(payload.Operator) > 0 {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Operator",
+			Value: payload.Operator,
+			Short: true,
+		})
+	}
+	for _, res := range data.Resources {
+		for _, overview := range res.ScanOverview {
+			summary := nativeReportSummary(overview)
+			if summary == nil {
+				continue
+			}
+			attachment.Fields = append(attachment.Fields, &slackField{
+				Title: "Severity",
+				Value: severitySummary(summary),
+			})
+			attachment.Color = severityColor(summary.Severity)
+		}
+	}
+	if r := data.Replication; r != nil {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Replication",
+			Value: fmt.Sprintf("%s: %s, total %d, succeed %d, failed %d, stopped %d",
+				r.PolicyName, r.Status, r.Total, r.Succeed, r.Failed, r.Stopped),
+		})
+	}
+	if r := data.Retention; r != nil {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Retention",
+			Value: fmt.Sprintf("total %d, retained %d, deleted %d, dry run %t",
+				r.Total, r.Retained, r.Deleted, r.DryRun),
+		})
+	}
+	if gc := data.GC; gc != nil {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Status",
+			Value: gc.Status,
+			Short: true,
+		})
+	}
+	if details, ok := data.Custom["Details"]; ok {
+		attachment.Fields = append(attachment.Fields, &slackField{
+			Title: "Details",
+			Value: details,
+		})
+	}
+
+	if len(attachment.Fields) > 0 {
+		msg.Attachments = []*slackAttachment{attachment}
+	}
+	return msg
+}
+
+// nativeReportSummary converts the scan overview to the native report summary
+func nativeReportSummary(overview interface{}) *vuln.NativeReportSummary {
+	if summary, ok := overview.(*vuln.NativeReportSummary); ok {
+		return summary
+	}
+	data, err := json.Marshal(overview)
+	if err != nil {
+		return nil
+	}
+	summary := &vuln.NativeReportSummary{}
+	if err := json.Unmarshal(data, summary); err != nil || len(summary.Severity) == 0 {
+		return nil
+	}
+	return summary
+}
+
+// severitySummary returns the summary like "High, 10 vulnerabilities (Critical: 0, High: 2, ...), 3 fixable"
+func severitySummary(summary *vuln.NativeReportSummary) string {
+	if summary.Summary == nil || summary.Summary.Total == 0 {
+		return fmt.Sprintf("%s, no vulnerabilities", summary.Severity)
+	}
+	var counts []string
+	for _, severity := range []vuln.Severity{vuln.Critical, vuln.High, vuln.Medium, vuln.Low, vuln.Negligible, vuln.Unknown} {
+		if n := summary.Summary.Summary[severity]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", severity, n))
+		}
+	}
+	return fmt.Sprintf("%s, %d vulnerabilities (%s), %d fixable",
+		summary.Severity, summary.Summary.Total, strings.Join(counts, ", "), summary.Summary.Fixable)
+}
+
+func severityColor(severity vuln.Severity) string {
+	switch {
+	case severity.Code() >= vuln.High.Code():
+		return "danger"
+	case severity.Code() >= vuln.Low.Code():
+		return "warning"
+	default:
+		return "good"
+	}
+}
+
+// chatLimiters holds the rate limiters per chat webhook address
+type chatLimiters struct {
+	sync.Mutex
+	limiters map[string]*chatLimiter
+	every    time.Duration
+	burst    int
+}
+
+// chatLimiter limits the messages to one address and counts the suppressed ones
+type chatLimiter struct {
+	*rate.Limiter
+	suppressed int
+}
+
+// allow returns whether the message to the address is allowed at the time, and the messages suppressed before it
+func (c *chatLimiters) allow(address string, now time.Time) (bool, int) {
+	c.Lock()
+	defer c.Unlock()
+
+	l, ok := c.limiters[address]
+	if !ok {
+		l = &chatLimiter{
+			Limiter: rate.NewLimiter(rate.Every(c.every), c.burst),
+		}
+		c.limiters[address] = l
+	}
+
+	if !l.AllowN(now, 1) {
+		l.suppressed++
+		return false, 0
+	}
+
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return true, suppressed
+}
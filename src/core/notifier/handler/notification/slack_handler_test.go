@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/core/notifier/model"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSlackMessage(t *testing.T) {
+	msg := renderSlackMessage(newTestPayload())
+	assert.Equal(t, "*[Harbor] Image pushed*", msg.Text)
+	require.Equal(t, 1, len(msg.Attachments))
+	fields := msg.Attachments[0].Fields
+	require.Equal(t, 3, len(fields))
+	assert.Equal(t, "library/hello-world", fields[0].Value)
+	assert.Equal(t, "latest", fields[1].Value)
+	assert.Equal(t, "admin", fields[2].Value)
+
+	payload := newTestPayload()
+	payload.Type = notifyModel.EventTypeScanningCompleted
+	payload.EventData.Resources[0].ScanOverview = map[string]interface{}{
+		"application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0": &vuln.NativeReportSummary{
+			Severity: vuln.High,
+			Summary: &vuln.VulnerabilitySummary{
+				Total:   3,
+				Fixable: 1,
+				Summary: vuln.SeveritySummary{vuln.High: 1, vuln.Low: 2},
+			},
+		},
+	}
+	msg = renderSlackMessage(payload)
+	fields = msg.Attachments[0].Fields
+	assert.Equal(t, "Severity", fields[3].Title)
+	assert.Equal(t, "High, 3 vulnerabilities (High: 1, Low: 2), 1 fixable", fields[3].Value)
+	assert.Equal(t, "danger", msg.Attachments[0].Color)
+
+	msg = renderSlackMessage(&model.Payload{Type: notifyModel.EventTypeTestEndpoint})
+	assert.Equal(t, "*[Harbor] Test message from Harbor*", msg.Text)
+	assert.Nil(t, msg.Attachments)
+}
+
+func TestChatLimiters(t *testing.T) {
+	limiters := &chatLimiters{
+		limiters: map[string]*chatLimiter{},
+		every:    time.Hour,
+		burst:    2,
+	}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		allowed, suppressed := limiters.allow("http://chat/a", now)
+		assert.True(t, allowed)
+		assert.Equal(t, 0, suppressed)
+	}
+	allowed, _ := limiters.allow("http://chat/a", now)
+	assert.False(t, allowed)
+	allowed, _ = limiters.allow("http://chat/a", now)
+	assert.False(t, allowed)
+
+	// the addresses are limited separately
+	allowed, _ = limiters.allow("http://chat/b", now)
+	assert.True(t, allowed)
+
+	// the suppressed messages are counted in the next allowed one
+	allowed, suppressed := limiters.allow("http://chat/a", now.Add(time.Hour))
+	assert.True(t, allowed)
+	assert.Equal(t, 2, suppressed)
+}
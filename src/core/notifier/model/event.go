@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/models"
@@ -51,6 +52,29 @@ type QuotaEvent struct {
 	Msg       string
 }
 
+// ReplicationEvent is replication execution finished event data to publish
+type ReplicationEvent struct {
+	EventType   string
+	ExecutionID int64
+	OccurAt     time.Time
+}
+
+// RetentionEvent is tag retention finished event data to publish, it's published once per execution
+type RetentionEvent struct {
+	EventType   string
+	ExecutionID int64
+	OccurAt     time.Time
+}
+
+// GCEvent is system garbage collection finished event data to publish
+type GCEvent struct {
+	EventType string
+	JobID     int64
+	Status    string
+	OccurAt   time.Time
+	Operator  string
+}
+
 // HookEvent is hook related event data to publish
 type HookEvent struct {
 	PolicyID  int64
@@ -72,6 +96,12 @@ type EventData struct {
 	Resources  []*Resource       `json:"resources"`
 	Repository *Repository       `json:"repository"`
 	Custom     map[string]string `json:"custom_attributes,omitempty"`
+	// Replication is the summary of the replication execution of the replication event
+	Replication *Replication `json:"replication,omitempty"`
+	// Retention is the summary of the tag retention of the tag retention event
+	Retention *Retention `json:"retention,omitempty"`
+	// GC is the result of the garbage collection of the system gc event
+	GC *GC `json:"gc,omitempty"`
 }
 
 // Resource describe infos of resource triggered notification
@@ -90,3 +120,39 @@ type Repository struct {
 	RepoFullName string `json:"repo_full_name"`
 	RepoType     string `json:"repo_type"`
 }
+
+// Replication info of the replication event
+type Replication struct {
+	ExecutionID int64  `json:"execution_id"`
+	PolicyID    int64  `json:"policy_id"`
+	PolicyName  string `json:"policy_name"`
+	Trigger     string `json:"trigger"`
+	Status      string `json:"status"`
+	Total       int    `json:"total"`
+	Succeed     int    `json:"succeed"`
+	Failed      int    `json:"failed"`
+	Stopped     int    `json:"stopped"`
+	StartTime   int64  `json:"start_time"`
+	EndTime     int64  `json:"end_time"`
+}
+
+// Retention info of the tag retention event, the deleted tags are in the resources of the event data
+type Retention struct {
+	ExecutionID int64  `json:"execution_id"`
+	PolicyID    int64  `json:"policy_id"`
+	Trigger     string `json:"trigger"`
+	DryRun      bool   `json:"dry_run"`
+	Status      string `json:"status"`
+	Total       int    `json:"total"`
+	Retained    int    `json:"retained"`
+	Deleted     int    `json:"deleted"`
+}
+
+// GC info of the system gc event
+type GC struct {
+	JobID  int64  `json:"job_id"`
+	Status string `json:"status"`
+	// Report is the part of the gc report about the project receiving the event, e.g. the reclaimable
+	// manifests and blobs of dry run
+	Report json.RawMessage `json:"report,omitempty"`
+}
@@ -22,9 +22,19 @@ const (
 	QuotaWarningTopic = "OnQuotaWarning"
 	// QuotaExceedTopic is topic for quota exceeded event
 	QuotaExceedTopic = "OnQuotaExceed"
+	// ReplicationTopic is topic for replication execution finished event
+	ReplicationTopic = "OnReplication"
+	// TagRetentionTopic is topic for tag retention finished event
+	TagRetentionTopic = "OnTagRetention"
+	// ImmutableTagRejectedTopic is topic for the push rejected by immutable tag rules event
+	ImmutableTagRejectedTopic = "OnImmutableTagRejected"
+	// SystemGCTopic is topic for system garbage collection finished event
+	SystemGCTopic = "OnSystemGC"
 
 	// WebhookTopic is topic for sending webhook payload
 	WebhookTopic = "http"
+	// SlackTopic is topic for sending slack message
+	SlackTopic = "slack"
 	// EmailTopic is topic for sending email payload
 	EmailTopic = "email"
 )
@@ -10,16 +10,21 @@ import (
 // Subscribe topics
 func init() {
 	handlersMap := map[string][]notifier.NotificationHandler{
-		model.PushImageTopic:         {&notification.ImagePreprocessHandler{}},
-		model.PullImageTopic:         {&notification.ImagePreprocessHandler{}},
-		model.DeleteImageTopic:       {&notification.ImagePreprocessHandler{}},
-		model.WebhookTopic:           {&notification.HTTPHandler{}},
-		model.UploadChartTopic:       {&notification.ChartPreprocessHandler{}},
-		model.DownloadChartTopic:     {&notification.ChartPreprocessHandler{}},
-		model.DeleteChartTopic:       {&notification.ChartPreprocessHandler{}},
-		model.ScanningCompletedTopic: {&notification.ScanImagePreprocessHandler{}},
-		model.ScanningFailedTopic:    {&notification.ScanImagePreprocessHandler{}},
-		model.QuotaExceedTopic:       {&notification.QuotaPreprocessHandler{}},
+		model.PushImageTopic:            {&notification.ImagePreprocessHandler{}},
+		model.PullImageTopic:            {&notification.ImagePreprocessHandler{}},
+		model.DeleteImageTopic:          {&notification.ImagePreprocessHandler{}},
+		model.WebhookTopic:              {&notification.HTTPHandler{}},
+		model.SlackTopic:                {&notification.SlackHandler{}},
+		model.UploadChartTopic:          {&notification.ChartPreprocessHandler{}},
+		model.DownloadChartTopic:        {&notification.ChartPreprocessHandler{}},
+		model.DeleteChartTopic:          {&notification.ChartPreprocessHandler{}},
+		model.ScanningCompletedTopic:    {&notification.ScanImagePreprocessHandler{}},
+		model.ScanningFailedTopic:       {&notification.ScanImagePreprocessHandler{}},
+		model.QuotaExceedTopic:          {&notification.QuotaPreprocessHandler{}},
+		model.ReplicationTopic:          {&notification.ReplicationPreprocessHandler{}},
+		model.TagRetentionTopic:         {&notification.RetentionPreprocessHandler{}},
+		model.ImmutableTagRejectedTopic: {&notification.ImagePreprocessHandler{}},
+		model.SystemGCTopic:             {&notification.GCPreprocessHandler{}},
 	}
 
 	for t, handlers := range handlersMap {
@@ -16,6 +16,8 @@ package admin
 
 import (
 	"encoding/json"
+	"time"
+
 	"github.com/goharbor/harbor/src/core/service/notifications"
 
 	"github.com/goharbor/harbor/src/common/dao"
@@ -23,6 +25,7 @@ import (
 	job_model "github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/notifier/event"
 	j "github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/scan/api/scan"
 )
@@ -107,4 +110,20 @@ func (h *Handler) HandleAdminJob() {
 	if h.jobName == job.ImageScanAllJob {
 		scan.HandleCheckIn(h.checkIn)
 	}
+
+	if h.jobName == job.ImageGC && (h.status == models.JobFinished || h.status == models.JobError || h.status == models.JobStopped) {
+		e := &event.Event{}
+		metaData := &event.GCMetaData{
+			JobID:   h.id,
+			Status:  h.status,
+			OccurAt: time.Now(),
+		}
+		if err := e.Build(metaData); err == nil {
+			if err := e.Publish(); err != nil {
+				log.Errorf("failed to publish the system gc event: %v", err)
+			}
+		} else {
+			log.Errorf("failed to build the system gc event: %v", err)
+		}
+	}
 }
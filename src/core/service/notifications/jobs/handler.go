@@ -157,6 +157,21 @@ func (h *Handler) HandleReplicationTask() {
 		h.SendInternalServerError(err)
 		return
 	}
+
+	if h.status != models.JobFinished && h.status != models.JobError && h.status != models.JobStopped {
+		return
+	}
+	execution, err := hook.FinishExecution(replication.OperationCtl, h.id)
+	if err != nil {
+		log.Errorf("failed to finish the execution of the replication task %d: %v", h.id, err)
+		return
+	}
+	if execution != nil {
+		publishEvent(&event.ReplicationMetaData{
+			ExecutionID: execution.ID,
+			OccurAt:     time.Now(),
+		})
+	}
 }
 
 // HandleRetentionTask handles the webhook of retention task
@@ -167,11 +182,8 @@ func (h *Handler) HandleRetentionTask() {
 	mgr := &retention.DefaultManager{}
 	// handle checkin
 	if h.checkIn != "" {
-		var retainObj struct {
-			Total    int `json:"total"`
-			Retained int `json:"retained"`
-		}
-		if err := json.Unmarshal([]byte(h.checkIn), &retainObj); err != nil {
+		retainObj := &retention.CheckIn{}
+		if err := json.Unmarshal([]byte(h.checkIn), retainObj); err != nil {
 			log.Errorf("failed to resolve checkin of retention task %d: %v", taskID, err)
 			return
 		}
@@ -179,8 +191,9 @@ func (h *Handler) HandleRetentionTask() {
 			ID:       taskID,
 			Total:    retainObj.Total,
 			Retained: retainObj.Retained,
+			Deleted:  retainObj.Deleted,
 		}
-		if err := mgr.UpdateTask(task, "Total", "Retained"); err != nil {
+		if err := mgr.UpdateTask(task, "Total", "Retained", "Deleted"); err != nil {
 			log.Errorf("failed to update of retention task %d: %v", taskID, err)
 			h.SendInternalServerError(err)
 			return
@@ -194,6 +207,43 @@ func (h *Handler) HandleRetentionTask() {
 		h.SendInternalServerError(err)
 		return
 	}
+
+	if h.status != models.JobFinished && h.status != models.JobError && h.status != models.JobStopped {
+		return
+	}
+	executionID, err := finishRetentionExecution(mgr, taskID)
+	if err != nil {
+		log.Errorf("failed to finish the execution of the retention task %d: %v", taskID, err)
+		return
+	}
+	if executionID > 0 {
+		publishEvent(&event.RetentionMetaData{
+			ExecutionID: executionID,
+			OccurAt:     time.Now(),
+		})
+	}
+}
+
+// finishRetentionExecution marks the execution which the task belongs to as finished if all the tasks
+// of it are finished, the execution ID is returned only to the caller which finishes it, so the
+// execution finished event is published once
+func finishRetentionExecution(mgr retention.Manager, taskID int64) (int64, error) {
+	task, err := mgr.GetTask(taskID)
+	if err != nil {
+		return 0, err
+	}
+	execution, err := mgr.GetExecution(task.ExecutionID)
+	if err != nil {
+		return 0, err
+	}
+	if execution.Status == retention.ExecutionStatusInProgress {
+		return 0, nil
+	}
+	finished, err := mgr.FinishExecution(execution.ID)
+	if err != nil || !finished {
+		return 0, err
+	}
+	return execution.ID, nil
 }
 
 // HandleNotificationJob handles the hook of notification job
@@ -209,3 +259,15 @@ func (h *Handler) HandleNotificationJob() {
 		return
 	}
 }
+
+// publishEvent builds and publishes the notification event, the errors are logged only
+func publishEvent(metaData event.Metadata) {
+	e := &event.Event{}
+	if err := e.Build(metaData); err != nil {
+		log.Errorf("failed to build the event: %v", err)
+		return
+	}
+	if err := e.Publish(); err != nil {
+		log.Errorf("failed to publish the event: %v", err)
+	}
+}
@@ -152,7 +152,8 @@ func (n *NotificationHandler) Post() {
 									"public": strconv.FormatBool(pro.IsPublic()),
 								},
 							},
-							Vtags: []string{tag},
+							Vtags:  []string{tag},
+							Digest: event.Target.Digest,
 						},
 					},
 				}
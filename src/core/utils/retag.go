@@ -21,8 +21,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/common/utils/registry"
 
-	"github.com/docker/distribution/manifest/schema1"
-	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution"
 )
 
 // Retag tags an image to another
@@ -51,8 +50,7 @@ func Retag(srcImage, destImage *models.Image) error {
 		return fmt.Errorf("image %s:%s not found", srcClient.Name, srcImage.Tag)
 	}
 
-	accepted := []string{schema1.MediaTypeManifest, schema2.MediaTypeManifest}
-	digest, mediaType, payload, err := srcClient.PullManifest(srcImage.Tag, accepted)
+	digest, mediaType, payload, err := srcClient.PullManifest(srcImage.Tag, registry.ManifestMediaTypes)
 	if err != nil {
 		return err
 	}
@@ -73,10 +71,15 @@ func Retag(srcImage, destImage *models.Image) error {
 	}
 
 	if !isSameRepo {
-		for _, descriptor := range manifest.References() {
-			err := destClient.MountBlob(descriptor.Digest.String(), srcClient.Name)
-			if err != nil {
-				log.Errorf("mount blob '%s' error: %v", descriptor.Digest.String(), err)
+		if registry.IsManifestList(mediaType) {
+			// the platform specific manifests must exist in the destination repository before the manifest list
+			for _, descriptor := range manifest.References() {
+				if err := copyManifest(srcClient, destClient, descriptor.Digest.String()); err != nil {
+					return err
+				}
+			}
+		} else {
+			if err := mountBlobs(srcClient, destClient, manifest); err != nil {
 				return err
 			}
 		}
@@ -90,6 +93,41 @@ func Retag(srcImage, destImage *models.Image) error {
 	return nil
 }
 
+// copyManifest copies the manifest with the digest and its blobs from the source repository to the destination one
+func copyManifest(srcClient, destClient *registry.Repository, digest string) error {
+	_, mediaType, payload, err := srcClient.PullManifest(digest, registry.ImageManifestMediaTypes)
+	if err != nil {
+		return err
+	}
+
+	manifest, _, err := registry.UnMarshal(mediaType, payload)
+	if err != nil {
+		return err
+	}
+
+	if err := mountBlobs(srcClient, destClient, manifest); err != nil {
+		return err
+	}
+
+	if _, err = destClient.PushManifest(digest, mediaType, payload); err != nil {
+		log.Errorf("push manifest '%s@%s' error: %v", destClient.Name, digest, err)
+		return err
+	}
+
+	return nil
+}
+
+func mountBlobs(srcClient, destClient *registry.Repository, manifest distribution.Manifest) error {
+	for _, descriptor := range manifest.References() {
+		err := destClient.MountBlob(descriptor.Digest.String(), srcClient.Name)
+		if err != nil {
+			log.Errorf("mount blob '%s' error: %v", descriptor.Digest.String(), err)
+			return err
+		}
+	}
+	return nil
+}
+
 func getRepoName(image *models.Image) string {
 	return fmt.Sprintf("%s/%s", image.Project, image.Repo)
 }
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -37,6 +38,16 @@ import (
 const (
 	totalHeaderKey = "Total-Count"
 	nextCursorKey  = "Next-Cursor"
+
+	// Query parameters of following the job log
+	logFollowParam = "follow"
+	logOffsetParam = "offset"
+	// Trailer key of the job status when following the job log
+	jobStatusTrailerKey = "Job-Status"
+	// The interval of checking the new log data
+	logFollowInterval = time.Second
+	// The max duration of one following request, it must be shorter than the write timeout of the server
+	logFollowWindow = 10 * time.Second
 )
 
 // Handler defines approaches to handle the http requests.
@@ -199,17 +210,15 @@ func (dh *DefaultHandler) HandleJobLogReq(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
+	// Follow the log of the running job
+	if follow, _ := strconv.ParseBool(req.URL.Query().Get(logFollowParam)); follow {
+		dh.followJobLog(w, req, jobID)
+		return
+	}
+
 	logData, err := dh.controller.GetJobLogData(jobID)
 	if err != nil {
-		code := http.StatusInternalServerError
-		if errs.IsObjectNotFoundError(err) {
-			code = http.StatusNotFound
-		} else if errs.IsBadRequestError(err) {
-			code = http.StatusBadRequest
-		} else {
-			err = errs.GetJobLogError(err)
-		}
-		dh.handleError(w, req, code, err)
+		dh.handleJobLogError(w, req, err)
 		return
 	}
 
@@ -219,6 +228,98 @@ func (dh *DefaultHandler) HandleJobLogReq(w http.ResponseWriter, req *http.Reque
 	writeDate(w, logData)
 }
 
+// followJobLog streams the log data of the job from the offset as the log grows.
+// The response ends once the job is done or the follow window elapses to respect the
+// write timeout of the server. The latest job status is sent in the trailer, the client
+// can resume following with the offset of the received log data if the job is not done.
+func (dh *DefaultHandler) followJobLog(w http.ResponseWriter, req *http.Request, jobID string) {
+	var offset int64
+	if o := req.URL.Query().Get(logOffsetParam); !utils.IsEmptyStr(o) {
+		v, err := strconv.ParseInt(o, 10, 64)
+		if err != nil || v < 0 {
+			dh.handleError(w, req, http.StatusBadRequest, errors.Errorf("invalid log offset: %s", o))
+			return
+		}
+		offset = v
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		dh.handleError(w, req, http.StatusInternalServerError, errs.GetJobLogError(errors.New("streaming is not supported")))
+		return
+	}
+
+	// Make sure the job exists before streaming
+	stats, err := dh.controller.GetJob(jobID)
+	if err != nil {
+		dh.handleJobLogError(w, req, err)
+		return
+	}
+
+	dh.log(req, http.StatusOK, "")
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Trailer", jobStatusTrailerKey)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+	window := time.After(logFollowWindow)
+
+	for {
+		// Read the log data after getting the status to make sure no log data is missed for the done job
+		data, err := dh.controller.GetJobLogDataFrom(jobID, offset)
+		if err != nil && !errs.IsObjectNotFoundError(err) {
+			// Headers have been sent, just log it
+			logger.Errorf("Follow log of job %s error: %s", jobID, err)
+			return
+		}
+		if len(data) > 0 {
+			if _, err := w.Write(data); err != nil {
+				logger.Errorf("Follow log of job %s error: %s", jobID, err)
+				return
+			}
+			flusher.Flush()
+			offset += int64(len(data))
+		}
+
+		if job.Status(stats.Info.Status).Final() {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-window:
+			w.Header().Set(jobStatusTrailerKey, stats.Info.Status)
+			return
+		case <-req.Context().Done():
+			// Client is gone
+			return
+		}
+
+		if stats, err = dh.controller.GetJob(jobID); err != nil {
+			logger.Errorf("Follow log of job %s error: %s", jobID, err)
+			return
+		}
+	}
+
+	w.Header().Set(jobStatusTrailerKey, stats.Info.Status)
+}
+
+// handleJobLogError handles the error of getting job log
+func (dh *DefaultHandler) handleJobLogError(w http.ResponseWriter, req *http.Request, err error) {
+	code := http.StatusInternalServerError
+	if errs.IsObjectNotFoundError(err) {
+		code = http.StatusNotFound
+	} else if errs.IsBadRequestError(err) {
+		code = http.StatusBadRequest
+	} else {
+		err = errs.GetJobLogError(err)
+	}
+	dh.handleError(w, req, code, err)
+}
+
 // HandlePeriodicExecutions is implementation of method defined in interface 'Handler'
 func (dh *DefaultHandler) HandlePeriodicExecutions(w http.ResponseWriter, req *http.Request) {
 	// Get param
@@ -240,6 +240,43 @@ func (suite *APIHandlerTestSuite) TestGetJobLog() {
 	assert.Equal(suite.T(), "hello log", string(resData))
 }
 
+// TestFollowJobLog ...
+func (suite *APIHandlerTestSuite) TestFollowJobLog() {
+	running := &job.Stats{Info: &job.StatsInfo{JobID: "fake_job_ID", Status: job.RunningStatus.String()}}
+	succeeded := &job.Stats{Info: &job.StatsInfo{JobID: "fake_job_ID", Status: job.SuccessStatus.String()}}
+
+	fc := &fakeController{}
+	fc.On("GetJob", "fake_job_ID").Return(running, nil).Once()
+	fc.On("GetJob", "fake_job_ID").Return(succeeded, nil).Once()
+	fc.On("GetJobLogDataFrom", "fake_job_ID", int64(2)).Return([]byte("llo "), nil)
+	fc.On("GetJobLogDataFrom", "fake_job_ID", int64(6)).Return([]byte("log"), nil)
+	suite.controller = fc
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s", suite.APIAddr, "jobs/fake_job_ID/log?follow=true&offset=2"), nil)
+	require.NoError(suite.T(), err)
+	req.Header.Set(authHeader, fmt.Sprintf("%s %s", secretPrefix, fakeSecret))
+
+	res, err := suite.client.Do(req)
+	require.NoError(suite.T(), err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(res.Body)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 200, res.StatusCode, "expected 200 ok but got %d", res.StatusCode)
+	assert.Equal(suite.T(), "llo log", string(data))
+	assert.Equal(suite.T(), job.SuccessStatus.String(), res.Trailer.Get(jobStatusTrailerKey))
+}
+
+// TestFollowJobLogInvalidOffset ...
+func (suite *APIHandlerTestSuite) TestFollowJobLogInvalidOffset() {
+	suite.controller = &fakeController{}
+
+	_, code := suite.getReq(fmt.Sprintf("%s/%s", suite.APIAddr, "jobs/fake_job_ID/log?follow=true&offset=-1"))
+	assert.Equal(suite.T(), 400, code, "expected 400 bad request but got %d", code)
+}
+
 // TestGetPeriodicExecutionsWithoutQuery ...
 func (suite *APIHandlerTestSuite) TestGetPeriodicExecutionsWithoutQuery() {
 	q := &query.Parameter{
@@ -402,6 +439,10 @@ func (suite *APIHandlerTestSuite) GetJobLogData(jobID string) ([]byte, error) {
 	return suite.controller.GetJobLogData(jobID)
 }
 
+func (suite *APIHandlerTestSuite) GetJobLogDataFrom(jobID string, offset int64) ([]byte, error) {
+	return suite.controller.GetJobLogDataFrom(jobID, offset)
+}
+
 func (suite *APIHandlerTestSuite) GetPeriodicExecutions(periodicJobID string, query *query.Parameter) ([]*job.Stats, int64, error) {
 	return suite.controller.GetPeriodicExecutions(periodicJobID, query)
 }
@@ -460,6 +501,15 @@ func (fc *fakeController) GetJobLogData(jobID string) ([]byte, error) {
 	return args.Get(0).([]byte), nil
 }
 
+func (fc *fakeController) GetJobLogDataFrom(jobID string, offset int64) ([]byte, error) {
+	args := fc.Called(jobID, offset)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]byte), nil
+}
+
 func (fc *fakeController) GetPeriodicExecutions(periodicJobID string, query *query.Parameter) ([]*job.Stats, int64, error) {
 	args := fc.Called(periodicJobID, query)
 	if args.Error(2) != nil {
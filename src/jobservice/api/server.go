@@ -23,6 +23,7 @@ import (
 	"context"
 	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 )
 
 // Server serves the http requests.
@@ -65,7 +66,7 @@ func NewServer(ctx context.Context, router Router, cfg ServerConfig) *Server {
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      http.HandlerFunc(router.ServeHTTP),
+		Handler:      metrics.Instrument("jobservice", http.HandlerFunc(router.ServeHTTP)),
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -112,6 +112,21 @@ func KeyInProgressQueue(namespace string, jobType string, workerPoolID string) s
 	return fmt.Sprintf("%s:%s:inprogress", KeyJobs(namespace, jobType), workerPoolID)
 }
 
+// KeyJobGraph returns the key of the job graph rooted at the specified job
+func KeyJobGraph(namespace string, rootJobID string) string {
+	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "job_graph", rootJobID)
+}
+
+// KeyJobGraphDone returns the key of the set of the finished jobs in the specified job graph
+func KeyJobGraphDone(namespace string, rootJobID string) string {
+	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "job_graph_done", rootJobID)
+}
+
+// KeyJobGraphNode returns the key of the link between the specified job and its job graph
+func KeyJobGraphNode(namespace string, jobID string) string {
+	return fmt.Sprintf("%s%s:%s", KeyNamespacePrefix(namespace), "job_graph_node", jobID)
+}
+
 // KeyWorkerPools returns the key of the worker pool
 func KeyWorkerPools(namespace string) string {
 	return KeyNamespacePrefix(namespace) + "worker_pools"
@@ -271,3 +271,27 @@ return nil`, requeueKeysPerJob)
 func RedisLuaReenqueueScript(jobTypesCount int) *redis.Script {
 	return redis.NewScript(jobTypesCount*requeueKeysPerJob, redisLuaReenqueueJob)
 }
+
+// Used to count the finished child job of the job graph, the job is ignored if it's already counted
+//
+// KEYS[1]: key of job graph
+// KEYS[2]: key of the finished jobs of the job graph
+// ARGV[1]: job ID
+// ARGV[2]: 1 if the job succeeded, otherwise 0
+// ARGV[3]: expire time of the finished jobs
+var graphChildDoneScriptText = `
+if redis.call('sadd', KEYS[2], ARGV[1]) == 0 then
+  return -1
+end
+redis.call('expire', KEYS[2], tonumber(ARGV[3]))
+
+if ARGV[2] == '0' then
+  redis.call('hincrby', KEYS[1], 'failed', 1)
+end
+
+return redis.call('hincrby', KEYS[1], 'pending', -1)
+`
+
+// GraphChildDoneScript is lua script to count the finished child job of the job graph,
+// it returns the number of the pending children or -1 if the job is counted before
+var GraphChildDoneScript = redis.NewScript(2, graphChildDoneScriptText)
@@ -39,6 +39,7 @@ const (
 	jobServiceRedisNamespace             = "JOB_SERVICE_POOL_REDIS_NAMESPACE"
 	jobServiceRedisIdleConnTimeoutSecond = "JOB_SERVICE_POOL_REDIS_CONN_IDLE_TIMEOUT_SECOND"
 	jobServiceAuthSecret                 = "JOBSERVICE_SECRET"
+	jobServiceMetricAddr                 = "JOB_SERVICE_METRIC_ADDR"
 	coreURL                              = "CORE_URL"
 
 	// JobServiceProtocolHTTPS points to the 'https' protocol
@@ -49,6 +50,13 @@ const (
 	// JobServicePoolBackendRedis represents redis backend
 	JobServicePoolBackendRedis = "redis"
 
+	// JobPriorityHigh represents the high priority class of the job kinds
+	JobPriorityHigh = "high"
+	// JobPriorityNormal represents the normal priority class of the job kinds
+	JobPriorityNormal = "normal"
+	// JobPriorityLow represents the low priority class of the job kinds
+	JobPriorityLow = "low"
+
 	// secret of UI
 	uiAuthSecret = "CORE_SECRET"
 
@@ -78,6 +86,9 @@ type Configuration struct {
 
 	// Logger configurations
 	LoggerConfigs []*LoggerConfig `yaml:"loggers,omitempty"`
+
+	// The address the metrics are exposed on, e.g. ":9090", empty means disabled
+	MetricAddr string `yaml:"metric_addr,omitempty"`
 }
 
 // HTTPSConfig keeps additional configurations when using https protocol
@@ -102,6 +113,18 @@ type PoolConfig struct {
 	WorkerCount  uint             `yaml:"workers"`
 	Backend      string           `yaml:"backend"`
 	RedisPoolCfg *RedisPoolConfig `yaml:"redis_pool,omitempty"`
+	// Options of the job kinds, key is the name of the job kind
+	JobOptions map[string]*JobOptions `yaml:"job_options,omitempty"`
+}
+
+// JobOptions keeps the options honored by the worker pool when dequeuing the jobs of one kind.
+type JobOptions struct {
+	// Priority class of the job kind: high, normal or low, the default is normal.
+	// The jobs of the kinds with higher priority are more likely to be dequeued
+	Priority string `yaml:"priority,omitempty"`
+	// MaxConcurrency limits the number of the jobs of the kind running concurrently,
+	// 0 means no limitation other than the worker count
+	MaxConcurrency uint `yaml:"max_concurrency,omitempty"`
 }
 
 // CustomizedSettings keeps the customized settings of logger
@@ -219,6 +242,11 @@ func (c *Configuration) loadEnvs() {
 		}
 	}
 
+	metricAddr := utils.ReadEnv(jobServiceMetricAddr)
+	if !utils.IsEmptyStr(metricAddr) {
+		c.MetricAddr = metricAddr
+	}
+
 	backend := utils.ReadEnv(jobServiceWorkerPoolBackend)
 	if !utils.IsEmptyStr(backend) {
 		if c.PoolConfig == nil {
@@ -327,6 +355,18 @@ func (c *Configuration) validate() error {
 		}
 	}
 
+	for name, options := range c.PoolConfig.JobOptions {
+		if options == nil {
+			continue
+		}
+		switch options.Priority {
+		case "", JobPriorityHigh, JobPriorityNormal, JobPriorityLow:
+		default:
+			return fmt.Errorf("priority of job %s should be %s, %s or %s, but current setting is %s",
+				name, JobPriorityHigh, JobPriorityNormal, JobPriorityLow, options.Priority)
+		}
+	}
+
 	// Job service loggers
 	if len(c.LoggerConfigs) == 0 {
 		return errors.New("missing logger config of job service")
@@ -46,6 +46,17 @@ func (suite *ConfigurationTestSuite) TestConfigLoadingSucceed() {
 	assert.Nil(suite.T(), err, "Load config from yaml file, expect nil error but got error '%s'", err)
 }
 
+// TestConfigInvalidJobPriority ...
+func (suite *ConfigurationTestSuite) TestConfigInvalidJobPriority() {
+	cfg := &Configuration{}
+	err := cfg.Load("../config_test.yml", false)
+	require.Nil(suite.T(), err, "load config from yaml file, expect nil error but got error '%s'", err)
+
+	cfg.PoolConfig.JobOptions["IMAGE_SCAN"].Priority = "urgent"
+	err = cfg.validate()
+	assert.NotNil(suite.T(), err, "validate config with invalid job priority, expect non nil error but got nil")
+}
+
 // TestConfigLoadingWithEnv ...
 func (suite *ConfigurationTestSuite) TestConfigLoadingWithEnv() {
 	err := setENV()
@@ -86,6 +97,7 @@ func (suite *ConfigurationTestSuite) TestConfigLoadingWithEnv() {
 	assert.Equal(suite.T(), "js_secret", GetAuthSecret(), "expect auth secret 'js_secret' but got '%s'", GetAuthSecret())
 	assert.Equal(suite.T(), "core_secret", GetUIAuthSecret(), "expect auth secret 'core_secret' but got '%s'", GetUIAuthSecret())
 	assert.Equal(suite.T(), "core_url", GetCoreURL(), "expect core url 'core_url' but got '%s'", GetCoreURL())
+	assert.Equal(suite.T(), ":9090", cfg.MetricAddr, "expect metric addr ':9090' but got '%s'", cfg.MetricAddr)
 }
 
 // TestDefaultConfig ...
@@ -96,6 +108,11 @@ func (suite *ConfigurationTestSuite) TestDefaultConfig() {
 	redisURL := DefaultConfig.PoolConfig.RedisPoolCfg.RedisURL
 	assert.Equal(suite.T(), "redis://localhost:6379", redisURL, "expect redisURL '%s' but got '%s'", "redis://localhost:6379", redisURL)
 
+	scanOptions := DefaultConfig.PoolConfig.JobOptions["IMAGE_SCAN"]
+	require.NotNil(suite.T(), scanOptions, "expect options of job IMAGE_SCAN but got nil")
+	assert.Equal(suite.T(), JobPriorityLow, scanOptions.Priority, "expect priority '%s' but got '%s'", JobPriorityLow, scanOptions.Priority)
+	assert.Equal(suite.T(), uint(2), scanOptions.MaxConcurrency, "expect max concurrency 2 but got %d", scanOptions.MaxConcurrency)
+
 	jLoggerCount := len(DefaultConfig.JobLoggerConfigs)
 	assert.Equal(suite.T(), 2, jLoggerCount, "expect 2 job loggers configured but got %d", jLoggerCount)
 
@@ -137,6 +154,7 @@ func setENV() error {
 	err = os.Setenv("JOBSERVICE_SECRET", "js_secret")
 	err = os.Setenv("CORE_SECRET", "core_secret")
 	err = os.Setenv("CORE_URL", "core_url")
+	err = os.Setenv("JOB_SERVICE_METRIC_ADDR", ":9090")
 
 	return err
 }
@@ -152,6 +170,7 @@ func unsetENV() error {
 	err = os.Unsetenv("JOB_SERVICE_POOL_REDIS_NAMESPACE")
 	err = os.Unsetenv("JOBSERVICE_SECRET")
 	err = os.Unsetenv("CORE_SECRET")
+	err = os.Unsetenv("JOB_SERVICE_METRIC_ADDR")
 
 	return err
 }
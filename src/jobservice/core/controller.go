@@ -23,6 +23,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/common/query"
 	"github.com/goharbor/harbor/src/jobservice/common/utils"
 	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/graph"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/mgt"
@@ -36,13 +37,16 @@ type basicController struct {
 	backendWorker worker.Interface
 	// Refer the job stats manager
 	manager mgt.Manager
+	// Refer the job graph controller
+	graphCtl graph.Controller
 }
 
 // NewController is constructor of basicController.
-func NewController(backendWorker worker.Interface, mgr mgt.Manager) Interface {
+func NewController(backendWorker worker.Interface, mgr mgt.Manager, graphCtl graph.Controller) Interface {
 	return &basicController{
 		backendWorker: backendWorker,
 		manager:       mgr,
+		graphCtl:      graphCtl,
 	}
 }
 
@@ -63,6 +67,13 @@ func (bc *basicController) LaunchJob(req *job.Request) (res *job.Stats, err erro
 		return nil, errs.BadRequestError(err)
 	}
 
+	// Validate the dependent jobs of the job graph
+	if req.Job.IsGraph() {
+		if err := bc.validGraphReq(req.Job); err != nil {
+			return nil, errs.BadRequestError(err)
+		}
+	}
+
 	// Enqueue job regarding of the kind
 	switch req.Job.Metadata.JobKind {
 	case job.KindScheduled:
@@ -92,6 +103,14 @@ func (bc *basicController) LaunchJob(req *job.Request) (res *job.Stats, err erro
 
 	// Save job stats
 	if err == nil {
+		// Create the job graph before saving the stats to make sure the root job is linked with
+		// the graph before it's done
+		if req.Job.IsGraph() {
+			if err := bc.graphCtl.Create(res.Info.JobID, req.Job); err != nil {
+				return nil, err
+			}
+		}
+
 		if err := bc.manager.SaveJob(res); err != nil {
 			return nil, err
 		}
@@ -141,6 +160,19 @@ func (bc *basicController) GetJobLogData(jobID string) ([]byte, error) {
 	return logData, nil
 }
 
+// GetJobLogDataFrom is used to return the log text data after the offset for the specified job if exists
+func (bc *basicController) GetJobLogDataFrom(jobID string, offset int64) ([]byte, error) {
+	if utils.IsEmptyStr(jobID) {
+		return nil, errs.BadRequestError(errors.New("empty job ID"))
+	}
+
+	if offset < 0 {
+		return nil, errs.BadRequestError(errors.Errorf("invalid log offset: %d", offset))
+	}
+
+	return logger.RetrieveFrom(jobID, offset)
+}
+
 // CheckStatus is implementation of same method in core interface.
 func (bc *basicController) CheckStatus() (*worker.Stats, error) {
 	return bc.backendWorker.Stats()
@@ -173,6 +205,48 @@ func (bc *basicController) GetJobs(q *query.Parameter) ([]*job.Stats, int64, err
 	return bc.manager.GetJobs(q)
 }
 
+// validGraphReq validates the children and join job of the job graph
+func (bc *basicController) validGraphReq(root *job.RequestBody) error {
+	if bc.graphCtl == nil {
+		return errors.New("job graph is not supported")
+	}
+
+	if root.Metadata.JobKind != job.KindGeneric {
+		return errors.Errorf("only %s job can be the root of job graph", job.KindGeneric)
+	}
+
+	deps := make([]*job.RequestBody, 0, len(root.Children)+1)
+	deps = append(deps, root.Children...)
+	if root.Join != nil {
+		deps = append(deps, root.Join)
+	}
+
+	for _, dep := range deps {
+		if err := validJobReq(&job.Request{Job: dep}); err != nil {
+			return err
+		}
+
+		if dep.Metadata.JobKind != job.KindGeneric {
+			return errors.Errorf("only %s job can be the dependent job of job graph: %s", job.KindGeneric, dep.Name)
+		}
+
+		if dep.IsGraph() {
+			return errors.Errorf("nested job graph is not supported: %s", dep.Name)
+		}
+
+		jobType, isKnownJob := bc.backendWorker.IsKnownJob(dep.Name)
+		if !isKnownJob {
+			return errors.Errorf("job with name '%s' is unknown", dep.Name)
+		}
+
+		if err := bc.backendWorker.ValidateJobParameters(jobType, dep.Parameters); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func validJobReq(req *job.Request) error {
 	if req == nil || req.Job == nil {
 		return errors.New("empty job request is not allowed")
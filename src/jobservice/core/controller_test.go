@@ -16,6 +16,7 @@ package core
 import (
 	"github.com/goharbor/harbor/src/jobservice/common/query"
 	"github.com/goharbor/harbor/src/jobservice/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/errs"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/sample"
 	"github.com/goharbor/harbor/src/jobservice/worker"
@@ -30,9 +31,10 @@ import (
 type ControllerTestSuite struct {
 	suite.Suite
 
-	manager *fakeManager
-	worker  *fakeWorker
-	ctl     Interface
+	manager  *fakeManager
+	worker   *fakeWorker
+	graphCtl *fakeGraphController
+	ctl      Interface
 
 	res    *job.Stats
 	jobID  string
@@ -41,7 +43,7 @@ type ControllerTestSuite struct {
 
 // SetupSuite prepares test suite
 func (suite *ControllerTestSuite) SetupSuite() {
-	suite.ctl = NewController(suite, suite)
+	suite.ctl = NewController(suite, suite, suite)
 
 	suite.params = make(job.Parameters)
 	suite.params["name"] = "testing:v1"
@@ -68,6 +70,7 @@ func (suite *ControllerTestSuite) SetupTest() {
 
 	suite.manager = fakeMgr
 
+	suite.graphCtl = &fakeGraphController{}
 }
 
 // TestControllerTestSuite is suite entry for 'go test'
@@ -108,6 +111,51 @@ func (suite *ControllerTestSuite) TestLaunchPeriodicJob() {
 	assert.Equal(suite.T(), suite.jobID, res.Info.JobID, "mismatch job ID")
 }
 
+// TestLaunchJobGraph ...
+func (suite *ControllerTestSuite) TestLaunchJobGraph() {
+	req := createJobReq("Generic")
+	req.Job.Children = []*job.RequestBody{
+		createJobReq("Generic").Job,
+		createJobReq("Generic").Job,
+	}
+	req.Job.Join = createJobReq("Generic").Job
+	req.Job.GraphHook = "http://localhost:9090/graph"
+
+	suite.worker.On("Enqueue", job.SampleJob, suite.params, true, req.Job.StatusHook).Return(suite.res, nil)
+	suite.graphCtl.On("Create", suite.jobID, req.Job).Return(nil)
+
+	res, err := suite.ctl.LaunchJob(req)
+	require.Nil(suite.T(), err, "launch job graph: nil error expected but got %s", err)
+	assert.Equal(suite.T(), suite.jobID, res.Info.JobID, "mismatch job ID")
+	suite.graphCtl.AssertCalled(suite.T(), "Create", suite.jobID, req.Job)
+}
+
+// TestInvalidJobGraph ...
+func (suite *ControllerTestSuite) TestInvalidJobGraph() {
+	req := createJobReq("Scheduled")
+	req.Job.Children = []*job.RequestBody{createJobReq("Generic").Job}
+	_, err := suite.ctl.LaunchJob(req)
+	assert.NotNil(suite.T(), err, "non generic root job: error expected but got nil")
+
+	req.Job.Metadata.JobKind = job.KindGeneric
+	req.Job.Children[0].Metadata.JobKind = job.KindPeriodic
+	_, err = suite.ctl.LaunchJob(req)
+	assert.NotNil(suite.T(), err, "non generic child job: error expected but got nil")
+
+	req.Job.Children[0].Metadata.JobKind = job.KindGeneric
+	req.Job.Children[0].Name = "fake"
+	_, err = suite.ctl.LaunchJob(req)
+	assert.NotNil(suite.T(), err, "unknown child job: error expected but got nil")
+
+	req.Job.Children[0].Name = job.SampleJob
+	req.Job.Join = createJobReq("Generic").Job
+	req.Job.Join.Children = []*job.RequestBody{createJobReq("Generic").Job}
+	_, err = suite.ctl.LaunchJob(req)
+	assert.NotNil(suite.T(), err, "nested job graph: error expected but got nil")
+
+	suite.graphCtl.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
 // TestGetJobStats ...
 func (suite *ControllerTestSuite) TestGetJobStats() {
 	res, err := suite.ctl.GetJob(suite.jobID)
@@ -126,6 +174,15 @@ func (suite *ControllerTestSuite) TestJobActions() {
 	assert.Nil(suite.T(), err, "job action: nil error expected but got %s", err)
 }
 
+// TestGetJobLogDataFrom ...
+func (suite *ControllerTestSuite) TestGetJobLogDataFrom() {
+	_, err := suite.ctl.GetJobLogDataFrom("", 0)
+	assert.True(suite.T(), errs.IsBadRequestError(err), "empty job ID: bad request error expected but got %v", err)
+
+	_, err = suite.ctl.GetJobLogDataFrom(suite.jobID, -1)
+	assert.True(suite.T(), errs.IsBadRequestError(err), "negative offset: bad request error expected but got %v", err)
+}
+
 // TestCheckStatus ...
 func (suite *ControllerTestSuite) TestCheckStatus() {
 	suite.worker.On("Stats").Return(&worker.Stats{
@@ -286,6 +343,15 @@ func (suite *ControllerTestSuite) SaveJob(j *job.Stats) error {
 	return suite.manager.SaveJob(j)
 }
 
+// Implement graph controller interface
+func (suite *ControllerTestSuite) Create(rootJobID string, root *job.RequestBody) error {
+	return suite.graphCtl.Create(rootJobID, root)
+}
+
+func (suite *ControllerTestSuite) JobDone(jobID string, status job.Status) error {
+	return suite.graphCtl.JobDone(jobID, status)
+}
+
 // fake worker
 type fakeWorker struct {
 	mock.Mock
@@ -401,3 +467,16 @@ func (fm *fakeManager) SaveJob(j *job.Stats) error {
 	args := fm.Called(j)
 	return args.Error(0)
 }
+
+// fake graph controller
+type fakeGraphController struct {
+	mock.Mock
+}
+
+func (fg *fakeGraphController) Create(rootJobID string, root *job.RequestBody) error {
+	return fg.Called(rootJobID, root).Error(0)
+}
+
+func (fg *fakeGraphController) JobDone(jobID string, status job.Status) error {
+	return fg.Called(jobID, status).Error(0)
+}
@@ -63,6 +63,10 @@ type Interface interface {
 	// GetJobLogData is used to return the log text data for the specified job if exists
 	GetJobLogData(jobID string) ([]byte, error)
 
+	// GetJobLogDataFrom is used to return the log text data after the offset for the specified job if exists.
+	// It's used to follow the log of the running job.
+	GetJobLogDataFrom(jobID string, offset int64) ([]byte, error)
+
 	// Get the periodic executions for the specified periodic job.
 	// Pagination by query is supported.
 	// The total number is also returned.
@@ -0,0 +1,471 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph coordinates the jobs of the job graphs.
+//
+// A job graph consists of a root job, the child jobs launched after the root job
+// succeeds and an optional join job launched after all the child jobs finish, or
+// only after all of them succeed if the graph requires.
+// The aggregate status of the graph is reported to the graph hook.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/common/rds"
+	"github.com/goharbor/harbor/src/jobservice/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/lcm"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+)
+
+const (
+	// The roles of the jobs in the graph
+	roleRoot  = "root"
+	roleChild = "child"
+	roleJoin  = "join"
+
+	// Keep the graph data as long as the job stats
+	graphDataExpireTime = 7 * 24 * 3600
+)
+
+// Controller coordinates the jobs of the job graphs
+type Controller interface {
+	// Create the graph rooted at the launched job.
+	// It should be called before the stats of the root job are saved.
+	//
+	// rootJobID string      : ID of the launched root job
+	// root *job.RequestBody : request of the root job which contains the children and join job
+	//
+	// Returns:
+	//  error if failed to create
+	Create(rootJobID string, root *job.RequestBody) error
+
+	// JobDone launches the dependent jobs of the job in the graph and reports the
+	// aggregate status of the graph if needed. It's called once the job reaches its final status,
+	// the jobs not belonging to any graph and the repeated calls for the same job are ignored.
+	//
+	// jobID string      : ID of the finished job
+	// status job.Status : final status of the job
+	//
+	// Returns:
+	//  error if failed to handle
+	JobDone(jobID string, status job.Status) error
+}
+
+// basicController is the default implementation of Controller based on redis
+type basicController struct {
+	namespace string
+	pool      *redis.Pool
+	enqueuer  *work.Enqueuer
+	lcmCtl    lcm.Controller
+	callback  job.HookCallback
+}
+
+// NewController is the constructor of basic controller
+func NewController(ns string, pool *redis.Pool, lcmCtl lcm.Controller, callback job.HookCallback) Controller {
+	return &basicController{
+		namespace: ns,
+		pool:      pool,
+		enqueuer:  work.NewEnqueuer(ns, pool),
+		lcmCtl:    lcmCtl,
+		callback:  callback,
+	}
+}
+
+// Create the graph
+func (bc *basicController) Create(rootJobID string, root *job.RequestBody) error {
+	if utils.IsEmptyStr(rootJobID) || root == nil {
+		return errors.New("graph can not be created with empty root job")
+	}
+
+	children, err := json.Marshal(root.Children)
+	if err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+	args := []interface{}{
+		rds.KeyJobGraph(bc.namespace, rootJobID),
+		"children", string(children),
+		"hook", root.GraphHook,
+		"pending", 0,
+		"failed", 0,
+	}
+	if root.Join != nil {
+		join, err := json.Marshal(root.Join)
+		if err != nil {
+			return errors.Wrap(err, "create graph")
+		}
+		args = append(args, "join", string(join))
+		if root.JoinOnSuccess {
+			args = append(args, "join_on_success", 1)
+		}
+	}
+
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err = conn.Send("MULTI"); err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+	if err = conn.Send("HMSET", args...); err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+	if err = conn.Send("EXPIRE", rds.KeyJobGraph(bc.namespace, rootJobID), graphDataExpireTime); err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+	if err = bc.sendLink(conn, rootJobID, rootJobID, roleRoot); err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+
+	if _, err = conn.Do("EXEC"); err != nil {
+		return errors.Wrap(err, "create graph")
+	}
+
+	return nil
+}
+
+// JobDone handles the finished job
+func (bc *basicController) JobDone(jobID string, status job.Status) error {
+	graphID, role, err := bc.getLink(jobID)
+	if err != nil {
+		if err == redis.ErrNil {
+			// Not a job of graph
+			return nil
+		}
+		return errors.Wrap(err, "job done")
+	}
+
+	if role == roleChild {
+		// The child is checked and counted atomically
+		return bc.childDone(graphID, jobID, status == job.SuccessStatus)
+	}
+
+	first, err := bc.markDone(graphID, jobID)
+	if err != nil {
+		return errors.Wrap(err, "job done")
+	}
+	if !first {
+		logger.Debugf("Job %s of graph %s is already done, ignore", jobID, graphID)
+		return nil
+	}
+
+	switch role {
+	case roleRoot:
+		if status != job.SuccessStatus {
+			return bc.report(graphID, status)
+		}
+		return bc.launchChildren(graphID)
+	case roleJoin:
+		return bc.joinDone(graphID, status)
+	default:
+		return errors.Errorf("unknown role %s of job %s in graph %s", role, jobID, graphID)
+	}
+}
+
+// markDone records the finished job in the graph, returns false if it's recorded before
+func (bc *basicController) markDone(graphID string, jobID string) (bool, error) {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	key := rds.KeyJobGraphDone(bc.namespace, graphID)
+	if err := conn.Send("MULTI"); err != nil {
+		return false, err
+	}
+	if err := conn.Send("SADD", key, jobID); err != nil {
+		return false, err
+	}
+	if err := conn.Send("EXPIRE", key, graphDataExpireTime); err != nil {
+		return false, err
+	}
+	values, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return false, err
+	}
+	added, err := redis.Int64(values[0], nil)
+	if err != nil {
+		return false, err
+	}
+
+	return added > 0, nil
+}
+
+// launchChildren launches the children after the root job succeeds
+func (bc *basicController) launchChildren(graphID string) error {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	data, err := redis.Bytes(conn.Do("HGET", rds.KeyJobGraph(bc.namespace, graphID), "children"))
+	if err != nil {
+		return errors.Wrap(err, "launch children")
+	}
+	children := make([]*job.RequestBody, 0)
+	if err := json.Unmarshal(data, &children); err != nil {
+		return errors.Wrap(err, "launch children")
+	}
+
+	if len(children) == 0 {
+		return bc.launchJoin(graphID, job.SuccessStatus)
+	}
+
+	// Set the pending count before launching to make sure the last finished child is recognized
+	if _, err := conn.Do("HSET", rds.KeyJobGraph(bc.namespace, graphID), "pending", len(children)); err != nil {
+		return errors.Wrap(err, "launch children")
+	}
+
+	if err := bc.report(graphID, job.RunningStatus); err != nil {
+		// Just log it
+		logger.Errorf("Failed to report the status of graph %s: %s", graphID, err)
+	}
+
+	for _, child := range children {
+		if err := bc.launch(graphID, roleChild, child); err != nil {
+			logger.Errorf("Failed to launch child job %s of graph %s: %s", child.Name, graphID, err)
+			// Treat as a failed child which has no job ID
+			if er := bc.childDone(graphID, utils.MakeIdentifier(), false); er != nil {
+				logger.Errorf("Failed to handle the failed child job %s of graph %s: %s", child.Name, graphID, er)
+			}
+		}
+	}
+
+	return nil
+}
+
+// childDone counts the finished child, the join job is launched after all the children finish
+// with their aggregate status
+func (bc *basicController) childDone(graphID string, jobID string, succeeded bool) error {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	flag := 0
+	if succeeded {
+		flag = 1
+	}
+	pending, err := redis.Int64(rds.GraphChildDoneScript.Do(conn,
+		rds.KeyJobGraph(bc.namespace, graphID),
+		rds.KeyJobGraphDone(bc.namespace, graphID),
+		jobID,
+		flag,
+		graphDataExpireTime,
+	))
+	if err != nil {
+		return errors.Wrap(err, "child done")
+	}
+	if pending < 0 {
+		logger.Debugf("Child job %s of graph %s is already done, ignore", jobID, graphID)
+		return nil
+	}
+	if pending > 0 {
+		// Waiting for the other children
+		return nil
+	}
+
+	failed, err := bc.failedChildren(graphID)
+	if err != nil {
+		return errors.Wrap(err, "child done")
+	}
+	if failed > 0 {
+		return bc.launchJoin(graphID, job.ErrorStatus)
+	}
+
+	return bc.launchJoin(graphID, job.SuccessStatus)
+}
+
+// joinDone reports the status of the graph after the join job finishes, the graph fails
+// if any child failed even the join job succeeds
+func (bc *basicController) joinDone(graphID string, status job.Status) error {
+	if status != job.SuccessStatus {
+		return bc.report(graphID, status)
+	}
+
+	failed, err := bc.failedChildren(graphID)
+	if err != nil {
+		return errors.Wrap(err, "join done")
+	}
+	if failed > 0 {
+		return bc.report(graphID, job.ErrorStatus)
+	}
+
+	return bc.report(graphID, job.SuccessStatus)
+}
+
+// failedChildren returns the number of the failed children of the graph
+func (bc *basicController) failedChildren(graphID string) (int64, error) {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return redis.Int64(conn.Do("HGET", rds.KeyJobGraph(bc.namespace, graphID), "failed"))
+}
+
+// launchJoin launches the join job with the aggregate status of the children, the status
+// is reported as the one of the graph directly if no join job, or the join job is only
+// launched on success but the children failed
+func (bc *basicController) launchJoin(graphID string, childrenStatus job.Status) error {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	values, err := redis.Values(conn.Do("HMGET", rds.KeyJobGraph(bc.namespace, graphID), "join", "join_on_success"))
+	if err != nil {
+		return errors.Wrap(err, "launch join")
+	}
+	data, err := redis.Bytes(values[0], nil)
+	if err != nil {
+		if err == redis.ErrNil {
+			return bc.report(graphID, childrenStatus)
+		}
+		return errors.Wrap(err, "launch join")
+	}
+	if values[1] != nil && childrenStatus != job.SuccessStatus {
+		return bc.report(graphID, childrenStatus)
+	}
+
+	join := &job.RequestBody{}
+	if err := json.Unmarshal(data, join); err != nil {
+		return errors.Wrap(err, "launch join")
+	}
+	if join.Parameters == nil {
+		join.Parameters = make(job.Parameters)
+	}
+	join.Parameters[job.ParamGraphChildrenStatus] = childrenStatus.String()
+
+	if err := bc.launch(graphID, roleJoin, join); err != nil {
+		logger.Errorf("Failed to launch join job %s of graph %s: %s", join.Name, graphID, err)
+		return bc.report(graphID, job.ErrorStatus)
+	}
+
+	return nil
+}
+
+// launch the job in the graph
+func (bc *basicController) launch(graphID string, role string, req *job.RequestBody) error {
+	var (
+		j   *work.Job
+		err error
+	)
+
+	isUnique := req.Metadata != nil && req.Metadata.IsUnique
+	if isUnique {
+		j, err = bc.enqueuer.EnqueueUnique(req.Name, req.Parameters)
+	} else {
+		j, err = bc.enqueuer.Enqueue(req.Name, req.Parameters)
+	}
+	if err != nil {
+		return err
+	}
+	if j == nil {
+		return errors.Errorf("job '%s' can not be enqueued, please check the job metatdata", req.Name)
+	}
+
+	// Link the job with the graph before saving its stats, the job is not trackable
+	// and won't run until its stats are saved
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	if err = conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err = bc.sendLink(conn, j.ID, graphID, role); err != nil {
+		return err
+	}
+	if _, err := conn.Do("EXEC"); err != nil {
+		return err
+	}
+
+	_, err = bc.lcmCtl.New(&job.Stats{
+		Info: &job.StatsInfo{
+			JobID:       j.ID,
+			JobName:     j.Name,
+			JobKind:     job.KindGeneric,
+			IsUnique:    isUnique,
+			Status:      job.PendingStatus.String(),
+			EnqueueTime: j.EnqueuedAt,
+			UpdateTime:  time.Now().Unix(),
+			RefLink:     fmt.Sprintf("/api/v1/jobs/%s", j.ID),
+			Parameters:  req.Parameters,
+			WebHookURL:  req.StatusHook,
+		},
+	})
+
+	return err
+}
+
+// report the aggregate status of the graph via the graph hook
+func (bc *basicController) report(graphID string, status job.Status) error {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	hookURL, err := redis.String(conn.Do("HGET", rds.KeyJobGraph(bc.namespace, graphID), "hook"))
+	if err != nil && err != redis.ErrNil {
+		return errors.Wrap(err, "report graph status")
+	}
+
+	logger.Infof("Graph %s is %s", graphID, status.String())
+
+	if utils.IsEmptyStr(hookURL) || bc.callback == nil {
+		return nil
+	}
+
+	// The graph is identified by its root job
+	return bc.callback(hookURL, &job.StatusChange{
+		JobID:  graphID,
+		Status: status.String(),
+	})
+}
+
+// sendLink sends the commands of linking the job with the graph to the connection
+func (bc *basicController) sendLink(conn redis.Conn, jobID string, graphID string, role string) error {
+	key := rds.KeyJobGraphNode(bc.namespace, jobID)
+	if err := conn.Send("HMSET", key, "graph", graphID, "role", role); err != nil {
+		return err
+	}
+
+	return conn.Send("EXPIRE", key, graphDataExpireTime)
+}
+
+// getLink returns the graph ID and role of the job
+func (bc *basicController) getLink(jobID string) (string, string, error) {
+	conn := bc.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	values, err := redis.Strings(conn.Do("HMGET", rds.KeyJobGraphNode(bc.namespace, jobID), "graph", "role"))
+	if err != nil {
+		return "", "", err
+	}
+	if len(values) != 2 || utils.IsEmptyStr(values[0]) {
+		return "", "", redis.ErrNil
+	}
+
+	return values[0], values[1], nil
+}
@@ -0,0 +1,312 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/common/rds"
+	"github.com/goharbor/harbor/src/jobservice/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/lcm"
+	"github.com/goharbor/harbor/src/jobservice/tests"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const graphHook = "http://localhost:9090/graph"
+
+// ControllerTestSuite tests functions of job graph controller
+type ControllerTestSuite struct {
+	suite.Suite
+
+	namespace string
+	pool      *redis.Pool
+	cancel    context.CancelFunc
+	ctl       Controller
+
+	lock    *sync.Mutex
+	changes []*job.StatusChange
+}
+
+// SetupSuite prepares test suite
+func (suite *ControllerTestSuite) SetupSuite() {
+	suite.namespace = tests.GiveMeTestNamespace()
+	suite.pool = tests.GiveMeRedisPool()
+	suite.lock = new(sync.Mutex)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.cancel = cancel
+	envCtx := &env.Context{
+		SystemContext: ctx,
+		WG:            new(sync.WaitGroup),
+	}
+	callback := func(hookURL string, change *job.StatusChange) error {
+		if hookURL == graphHook {
+			suite.lock.Lock()
+			defer suite.lock.Unlock()
+			suite.changes = append(suite.changes, change)
+		}
+		return nil
+	}
+
+	lcmCtl := lcm.NewController(envCtx, suite.namespace, suite.pool, callback)
+	suite.ctl = NewController(suite.namespace, suite.pool, lcmCtl, callback)
+}
+
+// SetupTest clears the reported status changes
+func (suite *ControllerTestSuite) SetupTest() {
+	suite.lock.Lock()
+	defer suite.lock.Unlock()
+	suite.changes = nil
+}
+
+// TearDownSuite clears test suite
+func (suite *ControllerTestSuite) TearDownSuite() {
+	suite.cancel()
+
+	conn := suite.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = tests.ClearAll(suite.namespace, conn)
+}
+
+// TestControllerTestSuite is entry of go test
+func TestControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ControllerTestSuite))
+}
+
+// TestGraphSuccess tests the job graph whose jobs are all succeeded
+func (suite *ControllerTestSuite) TestGraphSuccess() {
+	rootID := suite.createGraph(2, true)
+
+	err := suite.ctl.JobDone(rootID, job.SuccessStatus)
+	require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+	suite.assertChanges(rootID, job.RunningStatus)
+
+	children := suite.nodes(rootID, roleChild)
+	require.Equal(suite.T(), 2, len(children), "expected 2 launched children but got %d", len(children))
+	for _, child := range children {
+		t, err := suite.ctl.(*basicController).lcmCtl.Track(child)
+		require.NoError(suite.T(), err, "track child job: nil error expected but got %s", err)
+		assert.Equal(suite.T(), job.PendingStatus.String(), t.Job().Info.Status)
+	}
+
+	err = suite.ctl.JobDone(children[0], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	assert.Equal(suite.T(), 0, len(suite.nodes(rootID, roleJoin)), "join job should wait for all the children")
+
+	err = suite.ctl.JobDone(children[1], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	joins := suite.nodes(rootID, roleJoin)
+	require.Equal(suite.T(), 1, len(joins), "expected 1 launched join job but got %d", len(joins))
+
+	err = suite.ctl.JobDone(joins[0], job.SuccessStatus)
+	require.NoError(suite.T(), err, "join job done: nil error expected but got %s", err)
+	suite.assertChanges(rootID, job.RunningStatus, job.SuccessStatus)
+}
+
+// TestGraphWithoutJoin tests the job graph without join job
+func (suite *ControllerTestSuite) TestGraphWithoutJoin() {
+	rootID := suite.createGraph(1, false)
+
+	err := suite.ctl.JobDone(rootID, job.SuccessStatus)
+	require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+
+	children := suite.nodes(rootID, roleChild)
+	require.Equal(suite.T(), 1, len(children), "expected 1 launched child but got %d", len(children))
+
+	err = suite.ctl.JobDone(children[0], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	suite.assertChanges(rootID, job.RunningStatus, job.SuccessStatus)
+}
+
+// TestChildFailed tests the job graph with failed child, the join job is launched with the aggregate status
+func (suite *ControllerTestSuite) TestChildFailed() {
+	rootID := suite.createGraph(2, true)
+
+	err := suite.ctl.JobDone(rootID, job.SuccessStatus)
+	require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+
+	children := suite.nodes(rootID, roleChild)
+	require.Equal(suite.T(), 2, len(children), "expected 2 launched children but got %d", len(children))
+
+	err = suite.ctl.JobDone(children[0], job.ErrorStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	err = suite.ctl.JobDone(children[1], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+
+	joins := suite.nodes(rootID, roleJoin)
+	require.Equal(suite.T(), 1, len(joins), "expected 1 launched join job but got %d", len(joins))
+	t, err := suite.ctl.(*basicController).lcmCtl.Track(joins[0])
+	require.NoError(suite.T(), err, "track join job: nil error expected but got %s", err)
+	assert.Equal(suite.T(), job.ErrorStatus.String(), t.Job().Info.Parameters[job.ParamGraphChildrenStatus])
+
+	// The graph fails even the join job succeeds
+	err = suite.ctl.JobDone(joins[0], job.SuccessStatus)
+	require.NoError(suite.T(), err, "join job done: nil error expected but got %s", err)
+	suite.assertChanges(rootID, job.RunningStatus, job.ErrorStatus)
+}
+
+// TestChildFailedJoinOnSuccess tests the job graph with failed child whose join job is only launched on success
+func (suite *ControllerTestSuite) TestChildFailedJoinOnSuccess() {
+	root := suite.jobReq()
+	root.Children = []*job.RequestBody{suite.jobReq(), suite.jobReq()}
+	root.Join = suite.jobReq()
+	root.JoinOnSuccess = true
+	root.GraphHook = graphHook
+	rootID := utils.MakeIdentifier()
+	require.NoError(suite.T(), suite.ctl.Create(rootID, root))
+
+	err := suite.ctl.JobDone(rootID, job.SuccessStatus)
+	require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+
+	children := suite.nodes(rootID, roleChild)
+	require.Equal(suite.T(), 2, len(children), "expected 2 launched children but got %d", len(children))
+
+	err = suite.ctl.JobDone(children[0], job.ErrorStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	err = suite.ctl.JobDone(children[1], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+
+	assert.Equal(suite.T(), 0, len(suite.nodes(rootID, roleJoin)), "join job should not be launched")
+	suite.assertChanges(rootID, job.RunningStatus, job.ErrorStatus)
+}
+
+// TestJobDoneRepeated tests the repeated calls for the same jobs are ignored
+func (suite *ControllerTestSuite) TestJobDoneRepeated() {
+	rootID := suite.createGraph(2, true)
+
+	for i := 0; i < 2; i++ {
+		err := suite.ctl.JobDone(rootID, job.SuccessStatus)
+		require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+	}
+	children := suite.nodes(rootID, roleChild)
+	require.Equal(suite.T(), 2, len(children), "expected 2 launched children but got %d", len(children))
+
+	// The first child done twice doesn't count as all the children done
+	for i := 0; i < 2; i++ {
+		err := suite.ctl.JobDone(children[0], job.SuccessStatus)
+		require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	}
+	assert.Equal(suite.T(), 0, len(suite.nodes(rootID, roleJoin)), "join job should wait for all the children")
+
+	err := suite.ctl.JobDone(children[1], job.SuccessStatus)
+	require.NoError(suite.T(), err, "child job done: nil error expected but got %s", err)
+	joins := suite.nodes(rootID, roleJoin)
+	require.Equal(suite.T(), 1, len(joins), "expected 1 launched join job but got %d", len(joins))
+
+	for i := 0; i < 2; i++ {
+		err = suite.ctl.JobDone(joins[0], job.SuccessStatus)
+		require.NoError(suite.T(), err, "join job done: nil error expected but got %s", err)
+	}
+	suite.assertChanges(rootID, job.RunningStatus, job.SuccessStatus)
+}
+
+// TestRootFailed tests the job graph with failed root job
+func (suite *ControllerTestSuite) TestRootFailed() {
+	rootID := suite.createGraph(2, true)
+
+	err := suite.ctl.JobDone(rootID, job.StoppedStatus)
+	require.NoError(suite.T(), err, "root job done: nil error expected but got %s", err)
+
+	assert.Equal(suite.T(), 0, len(suite.nodes(rootID, roleChild)), "children should not be launched")
+	suite.assertChanges(rootID, job.StoppedStatus)
+}
+
+// TestNonGraphJob tests the job not belonging to any graph
+func (suite *ControllerTestSuite) TestNonGraphJob() {
+	err := suite.ctl.JobDone(utils.MakeIdentifier(), job.SuccessStatus)
+	assert.NoError(suite.T(), err, "non graph job done: nil error expected but got %s", err)
+	suite.assertChanges("")
+
+	err = suite.ctl.Create("", &job.RequestBody{})
+	assert.Error(suite.T(), err, "create graph with empty root: error expected but got nil")
+}
+
+// createGraph creates the job graph with the specified number of children
+func (suite *ControllerTestSuite) createGraph(children int, withJoin bool) string {
+	root := suite.jobReq()
+	for i := 0; i < children; i++ {
+		root.Children = append(root.Children, suite.jobReq())
+	}
+	if withJoin {
+		root.Join = suite.jobReq()
+	}
+	root.GraphHook = graphHook
+
+	rootID := utils.MakeIdentifier()
+	err := suite.ctl.Create(rootID, root)
+	require.NoError(suite.T(), err, "create graph: nil error expected but got %s", err)
+
+	return rootID
+}
+
+// jobReq returns the request of the sample job
+func (suite *ControllerTestSuite) jobReq() *job.RequestBody {
+	return &job.RequestBody{
+		Name: job.SampleJob,
+		Parameters: job.Parameters{
+			"image": "testing:v1",
+		},
+		Metadata: &job.Metadata{
+			JobKind: job.KindGeneric,
+		},
+	}
+}
+
+// nodes returns the IDs of the jobs with the specified role in the graph
+func (suite *ControllerTestSuite) nodes(graphID string, role string) []string {
+	conn := suite.pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	prefix := rds.KeyJobGraphNode(suite.namespace, "")
+	keys, err := redis.Strings(conn.Do("KEYS", prefix+"*"))
+	require.NoError(suite.T(), err, "list graph nodes: nil error expected but got %s", err)
+
+	ids := make([]string, 0)
+	for _, key := range keys {
+		values, err := redis.Strings(conn.Do("HMGET", key, "graph", "role"))
+		require.NoError(suite.T(), err, "get graph node: nil error expected but got %s", err)
+		if values[0] == graphID && values[1] == role {
+			ids = append(ids, strings.TrimPrefix(key, prefix))
+		}
+	}
+
+	return ids
+}
+
+// assertChanges checks the reported status changes of the graph
+func (suite *ControllerTestSuite) assertChanges(graphID string, statuses ...job.Status) {
+	suite.lock.Lock()
+	defer suite.lock.Unlock()
+
+	require.Equal(suite.T(), len(statuses), len(suite.changes), "mismatch number of reported status changes")
+	for i, status := range statuses {
+		assert.Equal(suite.T(), graphID, suite.changes[i].JobID)
+		assert.Equal(suite.T(), status.String(), suite.changes[i].Status)
+		assert.Nil(suite.T(), suite.changes[i].Metadata, "graph status change should not carry job stats")
+	}
+}
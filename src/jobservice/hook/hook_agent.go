@@ -229,6 +229,11 @@ func (ba *basicAgent) reSend() error {
 		return err
 	}
 
+	// Events without job stats (e.g: the aggregate status of job graph) have nothing to check
+	if evt.Data.Metadata == nil {
+		return ba.Trigger(evt)
+	}
+
 	// Args for executing script
 	args := []interface{}{
 		rds.KeyJobStats(ba.namespace, evt.Data.JobID),
@@ -281,6 +286,11 @@ func (ba *basicAgent) ack(evt *Event) error {
 		}
 	}()
 
+	// No job stats to ack
+	if evt.Data.Metadata == nil {
+		return nil
+	}
+
 	k := rds.KeyJobStats(ba.namespace, evt.Data.JobID)
 	k2 := rds.KeyJobTrackInProgress(ba.namespace)
 	reply, err := redis.String(rds.HookAckScript.Do(
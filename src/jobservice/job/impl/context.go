@@ -183,13 +183,13 @@ func createLoggers(jobID string) (logger.Interface, error) {
 	lOptions := make([]logger.Option, 0)
 	for _, lc := range config.DefaultConfig.JobLoggerConfigs {
 		// For running job, the depth should be 5
-		if lc.Name == logger.NameFile || lc.Name == logger.NameStdOutput || lc.Name == logger.NameDB {
+		if lc.Name == logger.NameFile || lc.Name == logger.NameStdOutput || lc.Name == logger.NameDB || lc.Name == logger.NameS3 {
 			if lc.Settings == nil {
 				lc.Settings = map[string]interface{}{}
 			}
 			lc.Settings["depth"] = 5
 		}
-		if lc.Name == logger.NameFile || lc.Name == logger.NameDB {
+		if lc.Name == logger.NameFile || lc.Name == logger.NameDB || lc.Name == logger.NameS3 {
 			// Need extra param
 			fSettings := map[string]interface{}{}
 			for k, v := range lc.Settings {
@@ -200,8 +200,8 @@ func createLoggers(jobID string) (logger.Interface, error) {
 				// Append file name param
 				fSettings["filename"] = fmt.Sprintf("%s.log", jobID)
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
-			} else { // DB Logger
-				// Append DB key
+			} else { // DB or S3 Logger
+				// Append DB key or S3 object name
 				fSettings["key"] = jobID
 				lOptions = append(lOptions, logger.BackendOption(lc.Name, lc.Level, fSettings))
 			}
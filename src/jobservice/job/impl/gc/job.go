@@ -29,6 +29,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/pkg/types"
+	"github.com/goharbor/harbor/src/registryctl/api"
 	"github.com/goharbor/harbor/src/registryctl/client"
 )
 
@@ -48,6 +49,7 @@ type GarbageCollector struct {
 	cfgMgr            *config.CfgManager
 	CoreURL           string
 	redisURL          string
+	adminJobID        int64
 }
 
 // MaxFails implements the interface in job/Interface
@@ -62,7 +64,8 @@ func (gc *GarbageCollector) ShouldRetry() bool {
 
 // Validate implements the interface in job/Interface
 func (gc *GarbageCollector) Validate(params job.Parameters) error {
-	return nil
+	_, err := parseOptions(params)
+	return err
 }
 
 // Run implements the interface in job/Interface
@@ -70,6 +73,16 @@ func (gc *GarbageCollector) Run(ctx job.Context, params job.Parameters) error {
 	if err := gc.init(ctx, params); err != nil {
 		return err
 	}
+	opts, err := parseOptions(params)
+	if err != nil {
+		return err
+	}
+	if opts.dryRun {
+		return gc.runDryRun(opts)
+	}
+	if opts.online {
+		return gc.runOnline(opts)
+	}
 	readOnlyCur, err := gc.getReadOnly()
 	if err != nil {
 		return err
@@ -85,7 +98,17 @@ func (gc *GarbageCollector) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 	gc.logger.Infof("start to run gc in job.")
-	gcr, err := gc.registryCtlClient.StartGC()
+	deleteUntagged, err := gc.canDeleteUntagged(opts)
+	if err != nil {
+		return err
+	}
+	if deleteUntagged {
+		// no push happens in read only mode, so all the untagged manifests can be deleted
+		if err := gc.deleteUntaggedManifests(time.Now()); err != nil {
+			return err
+		}
+	}
+	gcr, err := gc.registryCtlClient.StartGC(&api.GCOptions{DeleteUntagged: deleteUntagged})
 	if err != nil {
 		gc.logger.Errorf("failed to get gc result: %v", err)
 		return err
@@ -116,6 +139,14 @@ func (gc *GarbageCollector) init(ctx job.Context, params job.Parameters) error {
 	configURL := gc.CoreURL + common.CoreConfigPath
 	gc.cfgMgr = config.NewRESTCfgManager(configURL, secret)
 	gc.redisURL = params["redis_url_reg"].(string)
+	// the admin job ID is appended by core as string
+	if v, ok := params["admin_job_id"].(string); ok {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid admin job ID %s: %v", v, err)
+		}
+		gc.adminJobID = id
+	}
 	return nil
 }
 
@@ -170,20 +201,34 @@ func (gc *GarbageCollector) cleanCache() error {
 }
 
 func delKeys(con redis.Conn, pattern string) error {
+	keys, err := scanKeys(con, pattern)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		_, err := con.Do("DEL", key)
+		if err != nil {
+			return fmt.Errorf("failed to clean registry cache %v", err)
+		}
+	}
+	return nil
+}
+
+func scanKeys(con redis.Conn, pattern string) ([]string, error) {
 	iter := 0
 	keys := make([]string, 0)
 	for {
 		arr, err := redis.Values(con.Do("SCAN", iter, "MATCH", pattern))
 		if err != nil {
-			return fmt.Errorf("error retrieving '%s' keys", pattern)
+			return nil, fmt.Errorf("error retrieving '%s' keys", pattern)
 		}
 		iter, err = redis.Int(arr[0], nil)
 		if err != nil {
-			return fmt.Errorf("unexpected type for Int, got type %T", err)
+			return nil, fmt.Errorf("unexpected type for Int, got type %T", err)
 		}
 		k, err := redis.Strings(arr[1], nil)
 		if err != nil {
-			return fmt.Errorf("converts an array command reply to a []string %v", err)
+			return nil, fmt.Errorf("converts an array command reply to a []string %v", err)
 		}
 		keys = append(keys, k...)
 
@@ -191,13 +236,7 @@ func delKeys(con redis.Conn, pattern string) error {
 			break
 		}
 	}
-	for _, key := range keys {
-		_, err := con.Do("DEL", key)
-		if err != nil {
-			return fmt.Errorf("failed to clean registry cache %v", err)
-		}
-	}
-	return nil
+	return keys, nil
 }
 
 func (gc *GarbageCollector) ensureQuota() error {
@@ -0,0 +1,263 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/jobservice/job"
+)
+
+const (
+	// the parameter to run the gc in online mode which doesn't set the whole system to read only
+	paramOnline = "online"
+	// the parameter to only report the reclaimable manifests and blobs without deleting them
+	paramDryRun = "dry_run"
+	// the parameter to delete the manifests which are not referenced by any tag
+	paramDeleteUntagged = "delete_untagged"
+	// the parameter of the safety window in hours, the blobs created in the window are not deleted,
+	// this is to protect the blobs uploaded but not referenced by the manifests yet
+	paramTimeWindow = "time_window"
+
+	defaultTimeWindow = 2
+	onlineBatchSize   = 100
+	// the records of the blobs referenced by the requests in progress older than it are left by the interrupted requests
+	pendingReferenceTimeout = time.Hour
+)
+
+// options are the options of the gc parsed from the job parameters
+type options struct {
+	online         bool
+	dryRun         bool
+	deleteUntagged bool
+	timeWindow     time.Duration
+}
+
+func parseOptions(params job.Parameters) (*options, error) {
+	// the untagged manifests are deleted by default as what the gc always did
+	opts := &options{
+		deleteUntagged: true,
+		timeWindow:     defaultTimeWindow * time.Hour,
+	}
+
+	for name, opt := range map[string]*bool{
+		paramOnline:         &opts.online,
+		paramDryRun:         &opts.dryRun,
+		paramDeleteUntagged: &opts.deleteUntagged,
+	} {
+		if v, ok := params[name]; ok {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("invalid parameter %s: %v, bool is required", name, v)
+			}
+			*opt = b
+		}
+	}
+
+	if v, ok := params[paramTimeWindow]; ok {
+		var hours float64
+		switch w := v.(type) {
+		case float64:
+			hours = w
+		case int:
+			hours = float64(w)
+		case int64:
+			hours = float64(w)
+		default:
+			return nil, fmt.Errorf("invalid parameter %s: %v, number is required", paramTimeWindow, v)
+		}
+		if hours < 0 {
+			return nil, fmt.Errorf("invalid parameter %s: %v, it must not be negative", paramTimeWindow, v)
+		}
+		opts.timeWindow = time.Duration(hours * float64(time.Hour))
+	}
+
+	return opts, nil
+}
+
+// runOnline deletes the blobs which are not referenced by any artifact in batches without setting the system to read only,
+// only the pushes referencing the blobs being deleted are rejected
+func (gc *GarbageCollector) runOnline(opts *options) error {
+	if err := gc.registryCtlClient.Health(); err != nil {
+		gc.logger.Errorf("failed to start online gc as registry controller is unreachable: %v", err)
+		return err
+	}
+
+	// the blobs left by the previous interrupted run
+	if err := dao.ResetBlobStatus(); err != nil {
+		gc.logger.Errorf("failed to reset the status of blobs: %v", err)
+		return err
+	}
+	if err := dao.DeleteStalePendingBlobReferences(time.Now().Add(-pendingReferenceTimeout)); err != nil {
+		gc.logger.Errorf("failed to delete the stale pending references of blobs: %v", err)
+		return err
+	}
+
+	con, err := redis.DialURL(
+		gc.redisURL,
+		redis.DialConnectTimeout(dialConnectionTimeout),
+		redis.DialReadTimeout(dialReadTimeout),
+		redis.DialWriteTimeout(dialWriteTimeout),
+	)
+	if err != nil {
+		gc.logger.Errorf("failed to connect to redis %v", err)
+		return err
+	}
+	defer con.Close()
+
+	before := time.Now().Add(-opts.timeWindow)
+	gc.logger.Infof("start to run online gc in job, the blobs created after %s are skipped.", before)
+
+	if opts.deleteUntagged {
+		// the blobs of the untagged manifests become unreferenced and are deleted in the same run
+		if err := gc.deleteUntaggedManifests(before); err != nil {
+			return err
+		}
+	}
+
+	var (
+		lastID         int64
+		deleted, freed int64
+		failed         int
+	)
+	for {
+		blobs, err := dao.ListUnreferencedBlobs(lastID, before, onlineBatchSize)
+		if err != nil {
+			gc.logger.Errorf("failed to list the unreferenced blobs: %v", err)
+			return err
+		}
+		if len(blobs) == 0 {
+			break
+		}
+		lastID = blobs[len(blobs)-1].ID
+
+		var marked []*models.Blob
+		for _, blob := range blobs {
+			ok, err := dao.MarkBlobDeleting(blob.Digest)
+			if err != nil {
+				gc.logger.Errorf("failed to mark the blob %s as deleting: %v", blob.Digest, err)
+				failed++
+				continue
+			}
+			if !ok {
+				// the blob is referenced after listed
+				continue
+			}
+			marked = append(marked, blob)
+		}
+
+		removed := gc.deleteBlobs(con, marked)
+		for _, blob := range removed {
+			deleted++
+			freed += blob.Size
+		}
+		failed += len(marked) - len(removed)
+	}
+
+	gc.logger.Infof("online gc results: %d blobs deleted, %d bytes freed, %d blobs failed.", deleted, freed, failed)
+
+	if err := gc.ensureQuota(); err != nil {
+		gc.logger.Warningf("failed to align quota data in gc job, with error: %v", err)
+	}
+	gc.logger.Infof("success to run online gc in job.")
+	return nil
+}
+
+// deleteBlobs deletes the blobs marked as deleting from the storage, the cache of the registry and the database,
+// the status of the blob is reset when failed to delete it, returns the blobs deleted
+func (gc *GarbageCollector) deleteBlobs(con redis.Conn, blobs []*models.Blob) []*models.Blob {
+	var removed []*models.Blob
+	for _, blob := range blobs {
+		// the pushes referencing the blob are rejected from now, the ones which passed the check before it was marked
+		// are recorded as pending references until their artifacts are saved, so both are checked here
+		referenced, err := dao.IsBlobReferenced(blob.Digest)
+		if err != nil {
+			gc.logger.Errorf("failed to check the references of the blob %s: %v", blob.Digest, err)
+			gc.resetBlob(blob)
+			continue
+		}
+		if referenced {
+			gc.resetBlob(blob)
+			continue
+		}
+
+		if err := gc.registryCtlClient.DeleteBlob(blob.Digest); err != nil {
+			gc.logger.Errorf("failed to delete the blob %s from storage: %v", blob.Digest, err)
+			gc.resetBlob(blob)
+			continue
+		}
+		removed = append(removed, blob)
+	}
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// the cache must be cleaned before the status of the blobs is gone,
+	// otherwise the registry may think the blobs exist and skip the uploads
+	if err := gc.cleanBlobsCache(con, removed); err != nil {
+		gc.logger.Errorf("failed to clean the registry cache of the blobs: %v", err)
+	}
+
+	var deleted []*models.Blob
+	for _, blob := range removed {
+		if err := dao.DeleteBlobAndProjectBlobs(blob); err != nil {
+			gc.logger.Errorf("failed to delete the blob %s from database: %v", blob.Digest, err)
+			gc.resetBlob(blob)
+			continue
+		}
+		gc.logger.Debugf("blob %s deleted", blob.Digest)
+		deleted = append(deleted, blob)
+	}
+	return deleted
+}
+
+func (gc *GarbageCollector) resetBlob(blob *models.Blob) {
+	if err := dao.ResetBlobStatus(blob.Digest); err != nil {
+		gc.logger.Errorf("failed to reset the status of the blob %s: %v", blob.Digest, err)
+	}
+}
+
+// cleanBlobsCache cleans the registry cache of the blobs, the keys are like:
+// 1) "blobs::sha256:1a6fd470b9ce10849be79e99529a88371dff60c60aab424c077007f6979b4812"
+// 2) "repository::library/hello-world::blobs::sha256:4ab4c602aa5eed5528a6620ff18a1dc4faef0e1ab3a5eddeddb410714478c67f"
+func (gc *GarbageCollector) cleanBlobsCache(con redis.Conn, blobs []*models.Blob) error {
+	digests := map[string]bool{}
+	for _, blob := range blobs {
+		digests[blob.Digest] = true
+		if _, err := con.Do("DEL", "blobs::"+blob.Digest); err != nil {
+			return err
+		}
+	}
+
+	keys, err := scanKeys(con, repoPrefix+"::blobs::*")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if !digests[key[strings.LastIndex(key, "::")+2:]] {
+			continue
+		}
+		if _, err := con.Do("DEL", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts, err := parseOptions(job.Parameters{"redis_url_reg": "redis://redis:6379/1"})
+	require.Nil(t, err)
+	assert.False(t, opts.online)
+	assert.True(t, opts.deleteUntagged)
+	assert.Equal(t, 2*time.Hour, opts.timeWindow)
+
+	opts, err = parseOptions(job.Parameters{paramOnline: true, paramTimeWindow: float64(0.5)})
+	require.Nil(t, err)
+	assert.True(t, opts.online)
+	assert.Equal(t, 30*time.Minute, opts.timeWindow)
+
+	opts, err = parseOptions(job.Parameters{paramDryRun: true, paramDeleteUntagged: true})
+	require.Nil(t, err)
+	assert.True(t, opts.dryRun)
+	assert.True(t, opts.deleteUntagged)
+	assert.False(t, opts.online)
+
+	_, err = parseOptions(job.Parameters{paramOnline: "true"})
+	assert.NotNil(t, err)
+
+	_, err = parseOptions(job.Parameters{paramTimeWindow: -1})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,160 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// runDryRun walks the same data as the gc without deleting anything,
+// the reclaimable manifests and blobs are saved as the report of the admin job
+func (gc *GarbageCollector) runDryRun(opts *options) error {
+	gc.logger.Infof("start to run gc dry run in job.")
+
+	report, err := gc.buildReport(opts)
+	if err != nil {
+		gc.logger.Errorf("failed to build the gc report: %v", err)
+		return err
+	}
+	report.DryRun = true
+	report.EndTime = time.Now()
+
+	if err := gc.saveReport(report); err != nil {
+		return err
+	}
+
+	gc.logger.Infof("GC dry run results: %d untagged manifests, %d orphan blobs, %d bytes reclaimable.",
+		report.TotalManifests, report.TotalBlobs, report.TotalSize)
+	gc.logger.Infof("success to run gc dry run in job.")
+	return nil
+}
+
+// buildReport collects the untagged manifests and the blobs not referenced by any artifact per project,
+// the manifests and blobs created in the time window are skipped in online mode as the online gc does
+func (gc *GarbageCollector) buildReport(opts *options) (*models.GCReport, error) {
+	report := &models.GCReport{
+		StartTime: time.Now(),
+	}
+
+	before := report.StartTime
+	if opts.online {
+		before = before.Add(-opts.timeWindow)
+	}
+
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		return nil, err
+	}
+	projectReports := map[int64]*models.GCProjectReport{}
+	getProjectReport := func(projectID int64) *models.GCProjectReport {
+		pr, ok := projectReports[projectID]
+		if !ok {
+			// the blobs which don't belong to any project
+			pr = &models.GCProjectReport{
+				ProjectID:         projectID,
+				UntaggedManifests: []*models.GCReportItem{},
+				OrphanBlobs:       []*models.GCReportItem{},
+			}
+			projectReports[projectID] = pr
+			report.Projects = append(report.Projects, pr)
+		}
+		return pr
+	}
+	for _, project := range projects {
+		pr := getProjectReport(project.ProjectID)
+		pr.ProjectName = project.Name
+
+		afs, err := listUntaggedArtifacts(project.ProjectID, before)
+		if err != nil {
+			return nil, err
+		}
+		for _, af := range afs {
+			size, err := dao.GetExclusiveSizeOfArtifact(af.Digest)
+			if err != nil {
+				return nil, err
+			}
+			pr.UntaggedManifests = append(pr.UntaggedManifests, &models.GCReportItem{
+				Repository: af.Repo,
+				Digest:     af.Digest,
+				Size:       size,
+			})
+			pr.Size += size
+			report.TotalManifests++
+			report.TotalSize += size
+		}
+	}
+
+	var lastID int64
+	for {
+		blobs, err := dao.ListUnreferencedBlobs(lastID, before, onlineBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(blobs) == 0 {
+			break
+		}
+		lastID = blobs[len(blobs)-1].ID
+
+		var ids []int64
+		for _, blob := range blobs {
+			ids = append(ids, blob.ID)
+		}
+		projectIDs, err := dao.GetProjectIDsOfBlobs(ids...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range blobs {
+			pids := projectIDs[blob.ID]
+			if len(pids) == 0 {
+				pids = []int64{0}
+			}
+			for _, pid := range pids {
+				pr := getProjectReport(pid)
+				pr.OrphanBlobs = append(pr.OrphanBlobs, &models.GCReportItem{
+					Digest: blob.Digest,
+					Size:   blob.Size,
+				})
+				pr.Size += blob.Size
+			}
+			report.TotalBlobs++
+			report.TotalSize += blob.Size
+		}
+	}
+
+	return report, nil
+}
+
+// saveReport saves the report to the admin job which triggers the gc
+func (gc *GarbageCollector) saveReport(report *models.GCReport) error {
+	if gc.adminJobID == 0 {
+		gc.logger.Warningf("no admin job ID provided, skip saving the gc report")
+		return nil
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := dao.SetAdminJobReport(gc.adminJobID, string(data)); err != nil {
+		gc.logger.Errorf("failed to save the gc report of admin job %d: %v", gc.adminJobID, err)
+		return err
+	}
+	return nil
+}
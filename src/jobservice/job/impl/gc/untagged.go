@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/common/dao"
+	"github.com/goharbor/harbor/src/common/models"
+)
+
+// countManifestLists returns the count of the manifest lists and the OCI image indexes,
+// the garbage-collect of registry can't delete the untagged manifests if there is any
+// as it deletes the platform manifests referenced by them too
+var countManifestLists = func() (int64, error) {
+	return dao.GetTotalOfArtifacts(&models.ArtifactQuery{
+		Kind: models.ArtifactKindImageIndex,
+	})
+}
+
+// canDeleteUntagged returns whether the untagged manifests can be deleted by the gc which sets the
+// system to read only, it's skipped if any manifest list exists as the registry treats the platform
+// manifests of the manifest lists as untagged ones and deletes them
+func (gc *GarbageCollector) canDeleteUntagged(opts *options) (bool, error) {
+	if !opts.deleteUntagged {
+		return false, nil
+	}
+
+	lists, err := countManifestLists()
+	if err != nil {
+		gc.logger.Errorf("failed to count the manifest lists: %v", err)
+		return false, err
+	}
+	if lists > 0 {
+		gc.logger.Warningf("skip deleting the untagged manifests as %d manifest lists exist.", lists)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// listUntaggedArtifacts returns the untagged artifacts of the project created before the time,
+// the newer ones may be the platform manifests of the manifest list which is being pushed
+func listUntaggedArtifacts(projectID int64, before time.Time) ([]*models.Artifact, error) {
+	afs, err := dao.ListUntaggedArtifacts(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*models.Artifact
+	for _, af := range afs {
+		if af.CreationTime.Before(before) {
+			result = append(result, af)
+		}
+	}
+	return result, nil
+}
+
+// deleteUntaggedManifests deletes the artifacts which are not referenced by any tag from the database,
+// the blobs only referenced by them become unreferenced and the quota usage is fixed up by ensureQuota after the gc
+func (gc *GarbageCollector) deleteUntaggedManifests(before time.Time) error {
+	projects, err := dao.GetProjects(nil)
+	if err != nil {
+		return err
+	}
+
+	var total int
+	for _, project := range projects {
+		afs, err := listUntaggedArtifacts(project.ProjectID, before)
+		if err != nil {
+			gc.logger.Errorf("failed to list the untagged manifests of project %d: %v", project.ProjectID, err)
+			return err
+		}
+		for _, af := range afs {
+			if err := dao.DeleteUntaggedArtifact(af); err != nil {
+				gc.logger.Errorf("failed to delete the untagged manifest %s@%s: %v", af.Repo, af.Digest, err)
+				return err
+			}
+			gc.logger.Debugf("untagged manifest %s@%s deleted", af.Repo, af.Digest)
+			total++
+		}
+	}
+
+	gc.logger.Infof("%d untagged manifests deleted.", total)
+	return nil
+}
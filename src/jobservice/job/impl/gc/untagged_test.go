@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/jobservice/logger/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanDeleteUntagged(t *testing.T) {
+	defer func(count func() (int64, error)) {
+		countManifestLists = count
+	}(countManifestLists)
+
+	gc := &GarbageCollector{
+		logger: backend.NewStdOutputLogger("DEBUG", backend.StdErr, 4),
+	}
+
+	var lists int64
+	countManifestLists = func() (int64, error) {
+		return lists, nil
+	}
+
+	// the untagged manifests are deleted by default
+	opts, err := parseOptions(nil)
+	require.Nil(t, err)
+	deletable, err := gc.canDeleteUntagged(opts)
+	require.Nil(t, err)
+	assert.True(t, deletable)
+
+	// skipped when the manifest lists exist
+	lists = 1
+	deletable, err = gc.canDeleteUntagged(opts)
+	require.Nil(t, err)
+	assert.False(t, deletable)
+
+	// disabled by users
+	lists = 0
+	opts.deleteUntagged = false
+	deletable, err = gc.canDeleteUntagged(opts)
+	require.Nil(t, err)
+	assert.False(t, deletable)
+}
@@ -3,12 +3,15 @@ package notification
 import (
 	"bytes"
 	"fmt"
+	"github.com/goharbor/harbor/src/common/config/encrypt"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
 // Max retry has the same meaning as max fails.
@@ -84,7 +87,31 @@ func (wj *WebhookJob) execute(ctx job.Context, params map[string]interface{}) er
 	if v, ok := params["auth_header"]; ok && len(v.(string)) > 0 {
 		req.Header.Set("Authorization", v.(string))
 	}
-	req.Header.Set("Content-Type", "application/json")
+	contentType := "application/json"
+	if v, ok := params["content_type"]; ok && len(v.(string)) > 0 {
+		contentType = v.(string)
+	}
+	req.Header.Set("Content-Type", contentType)
+	// the extra headers, e.g. the "ce-" attributes of the CloudEvents in binary mode
+	if v, ok := params["headers"]; ok {
+		headers, err := parseHeaders(v)
+		if err != nil {
+			return err
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+	}
+
+	// sign the body at the time of sending, so the retries are signed with the new timestamps
+	if v, ok := params["secret"]; ok && len(v.(string)) > 0 {
+		// the secret is encrypted by the core with the shared key
+		secret, err := encrypt.Instance().Decrypt(v.(string))
+		if err != nil {
+			return fmt.Errorf("decrypt the secret of webhook job(target: %s) failed: %v", address, err)
+		}
+		req.Header.Set(signature.Header, signature.Sign(secret, []byte(payload), time.Now()))
+	}
 
 	resp, err := wj.client.Do(req)
 	if err != nil {
@@ -97,3 +124,23 @@ func (wj *WebhookJob) execute(ctx job.Context, params map[string]interface{}) er
 
 	return nil
 }
+
+// parseHeaders parses the headers parameter, which is decoded as "map[string]interface{}" from the JSON
+func parseHeaders(v interface{}) (map[string]string, error) {
+	switch headers := v.(type) {
+	case map[string]string:
+		return headers, nil
+	case map[string]interface{}:
+		result := map[string]string{}
+		for key, value := range headers {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid value of header %s: %v", key, value)
+			}
+			result[key] = s
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("invalid headers: %v", v)
+	}
+}
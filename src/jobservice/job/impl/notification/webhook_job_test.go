@@ -1,13 +1,18 @@
 package notification
 
 import (
+	"github.com/goharbor/harbor/src/common/config/encrypt"
 	"github.com/goharbor/harbor/src/jobservice/job/impl"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
 	"testing"
+	"time"
 )
 
 func TestMaxFails(t *testing.T) {
@@ -73,3 +78,56 @@ func TestRun(t *testing.T) {
 	// test incorrect webhook response
 	assert.NotNil(t, rep.Run(&impl.Context{}, paramsWrong))
 }
+
+func TestRunWithHeaders(t *testing.T) {
+	rep := &WebhookJob{}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/cloudevents+json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "io.goharbor.image.pushed", r.Header.Get("ce-type"))
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"skip_cert_verify": true,
+		"payload":          `{"key": "value"}`,
+		"address":          ts.URL,
+		"content_type":     "application/cloudevents+json",
+		// the headers are decoded from the JSON of job parameters
+		"headers": map[string]interface{}{"ce-type": "io.goharbor.image.pushed"},
+	}
+	assert.Nil(t, rep.Run(&impl.Context{}, params))
+
+	params["headers"] = map[string]interface{}{"ce-type": 1}
+	assert.NotNil(t, rep.Run(&impl.Context{}, params))
+}
+
+func TestRunWithSecret(t *testing.T) {
+	key := path.Join(os.TempDir(), "webhook_job_key")
+	require.Nil(t, ioutil.WriteFile(key, []byte("9TXCcHgNAAp1aSHh"), 0600))
+	defer os.Remove(key)
+	require.Nil(t, os.Setenv("KEY_PATH", key))
+	defer os.Unsetenv("KEY_PATH")
+	secret, err := encrypt.Instance().Encrypt("secret")
+	require.Nil(t, err)
+
+	rep := &WebhookJob{}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Nil(t, signature.Verify("secret", body, r.Header.Get(signature.Header), time.Minute))
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"skip_cert_verify": true,
+		"payload":          `{"key": "value"}`,
+		"address":          ts.URL,
+		"secret":           secret,
+	}
+	assert.Nil(t, rep.Run(&impl.Context{}, params))
+
+	// the secret isn't encrypted
+	params["secret"] = "not encrypted"
+	assert.NotNil(t, rep.Run(&impl.Context{}, params))
+}
@@ -17,6 +17,7 @@ package replication
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/replication/model"
@@ -50,6 +51,12 @@ import (
 	_ "github.com/goharbor/harbor/src/replication/adapter/helmhub"
 	// register the GitLab adapter
 	_ "github.com/goharbor/harbor/src/replication/adapter/gitlab"
+	// register the OCI image layout adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/ocilayout"
+	// register the Nexus adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/nexus"
+	// register the Tencent TCR adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/tencentcr"
 )
 
 // Replication implements the job interface
@@ -100,7 +107,13 @@ func (r *Replication) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 
-	return trans.Transfer(src, dst)
+	if err = trans.Transfer(src, dst); err == transfer.ErrOutOfTimeWindows {
+		// run the replication again when the nearest time window starts
+		delay := model.UntilTimeWindows(dst.Throttle.TimeWindows, time.Now())
+		logger.Infof("the replication is postponed for %s", delay)
+		return job.Postpone(delay, err.Error())
+	}
+	return err
 }
 
 func parseParams(params map[string]interface{}) (*model.Resource, *model.Resource, error) {
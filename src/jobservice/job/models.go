@@ -33,6 +33,26 @@ type RequestBody struct {
 	Parameters Parameters `json:"parameters"`
 	Metadata   *Metadata  `json:"metadata"`
 	StatusHook string     `json:"status_hook"`
+
+	// The following fields make the job the root of a job graph:
+	// the children are launched after the job succeeds and the join job is
+	// launched after all the children finish, with the aggregate status of
+	// the children in the parameter ParamGraphChildrenStatus.
+	Children []*RequestBody `json:"children,omitempty"`
+	Join     *RequestBody   `json:"join,omitempty"`
+	// JoinOnSuccess launches the join job only if all the children succeed
+	JoinOnSuccess bool `json:"join_on_success,omitempty"`
+	// GraphHook receives the aggregate status of the job graph
+	GraphHook string `json:"graph_hook,omitempty"`
+}
+
+// ParamGraphChildrenStatus is the parameter added to the join job of the job graph, it's the
+// aggregate status of the children: "Success" if all of them succeeded, otherwise "Error"
+const ParamGraphChildrenStatus = "graph_children_status"
+
+// IsGraph checks if the job is the root of a job graph
+func (rb *RequestBody) IsGraph() bool {
+	return len(rb.Children) > 0 || rb.Join != nil
 }
 
 // Metadata stores the metadata of job.
@@ -0,0 +1,41 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+// PostponedError is returned by the job which can't go on for now, e.g. the replication out of the
+// time windows of its policy. The worker runs the job again after the delay and the postponement
+// doesn't consume the fails declared by the method 'MaxFails', so the job must be the retryable one
+type PostponedError struct {
+	Delay  time.Duration
+	Reason string
+}
+
+// Error returns the message of the error
+func (p *PostponedError) Error() string {
+	return fmt.Sprintf("postponed for %s: %s", p.Delay, p.Reason)
+}
+
+// Postpone returns the error to ask the worker to run the job again after the delay
+func Postpone(delay time.Duration, reason string) error {
+	return &PostponedError{
+		Delay:  delay,
+		Reason: reason,
+	}
+}
@@ -0,0 +1,308 @@
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3ClientTimeout = 60 * time.Second
+)
+
+var (
+	// S3LogChunkSize is the max size of one chunk object of the log, the memory used by one logger is bounded by it
+	S3LogChunkSize = 1 << 20
+	// S3LogFlushInterval is the interval of uploading the current chunk of the log
+	S3LogFlushInterval = 5 * time.Second
+	// S3LogBufferLimit is the max size of the log buffered when the uploading fails, the log written
+	// beyond it is dropped
+	S3LogBufferLimit = 4 << 20
+	// S3LogMaxFailures is the max times the uploading of one chunk fails before the chunk is dropped
+	S3LogMaxFailures = 3
+)
+
+// S3Logger is an implementation of logger.Interface.
+// It outputs logs to the objects of the S3 compatible storage.
+type S3Logger struct {
+	backendLogger *log.Logger
+	writer        *s3LogWriter
+}
+
+// NewS3Logger crates a new S3 logger
+// As the object can't be appended, the logs are split into the chunk objects named by the
+// sequence number under the prefix and the key, see S3LogChunkKey. The current chunk is
+// uploaded periodically and replaced by the next one once it reaches the S3LogChunkSize.
+func NewS3Logger(client *s3.S3, bucket, prefix, key string, level string, depth int) (*S3Logger, error) {
+	if client == nil {
+		return nil, errors.New("nil s3 client")
+	}
+
+	writer := &s3LogWriter{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+		key:    key,
+		full:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go writer.loop(S3LogFlushInterval)
+
+	logLevel := parseLevel(level)
+	backendLogger := log.New(writer, log.NewTextFormatter(), logLevel, depth)
+
+	return &S3Logger{
+		backendLogger: backendLogger,
+		writer:        writer,
+	}, nil
+}
+
+// S3LogChunkKey returns the key of the chunk object of the log with the sequence number,
+// the zero padded sequence number keeps the chunks listed in order
+func S3LogChunkKey(prefix, key string, seq int) string {
+	return path.Join(prefix, key, fmt.Sprintf("%010d.log", seq))
+}
+
+// s3LogWriter keeps the current chunk of the log and uploads it, the lock only guards the buffer
+// and the uploading is done out of it, so the writing isn't blocked by the S3 storage
+type s3LogWriter struct {
+	lock   sync.Mutex
+	client *s3.S3
+	bucket string
+	prefix string
+	key    string
+	seq    int
+	buffer bytes.Buffer
+	// whether the buffer has data not uploaded
+	dirty bool
+	// the times the uploading of the current chunk fails
+	failures int
+	// serializes the uploading, so the chunks are uploaded in order
+	uploadLock sync.Mutex
+	// notifies the loop to upload the full chunk
+	full   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// Write implements io.Writer
+func (w *s3LogWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.buffer.Len()+len(p) > S3LogBufferLimit {
+		// the uploading keeps failing, drop the log rather than using up the memory
+		return len(p), nil
+	}
+	n, _ := w.buffer.Write(p)
+	w.dirty = true
+	if w.buffer.Len() >= S3LogChunkSize {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+	return n, nil
+}
+
+func (w *s3LogWriter) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.full:
+		case <-w.done:
+			return
+		}
+		if err := w.flush(); err != nil {
+			log.Errorf("failed to upload the log chunk of %s: %v", w.key, err)
+		}
+	}
+}
+
+// flush uploads the data in the buffer chunk by chunk, the chunk not full is uploaded as the current one
+func (w *s3LogWriter) flush() error {
+	w.uploadLock.Lock()
+	defer w.uploadLock.Unlock()
+
+	for {
+		done, err := w.flushChunk()
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// flushChunk uploads the snapshot of the current chunk and moves to the next one if it's full. The data
+// is kept to be uploaded again if failed, unless the uploading fails S3LogMaxFailures times.
+// It returns true if there is no full chunk left to upload
+func (w *s3LogWriter) flushChunk() (bool, error) {
+	w.lock.Lock()
+	if !w.dirty {
+		w.lock.Unlock()
+		return true, nil
+	}
+	size := w.buffer.Len()
+	if size > S3LogChunkSize {
+		size = S3LogChunkSize
+	}
+	data := make([]byte, size)
+	copy(data, w.buffer.Bytes())
+	seq := w.seq
+	w.lock.Unlock()
+
+	_, err := w.client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(w.bucket),
+		Key:         aws.String(S3LogChunkKey(w.prefix, w.key, seq)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("text/plain"),
+	})
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err != nil {
+		w.failures++
+		if w.failures < S3LogMaxFailures {
+			return true, err
+		}
+		// drop the data not uploaded and move to the next chunk, the chunk object keeps the data uploaded before if any
+		log.Errorf("failed to upload the log chunk %s %d times, drop the data not uploaded",
+			S3LogChunkKey(w.prefix, w.key, seq), w.failures)
+		w.failures = 0
+		w.seq++
+		w.discard(size)
+		return true, err
+	}
+
+	w.failures = 0
+	if size == S3LogChunkSize {
+		w.seq++
+		w.discard(size)
+		return !w.dirty, nil
+	}
+	// the data written during the uploading is uploaded next time
+	w.dirty = w.buffer.Len() > size
+	return true, nil
+}
+
+// discard removes the first n bytes of the buffer which are uploaded or dropped, the lock must be held by the caller
+func (w *s3LogWriter) discard(n int) {
+	rest := w.buffer.Bytes()[n:]
+	if len(rest) == 0 {
+		w.buffer.Reset()
+		w.dirty = false
+		return
+	}
+	// copy the rest to release the memory of the discarded data
+	var buffer bytes.Buffer
+	buffer.Write(rest)
+	w.buffer = buffer
+	w.dirty = true
+}
+
+func (w *s3LogWriter) close() error {
+	w.lock.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.done)
+	}
+	w.lock.Unlock()
+
+	return w.flush()
+}
+
+// NewS3Client creates the client of the S3 compatible storage.
+// The path style addressing is used if the endpoint is specified, e.g: MinIO.
+// The default credential chain is used if the access key is not specified.
+func NewS3Client(endpoint, region, accessKey, secretKey string) (*s3.S3, error) {
+	if len(region) == 0 {
+		region = defaultS3Region
+	}
+
+	cfg := &aws.Config{
+		Region:     aws.String(region),
+		HTTPClient: &http.Client{Timeout: s3ClientTimeout},
+	}
+	if len(endpoint) > 0 {
+		cfg.Endpoint = aws.String(endpoint)
+		cfg.S3ForcePathStyle = aws.Bool(true)
+	}
+	if len(accessKey) > 0 {
+		cfg.Credentials = credentials.NewStaticCredentials(accessKey, secretKey, "")
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return s3.New(sess), nil
+}
+
+// Close stops the periodical uploading and uploads the remaining data to the S3 storage
+// Implements logger.Closer interface
+func (sl *S3Logger) Close() error {
+	return sl.writer.close()
+}
+
+// Debug ...
+func (sl *S3Logger) Debug(v ...interface{}) {
+	sl.backendLogger.Debug(v...)
+}
+
+// Debugf with format
+func (sl *S3Logger) Debugf(format string, v ...interface{}) {
+	sl.backendLogger.Debugf(format, v...)
+}
+
+// Info ...
+func (sl *S3Logger) Info(v ...interface{}) {
+	sl.backendLogger.Info(v...)
+}
+
+// Infof with format
+func (sl *S3Logger) Infof(format string, v ...interface{}) {
+	sl.backendLogger.Infof(format, v...)
+}
+
+// Warning ...
+func (sl *S3Logger) Warning(v ...interface{}) {
+	sl.backendLogger.Warning(v...)
+}
+
+// Warningf with format
+func (sl *S3Logger) Warningf(format string, v ...interface{}) {
+	sl.backendLogger.Warningf(format, v...)
+}
+
+// Error ...
+func (sl *S3Logger) Error(v ...interface{}) {
+	sl.backendLogger.Error(v...)
+}
+
+// Errorf with format
+func (sl *S3Logger) Errorf(format string, v ...interface{}) {
+	sl.backendLogger.Errorf(format, v...)
+}
+
+// Fatal error
+func (sl *S3Logger) Fatal(v ...interface{}) {
+	sl.backendLogger.Fatal(v...)
+}
+
+// Fatalf error
+func (sl *S3Logger) Fatalf(format string, v ...interface{}) {
+	sl.backendLogger.Fatalf(format, v...)
+}
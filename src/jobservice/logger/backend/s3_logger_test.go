@@ -0,0 +1,159 @@
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/jobservice/errs"
+	"github.com/goharbor/harbor/src/jobservice/logger/getter"
+	"github.com/goharbor/harbor/src/jobservice/logger/sweeper"
+	"github.com/goharbor/harbor/src/jobservice/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test S3 logger
+func TestS3Logger(t *testing.T) {
+	server := tests.GiveMeS3Server()
+	defer server.Close()
+
+	client, err := NewS3Client(server.URL, "", "ak", "sk")
+	require.Nil(t, err)
+
+	key := "key_for_unit_test"
+	l, err := NewS3Logger(client, "job-logs", "jobs", key, "DEBUG", 4)
+	require.Nil(t, err)
+
+	l.Debug("JobLog Debug: TestS3Logger")
+	l.Info("JobLog Info: TestS3Logger")
+	l.Warning("JobLog Warning: TestS3Logger")
+	l.Error("JobLog Error: TestS3Logger")
+	l.Debugf("JobLog Debugf: %s", "TestS3Logger")
+	l.Infof("JobLog Infof: %s", "TestS3Logger")
+	l.Warningf("JobLog Warningf: %s", "TestS3Logger")
+	l.Errorf("JobLog Errorf: %s", "TestS3Logger")
+
+	err = l.Close()
+	require.Nil(t, err)
+
+	s3Getter := getter.NewS3Getter(client, "job-logs", "jobs")
+	data, err := s3Getter.Retrieve(key)
+	require.Nil(t, err)
+	assert.True(t, strings.Contains(string(data), "JobLog Errorf: TestS3Logger"))
+
+	_, err = s3Getter.Retrieve("non_existing_key")
+	require.NotNil(t, err)
+	assert.True(t, errs.IsObjectNotFoundError(err))
+
+	s3Sweeper := sweeper.NewS3Sweeper(client, "job-logs", "jobs", -1)
+	count, err := s3Sweeper.Sweep()
+	require.Nil(t, err)
+	require.Equal(t, 1, count)
+
+	_, err = s3Getter.Retrieve(key)
+	require.NotNil(t, err)
+}
+
+// Test the S3 logger uploading the log in chunks periodically
+func TestS3LoggerChunks(t *testing.T) {
+	server := tests.GiveMeS3Server()
+	defer server.Close()
+
+	chunkSize, interval := S3LogChunkSize, S3LogFlushInterval
+	S3LogChunkSize, S3LogFlushInterval = 256, 100*time.Millisecond
+	defer func() {
+		S3LogChunkSize, S3LogFlushInterval = chunkSize, interval
+	}()
+
+	client, err := NewS3Client(server.URL, "", "ak", "sk")
+	require.Nil(t, err)
+
+	key := "key_for_chunks"
+	l, err := NewS3Logger(client, "job-logs", "jobs", key, "DEBUG", 4)
+	require.Nil(t, err)
+
+	s3Getter := getter.NewS3Getter(client, "job-logs", "jobs")
+
+	// the log of the running job is uploaded periodically
+	l.Info("JobLog Info: first line")
+	time.Sleep(500 * time.Millisecond)
+	data, err := s3Getter.Retrieve(key)
+	require.Nil(t, err)
+	assert.True(t, strings.Contains(string(data), "first line"))
+	offset := int64(len(data))
+
+	for i := 0; i < 10; i++ {
+		l.Infof("JobLog Infof: line %d", i)
+	}
+	require.Nil(t, l.Close())
+
+	all, err := s3Getter.Retrieve(key)
+	require.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		assert.True(t, strings.Contains(string(all), fmt.Sprintf("line %d\n", i)))
+	}
+
+	// only the new data is retrieved from the offset
+	data, err = s3Getter.RetrieveFrom(key, offset)
+	require.Nil(t, err)
+	assert.Equal(t, string(all[offset:]), string(data))
+	data, err = s3Getter.RetrieveFrom(key, offset+300)
+	require.Nil(t, err)
+	assert.Equal(t, string(all[offset+300:]), string(data))
+	data, err = s3Getter.RetrieveFrom(key, int64(len(all)))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(data))
+
+	// the log is split into chunks
+	s3Sweeper := sweeper.NewS3Sweeper(client, "job-logs", "jobs", -1)
+	count, err := s3Sweeper.Sweep()
+	require.Nil(t, err)
+	assert.True(t, count > 1)
+}
+
+// Test the S3 logger bounding the buffer when the uploading fails
+func TestS3LoggerUploadFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	chunkSize, limit := S3LogChunkSize, S3LogBufferLimit
+	S3LogChunkSize, S3LogBufferLimit = 16, 64
+	defer func() {
+		S3LogChunkSize, S3LogBufferLimit = chunkSize, limit
+	}()
+
+	client, err := NewS3Client(server.URL, "", "ak", "sk")
+	require.Nil(t, err)
+	w := &s3LogWriter{
+		client: client,
+		bucket: "job-logs",
+		prefix: "jobs",
+		key:    "key_for_failure",
+		full:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	// the log beyond the limit is dropped
+	for i := 0; i < 10; i++ {
+		n, err := w.Write([]byte("0123456789"))
+		require.Nil(t, err)
+		assert.Equal(t, 10, n)
+	}
+	assert.Equal(t, 60, w.buffer.Len())
+
+	// the chunk is kept until the uploading fails S3LogMaxFailures times
+	for i := 1; i < S3LogMaxFailures; i++ {
+		require.NotNil(t, w.flush())
+		assert.Equal(t, 60, w.buffer.Len())
+	}
+	require.NotNil(t, w.flush())
+	assert.Equal(t, 44, w.buffer.Len())
+	assert.True(t, w.dirty)
+	assert.Equal(t, 1, w.seq)
+}
@@ -3,7 +3,10 @@ package logger
 import (
 	"errors"
 	"path"
+	"strings"
+	"sync"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/goharbor/harbor/src/jobservice/logger/backend"
 )
 
@@ -87,3 +90,101 @@ func DBFactory(options ...OptionItem) (Interface, error) {
 
 	return backend.NewDBLogger(key, level, depth)
 }
+
+// S3Factory is factory of S3 logger
+func S3Factory(options ...OptionItem) (Interface, error) {
+	var (
+		level, key string
+		depth      int
+	)
+	for _, op := range options {
+		switch op.Field() {
+		case "level":
+			level = op.String()
+		case "key":
+			key = op.String()
+		case "depth":
+			depth = op.Int()
+		default:
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, errors.New("missing key option of the s3 logger")
+	}
+
+	settings, err := parseS3Settings(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.NewS3Logger(settings.client, settings.bucket, settings.prefix, key, level, depth)
+}
+
+// s3Settings keeps the settings of the S3 compatible storage shared by the S3 logger, getter and sweeper
+type s3Settings struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+// parseS3Settings parses the settings of the S3 compatible storage from the options
+func parseS3Settings(options ...OptionItem) (*s3Settings, error) {
+	var endpoint, region, bucket, accessKey, secretKey, prefix string
+	for _, op := range options {
+		switch op.Field() {
+		case "endpoint":
+			endpoint = op.String()
+		case "region":
+			region = op.String()
+		case "bucket":
+			bucket = op.String()
+		case "access_key":
+			accessKey = op.String()
+		case "secret_key":
+			secretKey = op.String()
+		case "prefix":
+			prefix = op.String()
+		default:
+		}
+	}
+
+	if len(bucket) == 0 {
+		return nil, errors.New("missing bucket option of the s3 storage")
+	}
+
+	client, err := getS3Client(endpoint, region, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Settings{
+		client: client,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+	}, nil
+}
+
+var (
+	s3Clients     = map[string]*s3.S3{}
+	s3ClientsLock sync.Mutex
+)
+
+// getS3Client returns the client of the S3 compatible storage shared in the process, the client and
+// its session are created once for the same settings rather than for every logger
+func getS3Client(endpoint, region, accessKey, secretKey string) (*s3.S3, error) {
+	key := strings.Join([]string{endpoint, region, accessKey, secretKey}, "\n")
+
+	s3ClientsLock.Lock()
+	defer s3ClientsLock.Unlock()
+
+	if client, ok := s3Clients[key]; ok {
+		return client, nil
+	}
+	client, err := backend.NewS3Client(endpoint, region, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	s3Clients[key] = client
+	return client, nil
+}
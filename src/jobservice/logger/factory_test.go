@@ -72,3 +72,42 @@ func TestDBFactoryErr1(t *testing.T) {
 	_, err := DBFactory(ois...)
 	require.NotNil(t, err)
 }
+
+// TestS3Factory
+func TestS3Factory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"level", "DEBUG"})
+	ois = append(ois, OptionItem{"key", "key_s3_logger_unit_text"})
+	ois = append(ois, OptionItem{"depth", 5})
+	ois = append(ois, OptionItem{"endpoint", "http://127.0.0.1:9000"})
+	ois = append(ois, OptionItem{"bucket", "job-logs"})
+	ois = append(ois, OptionItem{"access_key", "ak"})
+	ois = append(ois, OptionItem{"secret_key", "sk"})
+
+	_, err := S3Factory(ois...)
+	require.Nil(t, err)
+}
+
+// TestS3ClientShared
+func TestS3ClientShared(t *testing.T) {
+	c1, err := getS3Client("http://127.0.0.1:9000", "", "ak", "sk")
+	require.Nil(t, err)
+	c2, err := getS3Client("http://127.0.0.1:9000", "", "ak", "sk")
+	require.Nil(t, err)
+	require.True(t, c1 == c2)
+
+	c3, err := getS3Client("http://127.0.0.1:9001", "", "ak", "sk")
+	require.Nil(t, err)
+	require.False(t, c1 == c3)
+}
+
+// TestS3FactoryErr1
+func TestS3FactoryErr1(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"level", "DEBUG"})
+	ois = append(ois, OptionItem{"key", "key_s3_logger_unit_text"})
+	ois = append(ois, OptionItem{"depth", 5})
+
+	_, err := S3Factory(ois...)
+	require.NotNil(t, err)
+}
@@ -10,3 +10,16 @@ type Interface interface {
 	// otherwise, a non nil error is returned
 	Retrieve(logID string) ([]byte, error)
 }
+
+// OffsetRetriever is an optional interface of the log data getter which supports
+// retrieving the log data from an offset, it's used to follow the growing log of the running job.
+type OffsetRetriever interface {
+	// RetrieveFrom retrieves the log data of the specified log entry from the offset
+	//
+	// logID string  : the id of the log entry
+	// offset int64  : the number of bytes to skip
+	//
+	// If succeed, log data bytes after the offset will be returned
+	// otherwise, a non nil error is returned
+	RetrieveFrom(logID string, offset int64) ([]byte, error)
+}
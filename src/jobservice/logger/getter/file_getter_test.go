@@ -44,4 +44,22 @@ func TestLogDataGetter(t *testing.T) {
 	if len(data) != 5 {
 		t.Errorf("expect reading 5 bytes but got %d bytes", len(data))
 	}
+
+	data, err = fg.RetrieveFrom(newLogFileID, 3)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(data) != "lo" {
+		t.Errorf("expect reading 'lo' from offset 3 but got '%s'", data)
+	}
+
+	data, err = fg.RetrieveFrom(newLogFileID, 10)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(data) != 0 {
+		t.Errorf("expect reading 0 bytes beyond the end but got %d bytes", len(data))
+	}
 }
@@ -0,0 +1,121 @@
+package getter
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/goharbor/harbor/src/jobservice/errs"
+)
+
+// S3Getter is responsible for retrieving the log data from the S3 compatible storage
+type S3Getter struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+// NewS3Getter is constructor of S3Getter
+func NewS3Getter(client *s3.S3, bucket, prefix string) *S3Getter {
+	return &S3Getter{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Retrieve implements @Interface.Retrieve
+func (sg *S3Getter) Retrieve(logID string) ([]byte, error) {
+	return sg.RetrieveFrom(logID, 0)
+}
+
+// RetrieveFrom implements @OffsetRetriever.RetrieveFrom
+// The log is split into the chunk objects by the S3 logger, only the chunks after the offset are downloaded.
+func (sg *S3Getter) RetrieveFrom(logID string, offset int64) ([]byte, error) {
+	if len(logID) == 0 {
+		return nil, errors.New("empty log identify")
+	}
+
+	chunks, err := sg.listChunks(logID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, errs.NoObjectFoundError(logID)
+	}
+
+	data := make([]byte, 0)
+	var pos int64
+	for _, chunk := range chunks {
+		size := aws.Int64Value(chunk.Size)
+		if pos+size <= offset {
+			pos += size
+			continue
+		}
+		var start int64
+		if offset > pos {
+			start = offset - pos
+		}
+		d, err := sg.getChunk(aws.StringValue(chunk.Key), start)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, d...)
+		pos += size
+	}
+
+	return data, nil
+}
+
+// listChunks lists the chunk objects of the log in order
+func (sg *S3Getter) listChunks(logID string) ([]*s3.Object, error) {
+	chunks := make([]*s3.Object, 0)
+	err := sg.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(sg.bucket),
+		Prefix: aws.String(path.Join(sg.prefix, logID) + "/"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			if strings.HasSuffix(aws.StringValue(obj.Key), ".log") {
+				chunks = append(chunks, obj)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return aws.StringValue(chunks[i].Key) < aws.StringValue(chunks[j].Key)
+	})
+	return chunks, nil
+}
+
+// getChunk downloads the chunk object from the start
+func (sg *S3Getter) getChunk(key string, start int64) ([]byte, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(sg.bucket),
+		Key:    aws.String(key),
+	}
+	if start > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", start))
+	}
+	output, err := sg.client.GetObject(input)
+	if err != nil {
+		if e, ok := err.(awserr.Error); ok && e.Code() == s3.ErrCodeNoSuchKey {
+			// swept
+			return []byte{}, nil
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = output.Body.Close()
+	}()
+
+	return ioutil.ReadAll(output.Body)
+}
@@ -30,3 +30,13 @@ func FileGetterFactory(options ...OptionItem) (getter.Interface, error) {
 func DBGetterFactory(options ...OptionItem) (getter.Interface, error) {
 	return getter.NewDBGetter(), nil
 }
+
+// S3GetterFactory creates a getter for the S3 logger
+func S3GetterFactory(options ...OptionItem) (getter.Interface, error) {
+	settings, err := parseS3Settings(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return getter.NewS3Getter(settings.client, settings.bucket, settings.prefix), nil
+}
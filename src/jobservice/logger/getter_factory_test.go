@@ -32,3 +32,14 @@ func TestDBGetterFactory(t *testing.T) {
 	_, err := DBGetterFactory(ois...)
 	require.Nil(t, err)
 }
+
+// TestS3GetterFactory
+func TestS3GetterFactory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"bucket", "job-logs"})
+	ois = append(ois, OptionItem{"prefix", "/jobs/"})
+
+	g, err := S3GetterFactory(ois...)
+	require.Nil(t, err)
+	require.NotNil(t, g)
+}
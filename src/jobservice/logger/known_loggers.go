@@ -13,6 +13,8 @@ const (
 	NameStdOutput = "STD_OUTPUT"
 	// NameDB is the unique name of the DB logger.
 	NameDB = "DB"
+	// NameS3 is the unique name of the S3 logger.
+	NameS3 = "S3"
 )
 
 // Declaration is used to declare a supported logger.
@@ -36,6 +38,8 @@ var knownLoggers = map[string]*Declaration{
 	NameStdOutput: {StdFactory, nil, nil, true},
 	// DB logger
 	NameDB: {DBFactory, DBSweeperFactory, DBGetterFactory, false},
+	// S3 compatible storage logger
+	NameS3: {S3Factory, S3SweeperFactory, S3GetterFactory, false},
 }
 
 // IsKnownLogger checks if the logger is supported with name.
@@ -102,6 +106,8 @@ func GetLoggerName(l Interface) string {
 		name = NameStdOutput
 	case *backend.FileLogger:
 		name = NameFile
+	case *backend.S3Logger:
+		name = NameS3
 	default:
 		name = reflect.TypeOf(l).String()
 	}
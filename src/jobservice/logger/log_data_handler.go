@@ -15,3 +15,31 @@ func Retrieve(logID string) ([]byte, error) {
 
 	return val.(getter.Interface).Retrieve(logID)
 }
+
+// RetrieveFrom is wrapper func for getter.OffsetRetriever.RetrieveFrom.
+// The log data after the offset is cut from the whole log data if the getter
+// does not support retrieving from offset.
+func RetrieveFrom(logID string, offset int64) ([]byte, error) {
+	if offset < 0 {
+		return nil, errors.New("negative log offset")
+	}
+
+	val, ok := singletons.Load(systemKeyLogDataGetter)
+	if !ok {
+		return nil, errors.New("no log data getter is configured")
+	}
+
+	if r, ok := val.(getter.OffsetRetriever); ok {
+		return r.RetrieveFrom(logID, offset)
+	}
+
+	data, err := val.(getter.Interface).Retrieve(logID)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= int64(len(data)) {
+		return []byte{}, nil
+	}
+
+	return data[offset:], nil
+}
@@ -47,4 +47,8 @@ func TestRetrieve(t *testing.T) {
 	require.NoError(t, err)
 	_, err = Retrieve("no_id")
 	require.Error(t, err)
+	_, err = RetrieveFrom("no_id", 0)
+	require.Error(t, err)
+	_, err = RetrieveFrom("no_id", -1)
+	require.Error(t, err)
 }
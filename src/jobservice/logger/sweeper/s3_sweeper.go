@@ -0,0 +1,81 @@
+package sweeper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Sweeper is used to sweep the logs in the S3 compatible storage
+type S3Sweeper struct {
+	duration int
+	client   *s3.S3
+	bucket   string
+	prefix   string
+}
+
+// NewS3Sweeper is constructor of S3Sweeper
+func NewS3Sweeper(client *s3.S3, bucket, prefix string, duration int) *S3Sweeper {
+	return &S3Sweeper{
+		duration: duration,
+		client:   client,
+		bucket:   bucket,
+		prefix:   prefix,
+	}
+}
+
+// Sweep logs
+func (ss *S3Sweeper) Sweep() (int, error) {
+	before := time.Now().Add(time.Duration(ss.duration) * oneDay * -1)
+
+	// Collect the outdated log objects
+	outdated := make([]string, 0)
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(ss.bucket),
+	}
+	if len(ss.prefix) > 0 {
+		input.Prefix = aws.String(fmt.Sprintf("%s/", ss.prefix))
+	}
+	err := ss.client.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			if obj.LastModified != nil && obj.LastModified.Before(before) &&
+				strings.HasSuffix(aws.StringValue(obj.Key), ".log") {
+				outdated = append(outdated, aws.StringValue(obj.Key))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("getting outdated log objects in bucket '%s' failed with error: %s", ss.bucket, err)
+	}
+
+	// Start to sweep log objects
+	// Record all errors
+	cleared := 0
+	errs := make([]string, 0)
+	for _, key := range outdated {
+		if _, err := ss.client.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(ss.bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("remove log object '%s' error: %s", key, err))
+			continue // go on for next one
+		}
+
+		cleared++
+	}
+
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return cleared, err
+}
+
+// Duration for sweeping
+func (ss *S3Sweeper) Duration() int {
+	return ss.duration
+}
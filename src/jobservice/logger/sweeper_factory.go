@@ -46,3 +46,24 @@ func DBSweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
 
 	return sweeper.NewDBSweeper(duration), nil
 }
+
+// S3SweeperFactory creates S3 sweeper.
+func S3SweeperFactory(options ...OptionItem) (sweeper.Interface, error) {
+	var duration = 1
+	for _, op := range options {
+		switch op.Field() {
+		case "duration":
+			if op.Int() > 0 {
+				duration = op.Int()
+			}
+		default:
+		}
+	}
+
+	settings, err := parseS3Settings(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sweeper.NewS3Sweeper(settings.client, settings.bucket, settings.prefix, duration), nil
+}
@@ -32,3 +32,22 @@ func TestDBSweeperFactory(t *testing.T) {
 	_, err := DBSweeperFactory(ois...)
 	require.Nil(t, err)
 }
+
+// TestS3SweeperFactory
+func TestS3SweeperFactory(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"duration", 2})
+	ois = append(ois, OptionItem{"bucket", "job-logs"})
+
+	_, err := S3SweeperFactory(ois...)
+	require.Nil(t, err)
+}
+
+// TestS3SweeperFactoryErr
+func TestS3SweeperFactoryErr(t *testing.T) {
+	ois := make([]OptionItem, 0)
+	ois = append(ois, OptionItem{"duration", 2})
+
+	_, err := S3SweeperFactory(ois...)
+	require.NotNil(t, err)
+}
@@ -16,33 +16,44 @@ package runner
 
 import (
 	"fmt"
+	"math/rand"
 	"runtime"
+	"sync"
+	"time"
 
 	"github.com/goharbor/harbor/src/jobservice/errs"
 
 	"github.com/gocraft/work"
 	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/graph"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/job/impl"
 	"github.com/goharbor/harbor/src/jobservice/lcm"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/jobservice/period"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/pkg/errors"
 )
 
+// The default max fails of the redis worker if the job does not declare it
+const defaultMaxFails = 4
+
 // RedisJob is a job wrapper to wrap the job.Interface to the style which can be recognized by the redis worker.
 type RedisJob struct {
-	job     interface{}    // the real job implementation
-	context *env.Context   // context
-	ctl     lcm.Controller // life cycle controller
+	job      interface{}      // the real job implementation
+	context  *env.Context     // context
+	ctl      lcm.Controller   // life cycle controller
+	graphCtl graph.Controller // job graph controller
+	delays   sync.Map         // the delays in seconds of the postponed jobs keyed by the job ID
 }
 
 // NewRedisJob is constructor of RedisJob
-func NewRedisJob(job interface{}, ctx *env.Context, ctl lcm.Controller) *RedisJob {
+func NewRedisJob(job interface{}, ctx *env.Context, ctl lcm.Controller, graphCtl graph.Controller) *RedisJob {
 	return &RedisJob{
-		job:     job,
-		context: ctx,
-		ctl:     ctl,
+		job:      job,
+		context:  ctx,
+		ctl:      ctl,
+		graphCtl: graphCtl,
 	}
 }
 
@@ -70,12 +81,19 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 		// directly return without retry again as we have no way to restore the stats again.
 		if errs.IsObjectNotFoundError(err) {
 			j.Fails = 10000000000 // never retry
+			// The job graph waiting for the job is notified as the job will never run
+			rj.jobDone(jID, job.ErrorStatus)
+
+			return
 		}
 		// ELSE:
 		// As tracker creation failed, there is no way to mark the job status change.
 		// Also a non nil error return consumes a fail. If all retries are failed here,
 		// it will cause the job to be zombie one (pending forever).
-		// Those zombie ones will be reaped by the reaper later.
+		// Those zombie ones will be reaped by the reaper later, but the job graph is notified here.
+		if isFinalFailure(Wrap(rj.job), j) {
+			rj.jobDone(jID, job.ErrorStatus)
+		}
 
 		return
 	}
@@ -85,13 +103,26 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 		// Switch job status based on the returned error.
 		// The err happened here should not override the job run error, just log it.
 		if err != nil {
+			// The postponed job is put back to the pending status and run again after the delay
+			if postponed, ok := errors.Cause(err).(*job.PostponedError); ok {
+				rj.postpone(tracker, j, postponed)
+				return
+			}
+
 			// log error
 			logger.Errorf("Job '%s:%s' exit with error: %s", j.Name, j.ID, err)
+			recordOutcome(runningJob, j.Name, job.ErrorStatus)
 
 			if er := tracker.Fail(); er != nil {
 				logger.Errorf("Error occurred when marking the status of job %s:%s to failure: %s", j.Name, j.ID, er)
 			}
 
+			// The job graph only cares the failure without any retries left
+			// The job may fail before it runs, so get the max fails from the job declaration
+			if isFinalFailure(Wrap(rj.job), j) {
+				rj.jobDone(jID, job.ErrorStatus)
+			}
+
 			return
 		}
 
@@ -103,15 +134,22 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 			if latest == job.StoppedStatus {
 				// Logged
 				logger.Infof("Job %s:%s is stopped", j.Name, j.ID)
+				recordOutcome(runningJob, j.Name, job.StoppedStatus)
+				rj.jobDone(jID, job.StoppedStatus)
 				return
 			}
 		}
 
 		// Mark job status to success.
 		logger.Infof("Job '%s:%s' exit with success", j.Name, j.ID)
+		recordOutcome(runningJob, j.Name, job.SuccessStatus)
 		if er := tracker.Succeed(); er != nil {
 			logger.Errorf("Error occurred when marking the status of job %s:%s to success: %s", j.Name, j.ID, er)
 		}
+		// The job which has succeeded before is not run again, it has been done already
+		if runningJob != nil {
+			rj.jobDone(jID, job.SuccessStatus)
+		}
 	}()
 
 	// Defer to handle runtime error
@@ -212,6 +250,56 @@ func (rj *RedisJob) retry(j job.Interface, wj *work.Job) {
 	}
 }
 
+// postpone resets the status of the job and records the delay for the backoff of the redis worker.
+// The fails of the job are increased after the run returns, offset it here as the postponement isn't a failure.
+func (rj *RedisJob) postpone(tracker job.Tracker, j *work.Job, postponed *job.PostponedError) {
+	logger.Infof("Job '%s:%s' is %s", j.Name, j.ID, postponed.Error())
+
+	if err := tracker.Reset(); err != nil {
+		logger.Errorf("Error occurred when resetting the status of the postponed job %s:%s: %s", j.Name, j.ID, err)
+	} else if err := tracker.FireHook(); err != nil {
+		logger.Errorf("Error occurred when firing the hook of the postponed job %s:%s: %s", j.Name, j.ID, err)
+	}
+
+	rj.delays.Store(j.ID, int64(postponed.Delay/time.Second))
+	j.Fails--
+}
+
+// Backoff returns the seconds to wait before retrying the failed job. The postponed job is
+// retried after the delay it asks for, others use the default backoff of the redis worker.
+func (rj *RedisJob) Backoff(j *work.Job) int64 {
+	if delay, ok := rj.delays.Load(j.ID); ok {
+		rj.delays.Delete(j.ID)
+		return delay.(int64)
+	}
+
+	fails := j.Fails
+	return (fails * fails * fails * fails) + 15 + (rand.Int63n(30) * (fails + 1))
+}
+
+// jobDone notifies the job graph controller the final status of the job
+func (rj *RedisJob) jobDone(jobID string, status job.Status) {
+	if rj.graphCtl == nil {
+		return
+	}
+
+	if err := rj.graphCtl.JobDone(jobID, status); err != nil {
+		// Just log it
+		logger.Errorf("Error occurred when handling the done job %s in job graph: %s", jobID, err)
+	}
+}
+
+// isFinalFailure checks if the failed job will not be retried by the redis worker any more.
+// The fails of the job are increased after the run returns.
+func isFinalFailure(runningJob job.Interface, j *work.Job) bool {
+	maxFails := int64(defaultMaxFails)
+	if runningJob != nil && runningJob.MaxFails() > 0 {
+		maxFails = int64(runningJob.MaxFails())
+	}
+
+	return j.Fails+1 >= maxFails
+}
+
 func isPeriodicJobExecution(j *work.Job) (string, bool) {
 	epoch, ok := j.Args[period.PeriodicExecutionMark]
 	return fmt.Sprintf("%s@%s", j.ID, epoch), ok
@@ -220,3 +308,12 @@ func isPeriodicJobExecution(j *work.Job) (string, bool) {
 func bp(b bool) *bool {
 	return &b
 }
+
+// recordOutcome records the outcome of the job in the metrics, the job which doesn't
+// really run(e.g. the one stopped before running) isn't recorded
+func recordOutcome(runningJob job.Interface, jobName string, status job.Status) {
+	if runningJob == nil {
+		return
+	}
+	metrics.JobOutcomesTotal.WithLabelValues(jobName, status.String()).Inc()
+}
@@ -138,7 +138,7 @@ func (suite *RedisRunnerTestSuite) TestJobWrapper() {
 		},
 	}
 
-	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl)
+	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl, nil)
 	err := redisJob.Run(j)
 	require.NoError(suite.T(), err, "redis job: nil error expected but got %s", err)
 }
@@ -152,7 +152,7 @@ func (suite *RedisRunnerTestSuite) TestJobWrapperInvalidTracker() {
 		Fails:      3,
 	}
 
-	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl)
+	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl, nil)
 	err := redisJob.Run(j)
 	require.Error(suite.T(), err, "redis job: non nil error expected but got nil")
 	assert.Equal(suite.T(), int64(10000000000), j.Fails)
@@ -166,7 +166,7 @@ func (suite *RedisRunnerTestSuite) TestJobWrapperPanic() {
 		EnqueuedAt: time.Now().Add(5 * time.Minute).Unix(),
 	}
 
-	redisJob := NewRedisJob((*fakePanicJob)(nil), suite.envContext, suite.lcmCtl)
+	redisJob := NewRedisJob((*fakePanicJob)(nil), suite.envContext, suite.lcmCtl, nil)
 	err := redisJob.Run(j)
 	assert.Error(suite.T(), err)
 }
@@ -184,11 +184,77 @@ func (suite *RedisRunnerTestSuite) TestJobWrapperStopped() {
 	err = t.Stop()
 	require.NoError(suite.T(), err)
 
-	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl)
+	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl, nil)
 	err = redisJob.Run(j)
 	require.NoError(suite.T(), err)
 }
 
+// TestJobWrapperPostponed tests job runner postponed
+func (suite *RedisRunnerTestSuite) TestJobWrapperPostponed() {
+	j := &work.Job{
+		ID:         "FAKE-j",
+		Name:       "fakePostponedJob",
+		EnqueuedAt: time.Now().Add(5 * time.Minute).Unix(),
+		Fails:      1,
+	}
+
+	redisJob := NewRedisJob((*fakePostponedJob)(nil), suite.envContext, suite.lcmCtl, nil)
+	err := redisJob.Run(j)
+	require.Error(suite.T(), err, "redis job: non nil error expected but got nil")
+	// offset the fail increased by the redis worker
+	assert.Equal(suite.T(), int64(0), j.Fails)
+
+	t, err := suite.lcmCtl.Track("FAKE-j")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), job.PendingStatus.String(), t.Job().Info.Status)
+
+	// retried after the delay
+	assert.Equal(suite.T(), int64(60), redisJob.Backoff(j))
+	// the default backoff
+	assert.True(suite.T(), redisJob.Backoff(j) >= 15)
+}
+
+// TestJobWrapperTrackNotFound tests the job graph is notified when the stats of the job are lost
+func (suite *RedisRunnerTestSuite) TestJobWrapperTrackNotFound() {
+	j := &work.Job{
+		ID:         "FAKE-lost",
+		Name:       "fakeParentJob",
+		EnqueuedAt: time.Now().Add(5 * time.Minute).Unix(),
+	}
+
+	graphCtl := &fakeGraphController{}
+	redisJob := NewRedisJob((*fakeParentJob)(nil), suite.envContext, suite.lcmCtl, graphCtl)
+	err := redisJob.Run(j)
+	require.Error(suite.T(), err, "redis job: non nil error expected but got nil")
+	assert.Equal(suite.T(), map[string]job.Status{"FAKE-lost": job.ErrorStatus}, graphCtl.done)
+}
+
+// TestIsFinalFailure ...
+func (suite *RedisRunnerTestSuite) TestIsFinalFailure() {
+	j := &work.Job{Fails: 0}
+	assert.True(suite.T(), isFinalFailure(Wrap((*fakeParentJob)(nil)), j), "job with max fails 1 should not be retried")
+
+	assert.False(suite.T(), isFinalFailure(nil, j), "job should be retried with default max fails")
+	j.Fails = defaultMaxFails - 1
+	assert.True(suite.T(), isFinalFailure(nil, j), "job should not be retried after reaching default max fails")
+}
+
+type fakeGraphController struct {
+	done map[string]job.Status
+}
+
+func (f *fakeGraphController) Create(rootJobID string, root *job.RequestBody) error {
+	return nil
+}
+
+func (f *fakeGraphController) JobDone(jobID string, status job.Status) error {
+	if f.done == nil {
+		f.done = map[string]job.Status{}
+	}
+	f.done[jobID] = status
+	return nil
+}
+
 type fakeParentJob struct {
 }
 
@@ -228,3 +294,22 @@ func (j *fakePanicJob) Validate(params job.Parameters) error {
 func (j *fakePanicJob) Run(ctx job.Context, params job.Parameters) error {
 	panic("for testing")
 }
+
+type fakePostponedJob struct {
+}
+
+func (j *fakePostponedJob) MaxFails() uint {
+	return 3
+}
+
+func (j *fakePostponedJob) ShouldRetry() bool {
+	return true
+}
+
+func (j *fakePostponedJob) Validate(params job.Parameters) error {
+	return nil
+}
+
+func (j *fakePostponedJob) Run(ctx job.Context, params job.Parameters) error {
+	return job.Postpone(time.Minute, "for testing")
+}
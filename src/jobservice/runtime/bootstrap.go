@@ -28,6 +28,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/core"
 	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/graph"
 	"github.com/goharbor/harbor/src/jobservice/hook"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/gc"
@@ -40,6 +41,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/migration"
 	"github.com/goharbor/harbor/src/jobservice/worker"
 	"github.com/goharbor/harbor/src/jobservice/worker/cworker"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/pkg/retention"
 	sc "github.com/goharbor/harbor/src/pkg/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/all"
@@ -93,6 +95,7 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 	var (
 		backendWorker worker.Interface
 		manager       mgt.Manager
+		graphCtl      graph.Controller
 	)
 	if cfg.PoolConfig.Backend == config.JobServicePoolBackendRedis {
 		// Number of workers
@@ -137,6 +140,8 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 
 		// Create job life cycle management controller
 		lcmCtl := lcm.NewController(rootContext, namespace, redisPool, hookCallback)
+		// Create job graph controller
+		graphCtl = graph.NewController(namespace, redisPool, lcmCtl, hookCallback)
 
 		// Start the backend worker
 		backendWorker, err = bs.loadAndRunRedisWorkerPool(
@@ -145,6 +150,8 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 			workerNum,
 			redisPool,
 			lcmCtl,
+			graphCtl,
+			cfg.PoolConfig.JobOptions,
 		)
 		if err != nil {
 			return errors.Errorf("load and run worker error: %s", err)
@@ -165,8 +172,11 @@ func (bs *Bootstrap) LoadAndRun(ctx context.Context, cancel context.CancelFunc)
 		return errors.Errorf("worker backend '%s' is not supported", cfg.PoolConfig.Backend)
 	}
 
+	// Expose the metrics if the listener address is configured
+	metrics.Serve(cfg.MetricAddr, worker.NewCollector(backendWorker))
+
 	// Initialize controller
-	ctl := core.NewController(backendWorker, manager)
+	ctl := core.NewController(backendWorker, manager, graphCtl)
 	// Start the API server
 	apiServer := bs.createAPIServer(ctx, cfg, ctl)
 
@@ -239,8 +249,10 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(
 	workers uint,
 	redisPool *redis.Pool,
 	lcmCtl lcm.Controller,
+	graphCtl graph.Controller,
+	jobOptions map[string]*config.JobOptions,
 ) (worker.Interface, error) {
-	redisWorker := cworker.NewWorker(ctx, ns, workers, redisPool, lcmCtl)
+	redisWorker := cworker.NewWorker(ctx, ns, workers, redisPool, lcmCtl, graphCtl, jobOptions)
 	// Register jobs here
 	if err := redisWorker.RegisterJobs(
 		map[string]interface{}{
@@ -0,0 +1,144 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// s3Object is the object kept in the fake S3 storage
+type s3Object struct {
+	data         []byte
+	lastModified time.Time
+}
+
+// s3Storage is a minimal in-memory stand-in of the S3 compatible storage (e.g: MinIO) which
+// supports putting, getting(with range), deleting and listing(v2) objects with path style addressing.
+type s3Storage struct {
+	lock    *sync.RWMutex
+	objects map[string]*s3Object
+}
+
+type s3ListResult struct {
+	XMLName     xml.Name          `xml:"ListBucketResult"`
+	Name        string            `xml:"Name"`
+	Prefix      string            `xml:"Prefix"`
+	KeyCount    int               `xml:"KeyCount"`
+	IsTruncated bool              `xml:"IsTruncated"`
+	Contents    []*s3ListedObject `xml:"Contents"`
+}
+
+type s3ListedObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	Size         int    `xml:"Size"`
+}
+
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// GiveMeS3Server starts a fake S3 compatible storage server for testing.
+// The server should be closed by the caller.
+func GiveMeS3Server() *httptest.Server {
+	storage := &s3Storage{
+		lock:    new(sync.RWMutex),
+		objects: make(map[string]*s3Object),
+	}
+
+	return httptest.NewServer(storage)
+}
+
+// ServeHTTP implements http.Handler
+func (s *s3Storage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	bucket := parts[0]
+	key := ""
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+	id := bucket + "/" + key
+
+	switch {
+	case r.Method == http.MethodPut && len(key) > 0:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusInternalServerError, "InternalError", err.Error())
+			return
+		}
+		s.lock.Lock()
+		s.objects[id] = &s3Object{data: data, lastModified: time.Now()}
+		s.lock.Unlock()
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && len(key) > 0:
+		s.lock.RLock()
+		obj, ok := s.objects[id]
+		s.lock.RUnlock()
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		// the range requests are handled as well
+		http.ServeContent(w, r, "", obj.lastModified, bytes.NewReader(obj.data))
+	case r.Method == http.MethodGet:
+		prefix := r.URL.Query().Get("prefix")
+		result := &s3ListResult{
+			Name:     bucket,
+			Prefix:   prefix,
+			Contents: make([]*s3ListedObject, 0),
+		}
+		s.lock.RLock()
+		for k, obj := range s.objects {
+			if strings.HasPrefix(k, bucket+"/"+prefix) {
+				result.Contents = append(result.Contents, &s3ListedObject{
+					Key:          strings.TrimPrefix(k, bucket+"/"),
+					LastModified: obj.lastModified.UTC().Format(time.RFC3339),
+					Size:         len(obj.data),
+				})
+			}
+		}
+		s.lock.RUnlock()
+		sort.Slice(result.Contents, func(i, j int) bool {
+			return result.Contents[i].Key < result.Contents[j].Key
+		})
+		result.KeyCount = len(result.Contents)
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodDelete && len(key) > 0:
+		s.lock.Lock()
+		delete(s.objects, id)
+		s.lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "not supported by the fake s3 storage")
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, code int, errCode string, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+	_ = xml.NewEncoder(w).Encode(&s3Error{Code: errCode, Message: message})
+}
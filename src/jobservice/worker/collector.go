@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queueDepthDesc = prometheus.NewDesc(
+		"harbor_jobservice_queue_depth",
+		"The number of the jobs waiting in the queue.",
+		[]string{"job_name"}, nil)
+	queueLatencyDesc = prometheus.NewDesc(
+		"harbor_jobservice_queue_latency_seconds",
+		"The seconds the oldest job has been waiting in the queue.",
+		[]string{"job_name"}, nil)
+)
+
+// NewCollector returns a prometheus collector which exports the depth and latency
+// of the job queues, the values are read from the stats of the worker when collecting
+func NewCollector(w Interface) prometheus.Collector {
+	return &collector{
+		worker: w,
+	}
+}
+
+type collector struct {
+	worker Interface
+}
+
+// Describe ...
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- queueLatencyDesc
+}
+
+// Collect ...
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.worker.Stats()
+	if err != nil {
+		logger.Errorf("Failed to get the worker stats for metrics: %s", err)
+		return
+	}
+	for _, q := range stats.Queues {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(q.Count), q.JobName)
+		ch <- prometheus.MustNewConstMetric(queueLatencyDesc, prometheus.GaugeValue, float64(q.Latency), q.JobName)
+	}
+}
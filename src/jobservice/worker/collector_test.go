@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWorker struct {
+	Interface
+}
+
+func (f *fakeWorker) Stats() (*Stats, error) {
+	return &Stats{
+		Queues: []*QueueStats{
+			{
+				JobName: "IMAGE_REPLICATE",
+				Count:   3,
+				Latency: 10,
+			},
+			{
+				JobName: "IMAGE_SCAN",
+				Count:   1,
+				Latency: 2,
+			},
+		},
+	}, nil
+}
+
+func TestCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.Nil(t, registry.Register(NewCollector(&fakeWorker{})))
+	families, err := registry.Gather()
+	require.Nil(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += "," + label.GetValue()
+			}
+			values[key] = metric.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"harbor_jobservice_queue_depth,IMAGE_REPLICATE":           3,
+		"harbor_jobservice_queue_depth,IMAGE_SCAN":                1,
+		"harbor_jobservice_queue_latency_seconds,IMAGE_REPLICATE": 10,
+		"harbor_jobservice_queue_latency_seconds,IMAGE_SCAN":      2,
+	}, values)
+}
@@ -21,8 +21,11 @@ import (
 	"time"
 
 	"github.com/gocraft/work"
+	"github.com/goharbor/harbor/src/jobservice/common/rds"
 	"github.com/goharbor/harbor/src/jobservice/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/env"
+	"github.com/goharbor/harbor/src/jobservice/graph"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/lcm"
 	"github.com/goharbor/harbor/src/jobservice/logger"
@@ -44,6 +47,14 @@ const (
 	defaultWorkerCount      uint = 10
 )
 
+// the priorities used by the worker pool for the priority classes of the job kinds,
+// the chance of one kind to be dequeued is proportional to its priority
+var priorities = map[string]uint{
+	config.JobPriorityHigh:   1000,
+	config.JobPriorityNormal: 100,
+	config.JobPriorityLow:    10,
+}
+
 // basicWorker is the worker implementation based on gocraft/work powered by redis.
 type basicWorker struct {
 	namespace string
@@ -54,8 +65,13 @@ type basicWorker struct {
 	context   *env.Context
 	scheduler period.Scheduler
 	ctl       lcm.Controller
+	graphCtl  graph.Controller
 	reaper    *reaper
 
+	// key is name of job kind
+	// value is the options of the job kind
+	jobOptions map[string]*config.JobOptions
+
 	// key is name of known job
 	// value is the type of known job
 	knownJobs *sync.Map
@@ -77,22 +93,25 @@ func (rpc *workerContext) logJob(job *work.Job, next work.NextMiddlewareFunc) er
 }
 
 // NewWorker is constructor of worker
-func NewWorker(ctx *env.Context, namespace string, workerCount uint, redisPool *redis.Pool, ctl lcm.Controller) worker.Interface {
+// jobOptions are the priority and concurrency options of the job kinds, key is the name of job kind
+func NewWorker(ctx *env.Context, namespace string, workerCount uint, redisPool *redis.Pool, ctl lcm.Controller, graphCtl graph.Controller, jobOptions map[string]*config.JobOptions) worker.Interface {
 	wc := defaultWorkerCount
 	if workerCount > 0 {
 		wc = workerCount
 	}
 
 	return &basicWorker{
-		namespace: namespace,
-		redisPool: redisPool,
-		pool:      work.NewWorkerPool(workerContext{}, wc, namespace, redisPool),
-		enqueuer:  work.NewEnqueuer(namespace, redisPool),
-		client:    work.NewClient(namespace, redisPool),
-		scheduler: period.NewScheduler(ctx.SystemContext, namespace, redisPool, ctl),
-		ctl:       ctl,
-		context:   ctx,
-		knownJobs: new(sync.Map),
+		namespace:  namespace,
+		redisPool:  redisPool,
+		pool:       work.NewWorkerPool(workerContext{}, wc, namespace, redisPool),
+		enqueuer:   work.NewEnqueuer(namespace, redisPool),
+		client:     work.NewClient(namespace, redisPool),
+		scheduler:  period.NewScheduler(ctx.SystemContext, namespace, redisPool, ctl),
+		ctl:        ctl,
+		graphCtl:   graphCtl,
+		context:    ctx,
+		jobOptions: jobOptions,
+		knownJobs:  new(sync.Map),
 		reaper: &reaper{
 			context:   ctx.SystemContext,
 			namespace: namespace,
@@ -309,11 +328,68 @@ func (w *basicWorker) Stats() (*worker.Stats, error) {
 		return nil, errors.New("failed to get stats of worker pools")
 	}
 
+	// The queue stats are supplementary, don't fail the whole stats if they're not available
+	queues, err := w.queueStats()
+	if err != nil {
+		logger.Errorf("Failed to get stats of queues: %s", err)
+		queues = make([]*worker.QueueStats, 0)
+	}
+
 	return &worker.Stats{
-		Pools: stats,
+		Pools:  stats,
+		Queues: queues,
 	}, nil
 }
 
+// queueStats returns the stats of the queue of each job kind, including the number of the running jobs
+func (w *basicWorker) queueStats() ([]*worker.QueueStats, error) {
+	qs, err := w.client.Queues()
+	if err != nil {
+		return nil, err
+	}
+
+	conn := w.redisPool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	queues := make([]*worker.QueueStats, 0, len(qs))
+	for _, q := range qs {
+		// The lock of the job kind counts the jobs in flight
+		running, err := redis.Int64(conn.Do("GET", rds.KeyJobLock(w.namespace, q.JobName)))
+		if err != nil && err != redis.ErrNil {
+			return nil, err
+		}
+
+		priority, maxConcurrency := w.getJobOptions(q.JobName)
+		queues = append(queues, &worker.QueueStats{
+			JobName:        q.JobName,
+			Count:          q.Count,
+			Latency:        q.Latency,
+			Running:        running,
+			Priority:       priority,
+			MaxConcurrency: maxConcurrency,
+		})
+	}
+
+	return queues, nil
+}
+
+// getJobOptions returns the priority class and max concurrency of the job kind
+func (w *basicWorker) getJobOptions(name string) (string, uint) {
+	options, ok := w.jobOptions[name]
+	if !ok || options == nil {
+		return config.JobPriorityNormal, 0
+	}
+
+	priority := options.Priority
+	if utils.IsEmptyStr(priority) {
+		priority = config.JobPriorityNormal
+	}
+
+	return priority, options.MaxConcurrency
+}
+
 // StopJob will stop the job
 func (w *basicWorker) StopJob(jobID string) error {
 	if utils.IsEmptyStr(jobID) {
@@ -403,15 +479,20 @@ func (w *basicWorker) registerJob(name string, j interface{}) (err error) {
 	}
 
 	// Wrap job
-	redisJob := runner.NewRedisJob(j, w.context, w.ctl)
+	redisJob := runner.NewRedisJob(j, w.context, w.ctl, w.graphCtl)
 	// Get more info from j
 	theJ := runner.Wrap(j)
+	// Get the priority and concurrency settings
+	priority, maxConcurrency := w.getJobOptions(name)
 	// Put into the pool
 	w.pool.JobWithOptions(
 		name,
 		work.JobOptions{
-			MaxFails: theJ.MaxFails(),
-			SkipDead: true,
+			MaxFails:       theJ.MaxFails(),
+			SkipDead:       true,
+			Priority:       priorities[priority],
+			MaxConcurrency: maxConcurrency,
+			Backoff:        redisJob.Backoff,
 		},
 		// Use generic handler to handle as we do not accept context with this way.
 		func(job *work.Job) error {
@@ -421,7 +502,7 @@ func (w *basicWorker) registerJob(name string, j interface{}) (err error) {
 	// Keep the name of registered jobs as known jobs for future validation
 	w.knownJobs.Store(name, j)
 
-	logger.Infof("Register job %s with name %s", reflect.TypeOf(j).String(), name)
+	logger.Infof("Register job %s with name %s (priority: %s, max concurrency: %d)", reflect.TypeOf(j).String(), name, priority, maxConcurrency)
 
 	return nil
 }
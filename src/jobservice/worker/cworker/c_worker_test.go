@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/goharbor/harbor/src/jobservice/common/utils"
+	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/env"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/lcm"
@@ -71,7 +72,12 @@ func (suite *CWorkerTestSuite) SetupSuite() {
 		func(hookURL string, change *job.StatusChange) error { return nil },
 	)
 
-	suite.cWorker = NewWorker(envCtx, suite.namespace, 5, suite.pool, suite.lcmCtl)
+	suite.cWorker = NewWorker(envCtx, suite.namespace, 5, suite.pool, suite.lcmCtl, nil, map[string]*config.JobOptions{
+		"fake_job": {
+			Priority:       config.JobPriorityHigh,
+			MaxConcurrency: 1,
+		},
+	})
 	err := suite.cWorker.RegisterJobs(map[string]interface{}{
 		"fake_job":          (*fakeJob)(nil),
 		"fake_long_run_job": (*fakeLongRunJob)(nil),
@@ -175,6 +181,17 @@ func (suite *CWorkerTestSuite) TestWorkerStats() {
 	stats, err := suite.cWorker.Stats()
 	require.NoError(suite.T(), err, "worker stats: nil error expected but got %s", err)
 	assert.Equal(suite.T(), 1, len(stats.Pools), "expected 1 pool but got 0")
+	assert.NotNil(suite.T(), stats.Queues, "expected non nil queue stats")
+	for _, q := range stats.Queues {
+		switch q.JobName {
+		case "fake_job":
+			assert.Equal(suite.T(), config.JobPriorityHigh, q.Priority, "expected priority %s but got %s", config.JobPriorityHigh, q.Priority)
+			assert.Equal(suite.T(), uint(1), q.MaxConcurrency, "expected max concurrency 1 but got %d", q.MaxConcurrency)
+		case "fake_long_run_job":
+			assert.Equal(suite.T(), config.JobPriorityNormal, q.Priority, "expected priority %s but got %s", config.JobPriorityNormal, q.Priority)
+			assert.Equal(suite.T(), uint(0), q.MaxConcurrency, "expected max concurrency 0 but got %d", q.MaxConcurrency)
+		}
+	}
 }
 
 // TestStopJob test stop job
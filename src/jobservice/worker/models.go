@@ -16,7 +16,8 @@ package worker
 
 // Stats represents the healthy and status of all the running worker pools.
 type Stats struct {
-	Pools []*StatsData `json:"worker_pools"`
+	Pools  []*StatsData  `json:"worker_pools"`
+	Queues []*QueueStats `json:"queues,omitempty"`
 }
 
 // StatsData represents the healthy and status of the worker worker.
@@ -28,3 +29,17 @@ type StatsData struct {
 	Concurrency  uint     `json:"concurrency"`
 	Status       string   `json:"status"`
 }
+
+// QueueStats represents the depth, latency and occupancy of the queue of one job kind.
+type QueueStats struct {
+	JobName string `json:"job_name"`
+	Count   int64  `json:"count"`
+	// Latency is the seconds the oldest job has been waiting in the queue
+	Latency int64 `json:"latency"`
+	// Running is the number of the jobs of the kind in flight
+	Running int64 `json:"running"`
+	// Priority is the priority class of the job kind
+	Priority string `json:"priority"`
+	// MaxConcurrency is the max number of the jobs of the kind in flight, 0 means no limitation
+	MaxConcurrency uint `json:"max_concurrency"`
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 
 	"github.com/pkg/errors"
 )
@@ -85,6 +86,15 @@ type Candidate struct {
 	CreationTime int64
 	// Labels attached with the candidate
 	Labels []string
+	// Vulnerability summary of the native scan report, nil if the candidate isn't scanned
+	Vulnerability *vuln.NativeReportSummary
+	// References are the digests of the platform specific manifests if the candidate is a manifest list
+	References []string
+}
+
+// Scanned returns whether the candidate has a completed vulnerability scan report
+func (c *Candidate) Scanned() bool {
+	return c.Vulnerability != nil && c.Vulnerability.Summary != nil
 }
 
 // Hash code based on the candidate info for differentiation
@@ -97,6 +107,18 @@ func (c *Candidate) Hash() string {
 	return base64.StdEncoding.EncodeToString([]byte(raw))
 }
 
+// ReferenceHashes returns the hash codes of the platform specific manifests referenced by the candidate,
+// they're the same as the hash codes of the candidates with the referenced digests
+func (c *Candidate) ReferenceHashes() []string {
+	hashes := make([]string, 0, len(c.References))
+	for _, ref := range c.References {
+		raw := fmt.Sprintf("%s:%s/%s:%s", c.Kind, c.Namespace, c.Repository, ref)
+		hashes = append(hashes, base64.StdEncoding.EncodeToString([]byte(raw)))
+	}
+
+	return hashes
+}
+
 // NameHash based on the candidate info for differentiation
 func (c *Candidate) NameHash() string {
 	raw := fmt.Sprintf("%s:%s/%s:%s", c.Kind, c.Namespace, c.Repository, c.Tag)
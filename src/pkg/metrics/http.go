@@ -0,0 +1,138 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type routeKey struct{}
+
+// the routes of the registry V2 API, the repository names are replaced to keep
+// the cardinality of the route label low
+var registryRoutes = []struct {
+	pattern *regexp.Regexp
+	route   string
+}{
+	{regexp.MustCompile(`^/v2/?$`), "/v2/"},
+	{regexp.MustCompile(`^/v2/_catalog$`), "/v2/_catalog"},
+	{regexp.MustCompile(`^/v2/.+/tags/list$`), "/v2/{name}/tags/list"},
+	{regexp.MustCompile(`^/v2/.+/manifests/[^/]+$`), "/v2/{name}/manifests/{reference}"},
+	{regexp.MustCompile(`^/v2/.+/blobs/uploads/?$`), "/v2/{name}/blobs/uploads/"},
+	{regexp.MustCompile(`^/v2/.+/blobs/uploads/[^/]+$`), "/v2/{name}/blobs/uploads/{uuid}"},
+	{regexp.MustCompile(`^/v2/.+/blobs/[^/]+$`), "/v2/{name}/blobs/{digest}"},
+}
+
+// RegistryRoute returns the route of the registry V2 API that the path matches
+func RegistryRoute(path string) string {
+	for _, r := range registryRoutes {
+		if r.pattern.MatchString(path) {
+			return r.route
+		}
+	}
+	return "/v2/*"
+}
+
+// SetRoute sets the route that the request matches, the route is used as the label of the
+// metrics instead of the path. It should be called by the router of the handler that is
+// instrumented by Instrument
+func SetRoute(r *http.Request, route string) {
+	if p, ok := r.Context().Value(routeKey{}).(*string); ok {
+		*p = route
+	}
+}
+
+// MuxMiddleware is the middleware of the gorilla mux router, it sets the path template
+// of the matched route as the route of the request
+func MuxMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				SetRoute(r, tpl)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Instrument the handler to record the count and latency of the requests, the requests
+// of the registry V2 API are recorded per operation, the others per the route set by SetRoute
+func Instrument(service string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := ""
+		r = r.WithContext(context.WithValue(r.Context(), routeKey{}, &route))
+		rw := &responseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+		handler.ServeHTTP(rw, r)
+
+		if r.URL.Path == "/v2" || strings.HasPrefix(r.URL.Path, "/v2/") {
+			route = RegistryRoute(r.URL.Path)
+		}
+		if len(route) == 0 {
+			route = "unknown"
+		}
+		HTTPRequestsTotal.WithLabelValues(service, r.Method, route, strconv.Itoa(rw.status)).Inc()
+		HTTPRequestDurationSeconds.WithLabelValues(service, r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// responseWriter records the status code of the response
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(data)
+}
+
+// Flush ...
+func (r *responseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer doesn't support hijacking")
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,87 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, labels ...string) float64 {
+	metric := &dto.Metric{}
+	require.Nil(t, HTTPRequestsTotal.WithLabelValues(labels...).Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
+func TestRegistryRoute(t *testing.T) {
+	cases := map[string]string{
+		"/v2":                               "/v2/",
+		"/v2/":                              "/v2/",
+		"/v2/_catalog":                      "/v2/_catalog",
+		"/v2/library/hello-world/tags/list": "/v2/{name}/tags/list",
+		"/v2/library/hello-world/manifests/latest":    "/v2/{name}/manifests/{reference}",
+		"/v2/library/sub/hello-world/manifests/1.0":   "/v2/{name}/manifests/{reference}",
+		"/v2/library/hello-world/blobs/uploads/":      "/v2/{name}/blobs/uploads/",
+		"/v2/library/hello-world/blobs/uploads/1234":  "/v2/{name}/blobs/uploads/{uuid}",
+		"/v2/library/hello-world/blobs/sha256:abcdef": "/v2/{name}/blobs/{digest}",
+		"/v2/library/hello-world/unknown":             "/v2/*",
+	}
+	for path, route := range cases {
+		assert.Equal(t, route, RegistryRoute(path), path)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	handler := Instrument("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/projects/1" {
+			SetRoute(r, "/api/projects/:id")
+			w.Write([]byte("{}"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		// the status code written later is ignored
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for _, path := range []string{"/api/projects/1", "/v2/library/hello-world/manifests/latest", "/other"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	assert.Equal(t, float64(1), counterValue(t, "test", http.MethodGet, "/api/projects/:id", "200"))
+	assert.Equal(t, float64(1), counterValue(t, "test", http.MethodGet, "/v2/{name}/manifests/{reference}", "404"))
+	assert.Equal(t, float64(1), counterValue(t, "test", http.MethodGet, "unknown", "404"))
+
+	metric := &dto.Metric{}
+	observer := HTTPRequestDurationSeconds.WithLabelValues("test", http.MethodGet, "/api/projects/:id")
+	require.Nil(t, observer.(interface {
+		Write(*dto.Metric) error
+	}).Write(metric))
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+}
+
+func TestMuxMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(MuxMiddleware)
+	router.HandleFunc("/api/v1/jobs/{job_id}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := Instrument("mux", router)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/jobs/1", nil))
+	assert.Equal(t, float64(1), counterValue(t, "mux", http.MethodGet, "/api/v1/jobs/{job_id}", "200"))
+}
@@ -0,0 +1,74 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "harbor"
+
+var (
+	// HTTPRequestsTotal is the number of the HTTP requests handled per service, method, route and status code
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "The total number of the HTTP requests handled.",
+	}, []string{"service", "method", "route", "code"})
+
+	// HTTPRequestDurationSeconds is the latency of the HTTP requests per service, method and route
+	HTTPRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "The latency of the HTTP requests in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method", "route"})
+
+	// JobOutcomesTotal is the number of the jobs that the job service finished per job name and final status
+	JobOutcomesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobservice",
+		Name:      "job_outcomes_total",
+		Help:      "The total number of the jobs finished by the job service.",
+	}, []string{"job_name", "status"})
+
+	// ReplicationTransferredBytesTotal is the size of the data transferred by the replication per resource type
+	ReplicationTransferredBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "replication",
+		Name:      "transferred_bytes_total",
+		Help:      "The total size of the data transferred by the replication in bytes.",
+	}, []string{"resource_type"})
+
+	// ScanDurationSeconds is the duration of the scan jobs per final status
+	ScanDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scan",
+		Name:      "duration_seconds",
+		Help:      "The duration of the scan jobs in seconds.",
+		// from 1 second to about 1 hour
+		Buckets: prometheus.ExponentialBuckets(1, 2, 13),
+	}, []string{"status"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequestsTotal,
+		HTTPRequestDurationSeconds,
+		JobOutcomesTotal,
+		ReplicationTransferredBytesTotal,
+		ScanDurationSeconds,
+	)
+}
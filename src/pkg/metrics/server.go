@@ -0,0 +1,46 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the path that the metrics are served on
+const Path = "/metrics"
+
+// Serve the metrics on the path "/metrics" of the listener address in a new goroutine,
+// the collectors specific to the service are registered only when the metrics are served.
+// The metrics aren't served if the address is empty.
+// The metrics are served without authentication, the address should be internal only
+func Serve(addr string, collectors ...prometheus.Collector) {
+	if len(addr) == 0 {
+		log.Debug("the listener address of metrics isn't configured, skip serving the metrics")
+		return
+	}
+	prometheus.MustRegister(collectors...)
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	go func() {
+		log.Infof("serving the metrics on %s%s", addr, Path)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Errorf("failed to serve the metrics on %s: %v", addr, err)
+		}
+	}()
+}
@@ -12,6 +12,25 @@ const (
 	EventTypeScanningFailed    = "scanningFailed"
 	EventTypeTestEndpoint      = "testEndpoint"
 	EventTypeProjectQuota      = "projectQuota"
+	// EventTypeReplication is the event of the replication execution finished
+	EventTypeReplication = "replication"
+	// EventTypeTagRetention is the event of the tag retention finished for a repository
+	EventTypeTagRetention = "tagRetention"
+	// EventTypeImmutableTagRejected is the event of the push rejected by the immutable tag rules
+	EventTypeImmutableTagRejected = "immutableTagRejected"
+	// EventTypeSystemGC is the event of the system garbage collection finished
+	EventTypeSystemGC = "systemGC"
 
-	NotifyTypeHTTP = "http"
+	NotifyTypeHTTP  = "http"
+	NotifyTypeSlack = "slack"
+
+	// PayloadFormatDefault is the Harbor payload format
+	PayloadFormatDefault = "Default"
+	// PayloadFormatCloudEvents is the CloudEvents 1.0 payload format
+	PayloadFormatCloudEvents = "CloudEvents"
+
+	// CloudEventsModeStructured sends the attributes and the data together as the JSON body
+	CloudEventsModeStructured = "structured"
+	// CloudEventsModeBinary sends the attributes as the "ce-" headers and the data as the body
+	CloudEventsModeBinary = "binary"
 )
@@ -1,17 +1,22 @@
 package manager
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/dao/notification"
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/core/config"
 	notifierModel "github.com/goharbor/harbor/src/core/notifier/model"
 	"github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 )
 
 // DefaultManager ...
@@ -29,7 +34,7 @@ func (m *DefaultManager) Create(policy *models.NotificationPolicy) (int64, error
 	policy.CreationTime = t
 	policy.UpdateTime = t
 
-	err := policy.ConvertToDBModel()
+	err := convertToDBModel(policy)
 	if err != nil {
 		return 0, err
 	}
@@ -45,7 +50,7 @@ func (m *DefaultManager) List(projectID int64) ([]*models.NotificationPolicy, er
 	}
 
 	for _, policy := range persisPolicies {
-		err := policy.ConvertFromDBModel()
+		err := convertFromDBModel(policy)
 		if err != nil {
 			return nil, err
 		}
@@ -64,7 +69,7 @@ func (m *DefaultManager) Get(id int64) (*models.NotificationPolicy, error) {
 	if policy == nil {
 		return nil, nil
 	}
-	err = policy.ConvertFromDBModel()
+	err = convertFromDBModel(policy)
 	return policy, err
 }
 
@@ -74,14 +79,14 @@ func (m *DefaultManager) GetByNameAndProjectID(name string, projectID int64) (*m
 	if err != nil {
 		return nil, err
 	}
-	err = policy.ConvertFromDBModel()
+	err = convertFromDBModel(policy)
 	return policy, err
 }
 
 // Update the specified notification policy
 func (m *DefaultManager) Update(policy *models.NotificationPolicy) error {
 	policy.UpdateTime = time.Now()
-	err := policy.ConvertToDBModel()
+	err := convertToDBModel(policy)
 	if err != nil {
 		return err
 	}
@@ -93,7 +98,9 @@ func (m *DefaultManager) Delete(policyID int64) error {
 	return notification.DeleteNotificationPolicy(policyID)
 }
 
-// Test the specified notification policy, just test for network connection without request body
+// Test the specified notification policy, just test for network connection,
+// the test payload is sent and signed only when the http target has a secret,
+// the slack targets are sent a Slack-formatted test message
 func (m *DefaultManager) Test(policy *models.NotificationPolicy) error {
 	p, err := json.Marshal(notifierModel.Payload{
 		Type: model.EventTypeTestEndpoint,
@@ -104,8 +111,10 @@ func (m *DefaultManager) Test(policy *models.NotificationPolicy) error {
 
 	for _, target := range policy.Targets {
 		switch target.Type {
-		case "http":
-			return m.policyHTTPTest(target.Address, target.SkipCertVerify, p)
+		case model.NotifyTypeHTTP:
+			return m.policyHTTPTest(target.Address, target.SkipCertVerify, target.Secret, p)
+		case model.NotifyTypeSlack:
+			return m.policySlackTest(target.Address, target.SkipCertVerify)
 		default:
 			return fmt.Errorf("invalid policy target type: %s", target.Type)
 		}
@@ -113,13 +122,20 @@ func (m *DefaultManager) Test(policy *models.NotificationPolicy) error {
 	return nil
 }
 
-func (m *DefaultManager) policyHTTPTest(address string, skipCertVerify bool, p []byte) error {
-	req, err := http.NewRequest(http.MethodPost, address, nil)
+func (m *DefaultManager) policyHTTPTest(address string, skipCertVerify bool, secret string, p []byte) error {
+	var body io.Reader
+	if len(secret) > 0 {
+		body = bytes.NewReader(p)
+	}
+	req, err := http.NewRequest(http.MethodPost, address, body)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if len(secret) > 0 {
+		req.Header.Set(signature.Header, signature.Sign(secret, p, time.Now()))
+	}
 
 	client := http.Client{
 		Transport: commonhttp.GetHTTPTransport(skipCertVerify),
@@ -135,6 +151,42 @@ func (m *DefaultManager) policyHTTPTest(address string, skipCertVerify bool, p [
 	return nil
 }
 
+// slackTestMessage is the test message for the Slack incoming webhooks which only accept
+// the Slack-formatted messages, it's rendered the same as the one by the Slack handler
+var slackTestMessage = map[string]string{
+	"text": "*[Harbor] Test message from Harbor*",
+}
+
+// policySlackTest sends the test message to the Slack incoming webhook, the webhook rejects
+// the request with an error status code if the address is invalid
+func (m *DefaultManager) policySlackTest(address string, skipCertVerify bool) error {
+	p, err := json.Marshal(slackTestMessage)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(p))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := http.Client{
+		Transport: commonhttp.GetHTTPTransport(skipCertVerify),
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("failed to send the test message to %s, status code: %d", address, resp.StatusCode)
+	}
+	log.Debugf("policy test success with slack address %s, skip cert verify :%v", address, skipCertVerify)
+
+	return nil
+}
+
 // GetRelatedPolices get policies including event type in project
 func (m *DefaultManager) GetRelatedPolices(projectID int64, eventType string) ([]*models.NotificationPolicy, error) {
 	policies, err := m.List(projectID)
@@ -157,3 +209,50 @@ func (m *DefaultManager) GetRelatedPolices(projectID int64, eventType string) ([
 	}
 	return result, nil
 }
+
+// convertToDBModel converts the policy to DB model with the secrets of targets encrypted
+func convertToDBModel(policy *models.NotificationPolicy) error {
+	targets := policy.Targets
+	defer func() {
+		policy.Targets = targets
+	}()
+
+	encrypted := make([]models.EventTarget, len(targets))
+	for i, target := range targets {
+		if len(target.Secret) > 0 {
+			key, err := config.SecretKey()
+			if err != nil {
+				return err
+			}
+			target.Secret, err = utils.ReversibleEncrypt(target.Secret, key)
+			if err != nil {
+				return err
+			}
+		}
+		encrypted[i] = target
+	}
+	policy.Targets = encrypted
+	return policy.ConvertToDBModel()
+}
+
+// convertFromDBModel converts the policy from DB model with the secrets of targets decrypted
+func convertFromDBModel(policy *models.NotificationPolicy) error {
+	if err := policy.ConvertFromDBModel(); err != nil {
+		return err
+	}
+	for i, target := range policy.Targets {
+		if len(target.Secret) == 0 {
+			continue
+		}
+		key, err := config.SecretKey()
+		if err != nil {
+			return err
+		}
+		secret, err := utils.ReversibleDecrypt(target.Secret, key)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt the secret of target %s: %v", target.Address, err)
+		}
+		policy.Targets[i].Secret = secret
+	}
+	return nil
+}
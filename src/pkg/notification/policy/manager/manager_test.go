@@ -1,8 +1,16 @@
 package manager
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/pkg/notification/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDefaultManger(t *testing.T) {
@@ -20,3 +28,34 @@ func TestNewDefaultManger(t *testing.T) {
 		})
 	}
 }
+
+func TestSlackPolicyTest(t *testing.T) {
+	var message map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil || len(message["text"]) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy := &models.NotificationPolicy{
+		Targets: []models.EventTarget{
+			{
+				Type:    model.NotifyTypeSlack,
+				Address: server.URL,
+			},
+		},
+	}
+	m := NewDefaultManger()
+	require.Nil(t, m.Test(policy))
+	assert.Equal(t, "*[Harbor] Test message from Harbor*", message["text"])
+
+	// rejected by the webhook
+	policy.Targets[0].Address = server.URL + "/invalid"
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	assert.NotNil(t, m.Test(policy))
+}
@@ -0,0 +1,83 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature signs the webhook requests with the secrets of the notification targets,
+// the signature header is like "t=1575187200,v1=<hex encoded HMAC-SHA256 of "1575187200.<body>">"
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Header is the header of the signature in the webhook requests
+	Header = "X-Harbor-Signature"
+
+	version = "v1"
+)
+
+// Sign returns the signature header value of the body signed at the time
+func Sign(secret string, body []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,%s=%s", timestamp, version, compute(secret, timestamp, body))
+}
+
+// Verify verifies the signature header value of the body, the signature must be signed within the tolerance
+// to prevent the replay attacks
+func Verify(secret string, body []byte, header string, tolerance time.Duration) error {
+	var timestamp, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case version:
+			sig = kv[1]
+		}
+	}
+	if len(timestamp) == 0 || len(sig) == 0 {
+		return errors.New("invalid signature header")
+	}
+
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s: %v", timestamp, err)
+	}
+	if d := time.Since(time.Unix(t, 0)); d > tolerance || d < -tolerance {
+		return errors.New("the signature is out of the tolerance")
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(compute(secret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func compute(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,41 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"pushImage"}`)
+	now := time.Now()
+
+	header := Sign("secret", body, now)
+	assert.Contains(t, header, "t=")
+	assert.Contains(t, header, ",v1=")
+	assert.Nil(t, Verify("secret", body, header, 5*time.Minute))
+
+	// wrong secret
+	assert.NotNil(t, Verify("wrong", body, header, 5*time.Minute))
+	// tampered body
+	assert.NotNil(t, Verify("secret", []byte(`{"type":"deleteImage"}`), header, 5*time.Minute))
+	// expired
+	assert.NotNil(t, Verify("secret", body, Sign("secret", body, now.Add(-time.Hour)), 5*time.Minute))
+	// invalid header
+	assert.NotNil(t, Verify("secret", body, "invalid", 5*time.Minute))
+}
@@ -0,0 +1,302 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxy implements the pull-through cache of the proxy cache projects,
+// the manifests and blobs missing locally are fetched from the upstream registry and stored locally.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	common_http_auth "github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	"github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/adapter/native"
+	"github.com/goharbor/harbor/src/replication/event"
+	"github.com/goharbor/harbor/src/replication/model"
+	reg "github.com/goharbor/harbor/src/replication/registry"
+	"github.com/goharbor/harbor/src/replication/util"
+	"github.com/opencontainers/go-digest"
+)
+
+// SecretHeader is the header carrying the secret of the local registry in the requests of the controller,
+// the requests with the valid secret bypass the proxy cache middleware
+const SecretHeader = "X-Harbor-Proxy-Cache-Secret"
+
+// the interval to prune the tags checked out of their staleness windows
+const pruneInterval = 10 * time.Minute
+
+// DefaultController is the global proxy cache controller, it's initialized when core starts
+var DefaultController Controller
+
+// Controller ensures the manifests and blobs of the proxy cache projects are available locally
+type Controller interface {
+	// EnsureManifest makes sure the manifest referenced by the tag or digest is cached locally,
+	// the cached tag is checked against the upstream registry when it's out of the staleness window
+	EnsureManifest(project *models.Project, repository, reference string) error
+	// EnsureBlob makes sure the blob is cached locally
+	EnsureBlob(project *models.Project, repository, digest string) error
+}
+
+// NewController returns a controller fetching the content from the upstream registries managed by the registry manager
+func NewController(registryMgr reg.Manager) Controller {
+	return &controller{
+		remote: func(registryID int64) (adapter.ImageRegistry, error) {
+			r, err := registryMgr.Get(registryID)
+			if err != nil {
+				return nil, err
+			}
+			if r == nil {
+				return nil, fmt.Errorf("registry %d not found", registryID)
+			}
+			return createImageRegistry(r)
+		},
+		local: func() (adapter.ImageRegistry, error) {
+			return createLocalRegistry(event.GetLocalRegistry())
+		},
+		checked: map[string]*checkedTag{},
+		locks:   newKeyLocker(),
+	}
+}
+
+// checkedTag records the last time the tag was checked against the upstream registry
+// and the staleness window of the project when it was checked
+type checkedTag struct {
+	time      time.Time
+	staleness time.Duration
+}
+
+type controller struct {
+	remote func(registryID int64) (adapter.ImageRegistry, error)
+	local  func() (adapter.ImageRegistry, error)
+
+	// the tags checked against the upstream registry, the ones out of their
+	// staleness windows are pruned every pruneInterval to bound the map
+	checkedLock sync.RWMutex
+	checked     map[string]*checkedTag
+	lastPruned  time.Time
+
+	locks *keyLocker
+}
+
+func (c *controller) EnsureManifest(project *models.Project, repository, reference string) error {
+	key := fmt.Sprintf("%s:%s", repository, reference)
+	c.locks.Lock(key)
+	defer c.locks.Unlock(key)
+
+	local, remote, err := c.registries(project)
+	if err != nil {
+		return err
+	}
+
+	exist, localDigest, err := local.ManifestExist(repository, reference)
+	if err != nil {
+		return err
+	}
+
+	_, err = digest.Parse(reference)
+	isDigest := err == nil
+	if exist && (isDigest || c.fresh(key, project.ProxyCacheStaleness())) {
+		// the content of the digest never changes
+		return nil
+	}
+
+	upstreamRepo := upstreamRepository(project, repository)
+	upstreamExist, upstreamDigest, err := remote.ManifestExist(upstreamRepo, reference)
+	if err != nil {
+		if exist {
+			log.Warningf("failed to check the manifest %s:%s in upstream registry, serve the cached one: %v", upstreamRepo, reference, err)
+			return nil
+		}
+		return err
+	}
+	if !upstreamExist {
+		// serve the cached one if it exists, otherwise the local registry responds the manifest unknown
+		return nil
+	}
+	if exist && upstreamDigest == localDigest {
+		c.markChecked(key, project.ProxyCacheStaleness())
+		return nil
+	}
+
+	log.Debugf("fetching the manifest %s:%s from upstream registry to %s", upstreamRepo, reference, repository)
+	if err := copyManifest(remote, local, upstreamRepo, repository, reference); err != nil {
+		return err
+	}
+	c.markChecked(key, project.ProxyCacheStaleness())
+	return nil
+}
+
+func (c *controller) EnsureBlob(project *models.Project, repository, digest string) error {
+	key := fmt.Sprintf("%s@%s", repository, digest)
+	c.locks.Lock(key)
+	defer c.locks.Unlock(key)
+
+	local, remote, err := c.registries(project)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("fetching the blob %s of %s from upstream registry if it doesn't exist", digest, repository)
+	return copyBlob(remote, local, upstreamRepository(project, repository), repository, digest)
+}
+
+func (c *controller) registries(project *models.Project) (adapter.ImageRegistry, adapter.ImageRegistry, error) {
+	if !project.IsProxyCache() {
+		return nil, nil, fmt.Errorf("project %s isn't a proxy cache project", project.Name)
+	}
+	local, err := c.local()
+	if err != nil {
+		return nil, nil, err
+	}
+	remote, err := c.remote(project.ProxyCacheRegistryID())
+	if err != nil {
+		return nil, nil, err
+	}
+	return local, remote, nil
+}
+
+func (c *controller) fresh(key string, staleness time.Duration) bool {
+	c.checkedLock.RLock()
+	defer c.checkedLock.RUnlock()
+
+	checked, ok := c.checked[key]
+	return ok && time.Since(checked.time) < staleness
+}
+
+func (c *controller) markChecked(key string, staleness time.Duration) {
+	c.checkedLock.Lock()
+	defer c.checkedLock.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastPruned) >= pruneInterval {
+		for k, checked := range c.checked {
+			if now.Sub(checked.time) >= checked.staleness {
+				delete(c.checked, k)
+			}
+		}
+		c.lastPruned = now
+	}
+	c.checked[key] = &checkedTag{
+		time:      now,
+		staleness: staleness,
+	}
+}
+
+// upstreamRepository returns the repository in upstream registry by removing the project name,
+// e.g. "dockerhub/library/hello-world" to "library/hello-world"
+func upstreamRepository(project *models.Project, repository string) string {
+	return strings.TrimPrefix(repository, project.Name+"/")
+}
+
+// copyManifest copies the manifest and the content it references from the source registry to the destination,
+// the platform manifests of the manifest list or image index are copied by digest
+func copyManifest(src, dst adapter.ImageRegistry, srcRepo, dstRepo, reference string) error {
+	manifest, _, err := src.PullManifest(srcRepo, reference, registry.ManifestMediaTypes)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range manifest.References() {
+		switch {
+		case registry.IsSupportedManifest(ref.MediaType):
+			if err := copyManifest(src, dst, srcRepo, dstRepo, ref.Digest.String()); err != nil {
+				return err
+			}
+		case ref.MediaType == schema2.MediaTypeForeignLayer:
+			// the foreign layers are pulled from their own URLs by the clients
+			continue
+		default:
+			if err := copyBlob(src, dst, srcRepo, dstRepo, ref.Digest.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	mediaType, payload, err := manifest.Payload()
+	if err != nil {
+		return err
+	}
+	return dst.PushManifest(dstRepo, reference, mediaType, payload)
+}
+
+// copyBlob copies the blob from the source registry to the destination if it doesn't exist in the destination
+func copyBlob(src, dst adapter.ImageRegistry, srcRepo, dstRepo, digest string) error {
+	exist, err := dst.BlobExist(dstRepo, digest)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+
+	size, data, err := src.PullBlob(srcRepo, digest)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	return dst.PushBlob(dstRepo, digest, size, data)
+}
+
+// createLocalRegistry creates the client of the local registry whose requests carry the secret in the SecretHeader,
+// so that the fetched content can be pushed into the proxy cache projects and the requests don't re-enter the controller
+func createLocalRegistry(r *model.Registry) (adapter.ImageRegistry, error) {
+	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
+		Transport: util.GetHTTPTransport(r.Insecure),
+	}, common_http_auth.NewSecretAuthorizer(r.Credential.AccessSecret), r.TokenServiceURL)
+
+	return native.NewAdapterWithCustomizedAuthorizer(r, &secretModifier{
+		authorizer: authorizer,
+		secret:     r.Credential.AccessSecret,
+	})
+}
+
+// secretModifier adds the secret in the SecretHeader to the requests authorized by the authorizer
+type secretModifier struct {
+	authorizer modifier.Modifier
+	secret     string
+}
+
+// Modify the request
+func (s *secretModifier) Modify(req *http.Request) error {
+	req.Header.Set(SecretHeader, s.secret)
+	return s.authorizer.Modify(req)
+}
+
+func createImageRegistry(r *model.Registry) (adapter.ImageRegistry, error) {
+	factory, err := adapter.GetFactory(r.Type)
+	if err != nil {
+		return nil, err
+	}
+	ad, err := factory.Create(r)
+	if err != nil {
+		return nil, err
+	}
+	registry, ok := ad.(adapter.ImageRegistry)
+	if !ok {
+		return nil, errors.New("the adapter doesn't implement the \"ImageRegistry\" interface")
+	}
+	return registry, nil
+}
@@ -0,0 +1,261 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistry struct {
+	manifests map[string][]byte // "repository:reference" -> payload
+	blobs     map[string][]byte // "repository@digest" -> content
+	pulls     int
+	err       error
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+}
+
+func (f *fakeRegistry) FetchImages(filters []*model.Filter) ([]*model.Resource, error) {
+	return nil, nil
+}
+
+func (f *fakeRegistry) ManifestExist(repository, reference string) (bool, string, error) {
+	if f.err != nil {
+		return false, "", f.err
+	}
+	payload, ok := f.manifests[repository+":"+reference]
+	if !ok {
+		return false, "", nil
+	}
+	return true, digest.FromBytes(payload).String(), nil
+}
+
+func (f *fakeRegistry) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
+	f.pulls++
+	payload, ok := f.manifests[repository+":"+reference]
+	if !ok {
+		return nil, "", errors.New("not found")
+	}
+	manifest, _, err := distribution.UnmarshalManifest(schema2.MediaTypeManifest, payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return manifest, digest.FromBytes(payload).String(), nil
+}
+
+func (f *fakeRegistry) PushManifest(repository, reference, mediaType string, payload []byte) error {
+	f.manifests[repository+":"+reference] = payload
+	return nil
+}
+
+func (f *fakeRegistry) DeleteManifest(repository, reference string) error {
+	delete(f.manifests, repository+":"+reference)
+	return nil
+}
+
+func (f *fakeRegistry) BlobExist(repository, digest string) (bool, error) {
+	_, ok := f.blobs[repository+"@"+digest]
+	return ok, nil
+}
+
+func (f *fakeRegistry) PullBlob(repository, digest string) (int64, io.ReadCloser, error) {
+	data, ok := f.blobs[repository+"@"+digest]
+	if !ok {
+		return 0, nil, errors.New("not found")
+	}
+	return int64(len(data)), ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeRegistry) PushBlob(repository, digest string, size int64, blob io.Reader) error {
+	data, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return err
+	}
+	f.blobs[repository+"@"+digest] = data
+	return nil
+}
+
+// addImage adds an image with one layer to the registry and returns the digest of its manifest
+func (f *fakeRegistry) addImage(t *testing.T, repository, tag, layer string) string {
+	config := []byte(`{"architecture":"amd64"}`)
+	manifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: schema2.MediaTypeLayer,
+				Digest:    digest.FromString(layer),
+				Size:      int64(len(layer)),
+			},
+		},
+	})
+	require.Nil(t, err)
+	_, payload, err := manifest.Payload()
+	require.Nil(t, err)
+
+	f.blobs[repository+"@"+digest.FromBytes(config).String()] = config
+	f.blobs[repository+"@"+digest.FromString(layer).String()] = []byte(layer)
+	f.manifests[repository+":"+tag] = payload
+	dgt := digest.FromBytes(payload).String()
+	f.manifests[repository+":"+dgt] = payload
+	return dgt
+}
+
+func newTestController(local, remote *fakeRegistry) *controller {
+	return &controller{
+		remote:  func(registryID int64) (adapter.ImageRegistry, error) { return remote, nil },
+		local:   func() (adapter.ImageRegistry, error) { return local, nil },
+		checked: map[string]*checkedTag{},
+		locks:   newKeyLocker(),
+	}
+}
+
+func newProxyProject(staleness string) *models.Project {
+	return &models.Project{
+		Name: "dockerhub",
+		Metadata: map[string]string{
+			models.ProMetaProxyCacheRegistryID: "1",
+			models.ProMetaProxyCacheStaleness:  staleness,
+		},
+	}
+}
+
+func TestEnsureManifest(t *testing.T) {
+	local, remote := newFakeRegistry(), newFakeRegistry()
+	dgt := remote.addImage(t, "library/hello-world", "latest", "layer1")
+	ctl := newTestController(local, remote)
+	project := newProxyProject("30")
+
+	// fetched when missing
+	require.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", "latest"))
+	exist, localDigest, err := local.ManifestExist("dockerhub/library/hello-world", "latest")
+	require.Nil(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, dgt, localDigest)
+	assert.Equal(t, 2, len(local.blobs))
+	assert.Equal(t, 1, remote.pulls)
+
+	// served from cache within the staleness window even if the upstream changes
+	remote.addImage(t, "library/hello-world", "latest", "layer2")
+	require.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", "latest"))
+	_, localDigest, _ = local.ManifestExist("dockerhub/library/hello-world", "latest")
+	assert.Equal(t, dgt, localDigest)
+	assert.Equal(t, 1, remote.pulls)
+
+	// refreshed when out of the staleness window
+	project = newProxyProject("0")
+	require.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", "latest"))
+	_, localDigest, _ = local.ManifestExist("dockerhub/library/hello-world", "latest")
+	assert.NotEqual(t, dgt, localDigest)
+	assert.Equal(t, 2, remote.pulls)
+
+	// the cached one is served when the upstream is unreachable
+	remote.err = errors.New("unreachable")
+	assert.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", "latest"))
+	assert.NotNil(t, ctl.EnsureManifest(project, "dockerhub/library/busybox", "latest"))
+}
+
+func TestEnsureManifestByDigest(t *testing.T) {
+	local, remote := newFakeRegistry(), newFakeRegistry()
+	dgt := remote.addImage(t, "library/hello-world", "latest", "layer1")
+	ctl := newTestController(local, remote)
+	project := newProxyProject("0")
+
+	require.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", dgt))
+	require.Nil(t, ctl.EnsureManifest(project, "dockerhub/library/hello-world", dgt))
+	// the digest is never checked again once cached
+	assert.Equal(t, 1, remote.pulls)
+}
+
+func TestEnsureBlob(t *testing.T) {
+	local, remote := newFakeRegistry(), newFakeRegistry()
+	remote.blobs["library/hello-world@"+digest.FromString("layer").String()] = []byte("layer")
+	ctl := newTestController(local, remote)
+
+	require.Nil(t, ctl.EnsureBlob(newProxyProject("30"), "dockerhub/library/hello-world", digest.FromString("layer").String()))
+	exist, err := local.BlobExist("dockerhub/library/hello-world", digest.FromString("layer").String())
+	require.Nil(t, err)
+	assert.True(t, exist)
+
+	// not a proxy cache project
+	assert.NotNil(t, ctl.EnsureBlob(&models.Project{Name: "library"}, "library/hello-world", digest.FromString("layer").String()))
+}
+
+type fakeAuthorizer struct{}
+
+func (f *fakeAuthorizer) Modify(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer token")
+	return nil
+}
+
+func TestSecretModifier(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://core/v2/", nil)
+	require.Nil(t, err)
+	m := &secretModifier{authorizer: &fakeAuthorizer{}, secret: "secret"}
+	require.Nil(t, m.Modify(req))
+	assert.Equal(t, "secret", req.Header.Get(SecretHeader))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+}
+
+func TestMarkChecked(t *testing.T) {
+	ctl := newTestController(newFakeRegistry(), newFakeRegistry())
+	ctl.checked["stale"] = &checkedTag{
+		time:      time.Now().Add(-2 * time.Minute),
+		staleness: time.Minute,
+	}
+	ctl.checked["fresh"] = &checkedTag{
+		time:      time.Now(),
+		staleness: time.Minute,
+	}
+
+	// the tags out of their staleness windows are pruned
+	ctl.markChecked("new", time.Minute)
+	assert.Equal(t, 2, len(ctl.checked))
+	assert.True(t, ctl.fresh("fresh", time.Minute))
+	assert.True(t, ctl.fresh("new", time.Minute))
+	assert.False(t, ctl.fresh("stale", time.Minute))
+
+	// not pruned again within the prune interval
+	ctl.checked["stale"] = &checkedTag{
+		time:      time.Now().Add(-2 * time.Minute),
+		staleness: time.Minute,
+	}
+	ctl.markChecked("new", time.Minute)
+	assert.Equal(t, 3, len(ctl.checked))
+}
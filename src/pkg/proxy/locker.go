@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"sync"
+)
+
+// keyLocker serializes the fetches of the same manifest or blob, so the concurrent pulls
+// of the same content only fetch it from the upstream registry once
+type keyLocker struct {
+	lock  sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newKeyLocker() *keyLocker {
+	return &keyLocker{
+		locks: map[string]*keyLock{},
+	}
+}
+
+// Lock locks the key
+func (k *keyLocker) Lock(key string) {
+	k.lock.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.lock.Unlock()
+
+	l.Lock()
+}
+
+// Unlock unlocks the key, the lock is released when no one holds or waits for it
+func (k *keyLocker) Unlock(key string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	l, ok := k.locks[key]
+	if !ok {
+		return
+	}
+	l.refs--
+	if l.refs == 0 {
+		delete(k.locks, key)
+	}
+	l.Unlock()
+}
@@ -60,4 +60,6 @@ type RetentionTask struct {
 	EndTime        time.Time `orm:"column(end_time)"`
 	Total          int       `orm:"column(total)"`
 	Retained       int       `orm:"column(retained)"`
+	// the tags deleted by the task in JSON
+	Deleted string `orm:"column(deleted)"`
 }
@@ -309,3 +309,16 @@ func IsFinalStatus(status string) bool {
 	}
 	return false
 }
+
+// FinishExecution marks the execution as finished, returns false if the execution has been finished by others
+func FinishExecution(id int64) (bool, error) {
+	res, err := dao.GetOrmer().Raw("update retention_execution set finished = true where id = ? and finished = false", id).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -167,6 +167,14 @@ func TestExecution(t *testing.T) {
 	es, err := ListExecutions(policyID, nil)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 1, len(es))
+
+	// only finished once
+	finished, err := FinishExecution(id)
+	require.Nil(t, err)
+	assert.True(t, finished)
+	finished, err = FinishExecution(id)
+	require.Nil(t, err)
+	assert.False(t, finished)
 }
 
 func TestTask(t *testing.T) {
@@ -188,7 +196,8 @@ func TestTask(t *testing.T) {
 	// update
 	task.ID = id
 	task.Total = 1
-	err = UpdateTask(task, "Total")
+	task.Deleted = `[{"tag":"latest"}]`
+	err = UpdateTask(task, "Total", "Deleted")
 	require.Nil(t, err)
 
 	// update status
@@ -203,6 +212,7 @@ func TestTask(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 1, len(tasks))
 	assert.Equal(t, 1, tasks[0].Total)
+	assert.Equal(t, `[{"tag":"latest"}]`, tasks[0].Deleted)
 	assert.Equal(t, int64(1), tasks[0].ExecutionID)
 	assert.Equal(t, "Running", tasks[0].Status)
 	assert.Equal(t, 1, tasks[0].StatusCode)
@@ -15,14 +15,18 @@
 package dep
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/pkg/art"
 	"github.com/goharbor/harbor/src/pkg/clients/core"
+	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 // DefaultClient for the retention
@@ -116,6 +120,17 @@ func (bc *basicClient) GetCandidates(repository *art.Repository) ([]*art.Candida
 				PulledTime:   image.PullTime.Unix(),
 				PushedTime:   image.PushTime.Unix(),
 			}
+			for _, m := range image.Manifests {
+				candidate.References = append(candidate.References, m.Digest)
+			}
+			if overview, ok := image.ScanOverview[v1.MimeTypeNativeReport]; ok {
+				summary, err := toNativeReportSummary(overview)
+				if err != nil {
+					log.Warningf("failed to parse the scan overview of %s/%s:%s: %v", repository.Namespace, repository.Name, image.Name, err)
+				} else {
+					candidate.Vulnerability = summary
+				}
+			}
 			candidates = append(candidates, candidate)
 		}
 	/*
@@ -181,3 +196,16 @@ func (bc *basicClient) Delete(candidate *art.Candidate) error {
 		return fmt.Errorf("unsupported candidate kind: %s", candidate.Kind)
 	}
 }
+
+// toNativeReportSummary converts the scan overview decoded from the response of core into the native report summary
+func toNativeReportSummary(overview interface{}) (*vuln.NativeReportSummary, error) {
+	data, err := json.Marshal(overview)
+	if err != nil {
+		return nil, err
+	}
+	summary := &vuln.NativeReportSummary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/art"
+	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/testing/clients"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,6 +39,25 @@ type fakeCoreClient struct {
 func (f *fakeCoreClient) ListAllImages(project, repository string) ([]*models.TagResp, error) {
 	image := &models.TagResp{}
 	image.Name = "latest"
+	image.Manifests = []*models.PlatformManifest{
+		{Digest: "sha256:amd64"},
+		{Digest: "sha256:arm64"},
+	}
+	// the scan overview is decoded from the JSON response of core
+	image.ScanOverview = map[string]interface{}{
+		v1.MimeTypeNativeReport: map[string]interface{}{
+			"scan_status": "Success",
+			"severity":    "High",
+			"summary": map[string]interface{}{
+				"total":   float64(2),
+				"fixable": float64(1),
+				"summary": map[string]interface{}{
+					"High": float64(1),
+					"Low":  float64(1),
+				},
+			},
+		},
+	}
 	return []*models.TagResp{image}, nil
 }
 
@@ -90,6 +111,10 @@ func (c *clientTestSuite) TestGetCandidates() {
 	assert.Equal(c.T(), "library", candidates[0].Namespace)
 	assert.Equal(c.T(), "hello-world", candidates[0].Repository)
 	assert.Equal(c.T(), "latest", candidates[0].Tag)
+	assert.Equal(c.T(), []string{"sha256:amd64", "sha256:arm64"}, candidates[0].References)
+	require.True(c.T(), candidates[0].Scanned())
+	assert.Equal(c.T(), vuln.High, candidates[0].Vulnerability.Severity)
+	assert.Equal(c.T(), 1, candidates[0].Vulnerability.Summary.Summary[vuln.Low])
 
 	/*
 		// chart repository
@@ -118,19 +118,20 @@ func (pj *Job) Run(ctx job.Context, params job.Parameters) error {
 }
 
 func saveRetainNum(ctx job.Context, results []*art.Result, allCandidates []*art.Candidate) error {
-	var delNum int
+	retainObj := &CheckIn{
+		Total: len(allCandidates),
+	}
 	for _, r := range results {
 		if r.Error == nil {
-			delNum++
+			d := &DeletedTag{}
+			if r.Target != nil {
+				d.Tag = r.Target.Tag
+				d.Digest = r.Target.Digest
+			}
+			retainObj.Deleted = append(retainObj.Deleted, d)
 		}
 	}
-	retainObj := struct {
-		Total    int `json:"total"`
-		Retained int `json:"retained"`
-	}{
-		Total:    len(allCandidates),
-		Retained: len(allCandidates) - delNum,
-	}
+	retainObj.Retained = retainObj.Total - len(retainObj.Deleted)
 	c, err := json.Marshal(retainObj)
 	if err != nil {
 		return err
@@ -129,6 +129,9 @@ func (f *fakeRetentionManager) UpdateTask(task *Task, cols ...string) error {
 func (f *fakeRetentionManager) UpdateTaskStatus(int64, string, int64) error {
 	return nil
 }
+func (f *fakeRetentionManager) FinishExecution(eid int64) (bool, error) {
+	return true, nil
+}
 func (f *fakeRetentionManager) GetTaskLog(taskID int64) ([]byte, error) {
 	return nil, nil
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/astaxie/beego/orm"
 	cjob "github.com/goharbor/harbor/src/common/job"
+	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/retention/dao"
 	"github.com/goharbor/harbor/src/pkg/retention/dao/models"
@@ -67,6 +68,9 @@ type Manager interface {
 	GetTask(taskID int64) (*Task, error)
 	// Get the log of the specified task
 	GetTaskLog(taskID int64) ([]byte, error)
+	// Mark the execution whose tasks are all finished as finished, returns false
+	// if it has been marked by others
+	FinishExecution(eid int64) (bool, error)
 }
 
 // DefaultManager ...
@@ -205,6 +209,7 @@ func (d *DefaultManager) CreateTask(task *Task) (int64, error) {
 		EndTime:        task.EndTime,
 		Total:          task.Total,
 		Retained:       task.Retained,
+		Deleted:        marshalDeletedTags(task.Deleted),
 	}
 	return dao.CreateTask(t)
 }
@@ -232,6 +237,7 @@ func (d *DefaultManager) ListTasks(query ...*q.TaskQuery) ([]*Task, error) {
 			EndTime:        t.EndTime,
 			Total:          t.Total,
 			Retained:       t.Retained,
+			Deleted:        unmarshalDeletedTags(t.Deleted),
 		})
 	}
 	return tasks, nil
@@ -262,6 +268,7 @@ func (d *DefaultManager) UpdateTask(task *Task, cols ...string) error {
 		EndTime:        task.EndTime,
 		Total:          task.Total,
 		Retained:       task.Retained,
+		Deleted:        marshalDeletedTags(task.Deleted),
 	}, cols...)
 }
 
@@ -295,6 +302,7 @@ func (d *DefaultManager) GetTask(taskID int64) (*Task, error) {
 		EndTime:        task.EndTime,
 		Total:          task.Total,
 		Retained:       task.Retained,
+		Deleted:        unmarshalDeletedTags(task.Deleted),
 	}, nil
 }
 
@@ -314,3 +322,32 @@ func (d *DefaultManager) GetTaskLog(taskID int64) ([]byte, error) {
 func NewManager() Manager {
 	return &DefaultManager{}
 }
+
+// FinishExecution marks the execution as finished
+func (d *DefaultManager) FinishExecution(eid int64) (bool, error) {
+	return dao.FinishExecution(eid)
+}
+
+func marshalDeletedTags(tags []*DeletedTag) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		log.Errorf("failed to marshal the deleted tags: %v", err)
+		return ""
+	}
+	return string(data)
+}
+
+func unmarshalDeletedTags(data string) []*DeletedTag {
+	if len(data) == 0 {
+		return nil
+	}
+	tags := []*DeletedTag{}
+	if err := json.Unmarshal([]byte(data), &tags); err != nil {
+		log.Errorf("failed to unmarshal the deleted tags: %v", err)
+		return nil
+	}
+	return tags
+}
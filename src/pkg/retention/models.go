@@ -54,6 +54,8 @@ type Task struct {
 	EndTime        time.Time `json:"end_time"`
 	Total          int       `json:"total"`
 	Retained       int       `json:"retained"`
+	// Deleted are the tags deleted by the task, or to be deleted in dry run mode
+	Deleted []*DeletedTag `json:"-"`
 }
 
 // History of retention
@@ -68,3 +70,17 @@ type History struct {
 	Artifact  string    `json:"tag"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// CheckIn is the data checked in by the retention job of a repository
+type CheckIn struct {
+	Total    int `json:"total"`
+	Retained int `json:"retained"`
+	// Deleted are the tags deleted, or to be deleted in dry run mode
+	Deleted []*DeletedTag `json:"deleted,omitempty"`
+}
+
+// DeletedTag is the tag deleted by the retention job
+type DeletedTag struct {
+	Tag    string `json:"tag"`
+	Digest string `json:"digest"`
+}
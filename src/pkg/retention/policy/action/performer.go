@@ -59,6 +59,11 @@ func (ra *retainAction) Perform(candidates []*art.Candidate) (results []*art.Res
 	for _, c := range candidates {
 		retained[c.NameHash()] = true
 		retainedShare[c.Hash()] = true
+		// the platform specific manifests of the retained manifest list can't be deleted,
+		// otherwise the manifest list is broken
+		for _, h := range c.ReferenceHashes() {
+			retainedShare[h] = true
+		}
 	}
 
 	for _, c := range ra.all {
@@ -101,6 +101,47 @@ func (suite *TestPerformerSuite) TestPerform() {
 	assert.Equal(suite.T(), "dev", results[0].Target.Tag)
 }
 
+// TestPerformManifestList tests Perform action with the retained manifest list
+func (suite *TestPerformerSuite) TestPerformManifestList() {
+	all := []*art.Candidate{
+		{
+			Namespace:  "library",
+			Repository: "harbor",
+			Kind:       "image",
+			Tag:        "latest",
+			Digest:     "list",
+			PushedTime: time.Now().Unix(),
+			References: []string{"amd64", "arm64"},
+		},
+		{
+			Namespace:  "library",
+			Repository: "harbor",
+			Kind:       "image",
+			Tag:        "latest-amd64",
+			Digest:     "amd64",
+			PushedTime: time.Now().Unix(),
+		},
+		{
+			Namespace:  "library",
+			Repository: "harbor",
+			Kind:       "image",
+			Tag:        "dev",
+			Digest:     "dev",
+			PushedTime: time.Now().Unix(),
+		},
+	}
+	p := &retainAction{
+		all: all,
+	}
+
+	results, err := p.Perform(all[:1])
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, len(results))
+	require.NotNil(suite.T(), results[0].Target)
+	assert.NoError(suite.T(), results[0].Error)
+	assert.Equal(suite.T(), "dev", results[0].Target.Tag)
+}
+
 // TestPerform tests Perform action
 func (suite *TestPerformerSuite) TestPerformImmutable() {
 	all := []*art.Candidate{
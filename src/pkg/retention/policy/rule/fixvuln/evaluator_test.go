@@ -0,0 +1,136 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixvuln
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/pkg/art"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name      string
+		args      rule.Parameters
+		expectedN int
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterN: float64(5)}, expectedN: 5},
+		{Name: "Default If Negative", args: map[string]rule.Parameter{ParameterN: float64(-1)}, expectedN: DefaultN},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedN: DefaultN},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterN: "foo"}, expectedN: DefaultN},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedN, e.n)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	now := time.Now().UTC()
+	data := []*art.Candidate{
+		{Tag: "critical-1", PushedTime: daysAgo(now, 1).Unix(), Vulnerability: scanned(1, 1, daysAgo(now, 1))},
+		{Tag: "critical-10", PushedTime: daysAgo(now, 10).Unix(), Vulnerability: scanned(2, 1, daysAgo(now, 10))},
+		{Tag: "critical-40", PushedTime: daysAgo(now, 40).Unix(), Vulnerability: scanned(1, 1, daysAgo(now, 40))},
+		{Tag: "unfixable-40", PushedTime: daysAgo(now, 40).Unix(), Vulnerability: scanned(1, 0, daysAgo(now, 40))},
+		{Tag: "unscanned-40", PushedTime: daysAgo(now, 40).Unix()},
+		// the rescan doesn't reset the period
+		{Tag: "critical-40-rescanned-1", PushedTime: daysAgo(now, 40).Unix(), Vulnerability: scanned(1, 1, daysAgo(now, 1))},
+	}
+
+	tests := []struct {
+		n        float64
+		expected []string
+	}{
+		{n: 0, expected: []string{"unfixable-40", "unscanned-40"}},
+		{n: 5, expected: []string{"critical-1", "unfixable-40", "unscanned-40"}},
+		{n: 30, expected: []string{"critical-1", "critical-10", "unfixable-40", "unscanned-40"}},
+		{n: 90, expected: []string{"critical-1", "critical-10", "critical-40", "unfixable-40", "unscanned-40",
+			"critical-40-rescanned-1"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(fmt.Sprintf("%v", tt.n), func(t *testing.T) {
+			sut := New(map[string]rule.Parameter{ParameterN: tt.n})
+
+			result, err := sut.Process(data)
+
+			require.NoError(t, err)
+			var tags []string
+			for _, v := range result {
+				tags = append(tags, v.Tag)
+			}
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name     string
+		args     rule.Parameters
+		expected error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterN: 5}, expected: nil},
+		{Name: "Negative", args: map[string]rule.Parameter{ParameterN: -1}, expected: errors.New("nDaysWithFixableCritical is less than zero")},
+		{Name: "Big", args: map[string]rule.Parameter{ParameterN: 21000000}, expected: errors.New("nDaysWithFixableCritical is too large")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestEvaluatorSuite(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
+
+func daysAgo(from time.Time, n int) time.Time {
+	return from.Add(time.Duration(-1*24*n)*time.Hour - time.Hour)
+}
+
+func scanned(critical, fixableCritical int, scanTime time.Time) *vuln.NativeReportSummary {
+	return &vuln.NativeReportSummary{
+		ScanStatus: "Success",
+		Severity:   vuln.Critical,
+		StartTime:  scanTime,
+		EndTime:    scanTime,
+		Summary: &vuln.VulnerabilitySummary{
+			Total:          critical,
+			Fixable:        fixableCritical,
+			Summary:        vuln.SeveritySummary{vuln.Critical: critical},
+			FixableSummary: vuln.SeveritySummary{vuln.Critical: fixableCritical},
+		},
+	}
+}
@@ -22,10 +22,12 @@ import (
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/always"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/dayspl"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/daysps"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/fixvuln"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/lastx"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestk"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpl"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestps"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/severity"
 	"github.com/pkg/errors"
 )
 
@@ -163,6 +165,34 @@ func init() {
 			},
 		},
 	}, daysps.New, daysps.Valid)
+
+	// Register severity
+	Register(&Metadata{
+		TemplateID: severity.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     severity.ParameterSeverity,
+				Type:     "string",
+				Unit:     "severity",
+				Required: true,
+			},
+		},
+	}, severity.New, severity.Valid)
+
+	// Register fixvuln
+	Register(&Metadata{
+		TemplateID: fixvuln.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     fixvuln.ParameterN,
+				Type:     "int",
+				Unit:     "days",
+				Required: true,
+			},
+		},
+	}, fixvuln.New, fixvuln.Valid)
 }
 
 // Register the rule evaluator with the corresponding rule template
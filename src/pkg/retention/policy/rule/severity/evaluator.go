@@ -0,0 +1,102 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package severity
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/art"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+const (
+	// TemplateID of the rule
+	TemplateID = "severityBelow"
+
+	// ParameterSeverity is the name of the metadata parameter for the severity value
+	ParameterSeverity = TemplateID
+
+	// DefaultSeverity is the default severity that the highest severity of the
+	// retained artifacts must be below.
+	DefaultSeverity = vuln.High
+)
+
+// severities which can be used as the parameter of the rule
+var severities = []vuln.Severity{vuln.Negligible, vuln.Low, vuln.Medium, vuln.High, vuln.Critical}
+
+type evaluator struct {
+	severity vuln.Severity
+}
+
+// Process retains the scanned artifacts whose highest severity is below the specified one,
+// the artifacts not scanned yet are retained as their vulnerabilities are unknown
+func (e *evaluator) Process(artifacts []*art.Candidate) (result []*art.Candidate, err error) {
+	for _, a := range artifacts {
+		if !a.Scanned() || a.Vulnerability.Severity.Code() < e.severity.Code() {
+			result = append(result, a)
+		}
+	}
+
+	return
+}
+
+func (e *evaluator) Action() string {
+	return action.Retain
+}
+
+// New constructs a new 'Severity Below' evaluator
+func New(params rule.Parameters) rule.Evaluator {
+	if params != nil {
+		if p, ok := params[ParameterSeverity]; ok {
+			if v, ok := p.(string); ok {
+				if severity, ok := parseSeverity(v); ok {
+					return &evaluator{severity: severity}
+				}
+			}
+		}
+	}
+
+	log.Warningf("default parameter %s used for rule %s", DefaultSeverity, TemplateID)
+
+	return &evaluator{severity: DefaultSeverity}
+}
+
+// Valid ...
+func Valid(params rule.Parameters) error {
+	if params != nil {
+		if p, ok := params[ParameterSeverity]; ok {
+			v, ok := p.(string)
+			if !ok {
+				return fmt.Errorf("%s type error", ParameterSeverity)
+			}
+			if _, ok := parseSeverity(v); !ok {
+				return fmt.Errorf("%s is invalid", ParameterSeverity)
+			}
+		}
+	}
+	return nil
+}
+
+func parseSeverity(s string) (vuln.Severity, bool) {
+	for _, severity := range severities {
+		if string(severity) == s {
+			return severity, true
+		}
+	}
+	return "", false
+}
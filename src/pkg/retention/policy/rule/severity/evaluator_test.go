@@ -0,0 +1,120 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package severity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goharbor/harbor/src/pkg/art"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name             string
+		args             rule.Parameters
+		expectedSeverity vuln.Severity
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "Medium"}, expectedSeverity: vuln.Medium},
+		{Name: "Default If Invalid", args: map[string]rule.Parameter{ParameterSeverity: "foo"}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: 1}, expectedSeverity: DefaultSeverity},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedSeverity, e.severity)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	data := []*art.Candidate{
+		{Tag: "none", Vulnerability: scanned(vuln.None)},
+		{Tag: "low", Vulnerability: scanned(vuln.Low)},
+		{Tag: "medium", Vulnerability: scanned(vuln.Medium)},
+		{Tag: "high", Vulnerability: scanned(vuln.High)},
+		{Tag: "critical", Vulnerability: scanned(vuln.Critical)},
+		{Tag: "unscanned"},
+		{Tag: "running", Vulnerability: &vuln.NativeReportSummary{ScanStatus: "Running"}},
+	}
+
+	tests := []struct {
+		severity string
+		expected []string
+	}{
+		{severity: "Low", expected: []string{"none", "unscanned", "running"}},
+		{severity: "High", expected: []string{"none", "low", "medium", "unscanned", "running"}},
+		{severity: "Critical", expected: []string{"none", "low", "medium", "high", "unscanned", "running"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.severity, func(t *testing.T) {
+			sut := New(map[string]rule.Parameter{ParameterSeverity: tt.severity})
+
+			result, err := sut.Process(data)
+
+			require.NoError(t, err)
+			var tags []string
+			for _, v := range result {
+				tags = append(tags, v.Tag)
+			}
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name     string
+		args     rule.Parameters
+		expected error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "High"}, expected: nil},
+		{Name: "Invalid", args: map[string]rule.Parameter{ParameterSeverity: "None"}, expected: errors.New("severityBelow is invalid")},
+		{Name: "Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: 1}, expected: errors.New("severityBelow type error")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestEvaluatorSuite(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
+
+func scanned(severity vuln.Severity) *vuln.NativeReportSummary {
+	return &vuln.NativeReportSummary{
+		ScanStatus: "Success",
+		Severity:   severity,
+		Summary:    &vuln.VulnerabilitySummary{},
+	}
+}
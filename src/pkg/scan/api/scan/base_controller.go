@@ -15,7 +15,10 @@
 package scan
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"time"
 
 	cj "github.com/goharbor/harbor/src/common/job"
 	jm "github.com/goharbor/harbor/src/common/job/models"
@@ -34,6 +37,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/scan/errs"
 	"github.com/goharbor/harbor/src/pkg/scan/report"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
@@ -333,6 +337,38 @@ func (bc *basicController) GetScanLog(uuid string) ([]byte, error) {
 	return bc.jc().GetJobLog(sr.JobID)
 }
 
+// FollowScanLog ...
+func (bc *basicController) FollowScanLog(ctx context.Context, uuid string, offset int64, w io.Writer) (bool, error) {
+	if len(uuid) == 0 {
+		return false, errors.New("empty uuid to follow scan log")
+	}
+
+	// Get by uuid
+	sr, err := bc.manager.Get(uuid)
+	if err != nil {
+		return false, errors.Wrap(err, "scan controller: follow scan log")
+	}
+
+	if sr == nil {
+		// Not found
+		return false, nil
+	}
+
+	// Not job error
+	if sr.StatusCode == job.ErrorStatus.Code() {
+		jst := job.Status(sr.Status)
+		if jst.Code() == -1 {
+			if offset < int64(len(sr.Status)) {
+				_, err = w.Write([]byte(sr.Status)[offset:])
+			}
+			return true, err
+		}
+	}
+
+	// Job log
+	return true, bc.jc().FollowJobLog(ctx, sr.JobID, offset, w)
+}
+
 // HandleJobHooks ...
 func (bc *basicController) HandleJobHooks(trackID string, change *job.StatusChange) error {
 	if len(trackID) == 0 {
@@ -417,6 +453,89 @@ func (bc *basicController) GetStats(requester string) (*all.Stats, error) {
 	return sts, nil
 }
 
+// ImportReport ...
+func (bc *basicController) ImportReport(artifact *v1.Artifact, mimeType string, rawReport string) error {
+	if artifact == nil {
+		return errors.New("nil artifact to import report for")
+	}
+
+	raw, err := report.ResolveData(mimeType, []byte(rawReport))
+	if err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+	rp, ok := raw.(*vuln.Report)
+	if !ok || rp.Scanner == nil {
+		return errors.Errorf("scan controller: import report: no scanner info in the report with mime type %s", mimeType)
+	}
+
+	// The imported report is attached to the scanner which generated it
+	r, err := bc.getRegistrationOfScanner(artifact.NamespaceID, rp.Scanner)
+	if err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+	if r == nil {
+		return errs.WithCode(errs.PreconditionFailed, errs.Errorf("no registered scanner matches the scanner %s of %s", rp.Scanner.Name, rp.Scanner.Vendor))
+	}
+
+	trackID, err := bc.uuid()
+	if err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+
+	UUID, err := bc.manager.Create(&scan.Report{
+		Digest:           artifact.Digest,
+		RegistrationUUID: r.UUID,
+		Status:           job.PendingStatus.String(),
+		StatusCode:       job.PendingStatus.Code(),
+		TrackID:          trackID,
+		Requester:        trackID,
+		MimeType:         mimeType,
+	})
+	if err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+
+	rev := time.Now().Unix()
+	if err := bc.manager.UpdateReportData(UUID, rawReport, rev); err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+
+	if err := bc.manager.UpdateStatus(trackID, job.SuccessStatus.String(), rev); err != nil {
+		return errors.Wrap(err, "scan controller: import report")
+	}
+
+	return nil
+}
+
+// getRegistrationOfScanner returns the registration whose adapter is the given scanner, the scanner
+// of the project is checked first to avoid requesting the metadata of all the registrations
+func (bc *basicController) getRegistrationOfScanner(projectID int64, s *v1.Scanner) (*scanner.Registration, error) {
+	r, err := bc.sc.GetRegistrationByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if r != nil && r.Adapter == s.Name && r.Vendor == s.Vendor {
+		return r, nil
+	}
+
+	registrations, err := bc.sc.ListRegistrations(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range registrations {
+		meta, err := bc.sc.GetMetadata(r.UUID)
+		if err != nil {
+			// the scanner may be unhealthy
+			logger.Warningf("failed to get the metadata of scanner %s: %v", r.Name, err)
+			continue
+		}
+		if meta.Scanner != nil && meta.Scanner.Name == s.Name && meta.Scanner.Vendor == s.Vendor {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
 func (bc *basicController) createRobotAccount(projectID int64, repository string) (*model.Robot, error) {
 	// Use uuid as name to avoid duplicated entries.
 	UUID, err := bc.uuid()
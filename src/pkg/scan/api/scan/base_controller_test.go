@@ -15,8 +15,11 @@
 package scan
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -32,8 +35,10 @@ import (
 	"github.com/goharbor/harbor/src/pkg/scan/all"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
+	"github.com/goharbor/harbor/src/pkg/scan/errs"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -96,6 +101,12 @@ func (suite *ControllerTestSuite) SetupSuite() {
 	sc := &MockScannerController{}
 	sc.On("GetRegistrationByProject", suite.artifact.NamespaceID).Return(suite.registration, nil)
 	sc.On("Ping", suite.registration).Return(m, nil)
+	sc.On("ListRegistrations", (*q.Query)(nil)).Return([]*scanner.Registration{
+		{UUID: "uuid002", Name: "Test-unreachable"},
+		suite.registration,
+	}, nil)
+	sc.On("GetMetadata", "uuid002").Return(nil, errors.New("unreachable"))
+	sc.On("GetMetadata", suite.registration.UUID).Return(m, nil)
 
 	mgr := &MockReportManager{}
 	mgr.On("Create", &scan.Report{
@@ -157,6 +168,9 @@ func (suite *ControllerTestSuite) SetupSuite() {
 	mgr.On("UpdateReportData", "rp-uuid-001", suite.rawReport, (int64)(10000)).Return(nil)
 	mgr.On("UpdateStatus", "the-uuid-123", "Success", (int64)(10000)).Return(nil)
 
+	mgr.On("UpdateReportData", "r-uuid", suite.rawReport, mock.AnythingOfType("int64")).Return(nil)
+	mgr.On("UpdateStatus", "the-uuid-123", "Success", mock.AnythingOfType("int64")).Return(nil)
+
 	rc := &MockRobotController{}
 
 	resource := fmt.Sprintf("/project/%d/repository", suite.artifact.NamespaceID)
@@ -216,6 +230,7 @@ func (suite *ControllerTestSuite) SetupSuite() {
 	}
 	jc.On("SubmitJob", j).Return("the-job-id", nil)
 	jc.On("GetJobLog", "the-job-id").Return([]byte("job log"), nil)
+	jc.On("FollowJobLog", "the-job-id", (int64)(4)).Return([]byte(" log"), nil)
 
 	suite.c = &basicController{
 		manager: mgr,
@@ -273,6 +288,15 @@ func (suite *ControllerTestSuite) TestScanControllerGetScanLog() {
 	})
 }
 
+// TestScanControllerFollowScanLog ...
+func (suite *ControllerTestSuite) TestScanControllerFollowScanLog() {
+	buf := &bytes.Buffer{}
+	found, err := suite.c.FollowScanLog(context.Background(), "rp-uuid-001", 4, buf)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), " log", buf.String())
+}
+
 // TestScanControllerHandleJobHooks ...
 func (suite *ControllerTestSuite) TestScanControllerHandleJobHooks() {
 	cReport := &sca.CheckInReport{
@@ -298,6 +322,26 @@ func (suite *ControllerTestSuite) TestScanControllerHandleJobHooks() {
 	require.NoError(suite.T(), err)
 }
 
+// TestScanControllerImportReport ...
+func (suite *ControllerTestSuite) TestScanControllerImportReport() {
+	err := suite.c.ImportReport(suite.artifact, v1.MimeTypeNativeReport, suite.rawReport)
+	require.NoError(suite.T(), err)
+
+	// no registered scanner generated the report
+	rp := &vuln.Report{
+		Scanner: &v1.Scanner{
+			Name:    "Trivy",
+			Vendor:  "Aqua Security",
+			Version: "0.1.0",
+		},
+	}
+	jsonData, err := json.Marshal(rp)
+	require.NoError(suite.T(), err)
+	err = suite.c.ImportReport(suite.artifact, v1.MimeTypeNativeReport, string(jsonData))
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), errs.AsError(err, errs.PreconditionFailed))
+}
+
 // Mock things
 
 // MockReportManager ...
@@ -502,6 +546,18 @@ func (mjc *MockJobServiceClient) GetJobLog(uuid string) ([]byte, error) {
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+// FollowJobLog ...
+func (mjc *MockJobServiceClient) FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error {
+	args := mjc.Called(uuid, offset)
+	if args.Get(0) != nil {
+		if _, err := w.Write(args.Get(0).([]byte)); err != nil {
+			return err
+		}
+	}
+
+	return args.Error(1)
+}
+
 // PostAction ...
 func (mjc *MockJobServiceClient) PostAction(uuid, action string) error {
 	args := mjc.Called(uuid, action)
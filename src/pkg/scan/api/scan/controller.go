@@ -15,6 +15,9 @@
 package scan
 
 import (
+	"context"
+	"io"
+
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/scan/all"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
@@ -69,6 +72,19 @@ type Controller interface {
 	//     error  : non nil error if any errors occurred
 	GetScanLog(uuid string) ([]byte, error)
 
+	// Follow the scan log of the specified scan report as the log grows until the scan job is done
+	//
+	//   Arguments:
+	//     ctx context.Context : the context to stop following
+	//     uuid string         : the UUID of the scan report
+	//     offset int64        : the number of bytes of the log to skip
+	//     w io.Writer         : the writer of the log text stream
+	//
+	//   Returns:
+	//     bool  : false if the scan report does not exist
+	//     error : non nil error if any errors occurred
+	FollowScanLog(ctx context.Context, uuid string, offset int64, w io.Writer) (bool, error)
+
 	// HandleJobHooks handle the hook events from the job service
 	// e.g : status change of the scan job or scan result
 	//
@@ -89,6 +105,19 @@ type Controller interface {
 	//    error        : non nil error if any errors occurred
 	DeleteReports(digests ...string) error
 
+	// ImportReport imports the report generated somewhere else, e.g. the report replicated
+	// from a remote Harbor, as the report of the given artifact. The report is recorded as the
+	// one of the registered scanner which generated it.
+	//
+	//  Arguments:
+	//    artifact *v1.Artifact : the artifact which the report belongs to
+	//    mimeType string       : the mime type of the report
+	//    rawReport string      : the JSON data of the report
+	//
+	//  Returns:
+	//    error        : non nil error if any errors occurred
+	ImportReport(artifact *v1.Artifact, mimeType string, rawReport string) error
+
 	// Get the stats of the scan reports requested by the given requester.
 	//
 	//  Arguments:
@@ -29,6 +29,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils/registry/auth"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/pkg/robot/model"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
 	"github.com/goharbor/harbor/src/pkg/scan/report"
@@ -131,7 +132,17 @@ func (j *Job) Validate(params job.Parameters) error {
 }
 
 // Run the job
-func (j *Job) Run(ctx job.Context, params job.Parameters) error {
+func (j *Job) Run(ctx job.Context, params job.Parameters) (err error) {
+	// Record the duration of the scan
+	startTime := time.Now()
+	defer func() {
+		status := job.SuccessStatus
+		if err != nil {
+			status = job.ErrorStatus
+		}
+		metrics.ScanDurationSeconds.WithLabelValues(status.String()).Observe(time.Since(startTime).Seconds())
+	}()
+
 	// Get logger
 	myLogger := ctx.GetLogger()
 
@@ -117,8 +117,9 @@ func GenerateNativeSummary(r *scan.Report, options ...Option) (interface{}, erro
 
 	sum.Severity = rp.Severity
 	vsum := &vuln.VulnerabilitySummary{
-		Total:   len(rp.Vulnerabilities),
-		Summary: make(vuln.SeveritySummary),
+		Total:          len(rp.Vulnerabilities),
+		Summary:        make(vuln.SeveritySummary),
+		FixableSummary: make(vuln.SeveritySummary),
 	}
 
 	overallSev := vuln.None
@@ -147,6 +148,7 @@ func GenerateNativeSummary(r *scan.Report, options ...Option) (interface{}, erro
 		// If the CVE item has a fixable version
 		if len(v.FixVersion) > 0 {
 			vsum.Fixable++
+			vsum.FixableSummary[v.Severity]++
 		}
 	}
 	sum.Summary = vsum
@@ -100,6 +100,9 @@ func (suite *SummaryTestSuite) TestSummaryGenerateSummaryNoOptions() {
 	suite.Equal(vuln.High, nativeSummary.Severity)
 	suite.Nil(nativeSummary.CVEBypassed)
 	suite.Equal(2, nativeSummary.Summary.Total)
+	suite.Equal(2, nativeSummary.Summary.Fixable)
+	suite.Equal(1, nativeSummary.Summary.FixableSummary[vuln.High])
+	suite.Equal(1, nativeSummary.Summary.FixableSummary[vuln.Medium])
 
 	suite.Equal("Clair", nativeSummary.Scanner.Name)
 	suite.Equal("Harbor", nativeSummary.Scanner.Vendor)
@@ -121,6 +124,7 @@ func (suite *SummaryTestSuite) TestSummaryGenerateSummaryWithOptions() {
 	suite.Equal(vuln.Medium, nativeSummary.Severity)
 	suite.Equal(1, len(nativeSummary.CVEBypassed))
 	suite.Equal(1, nativeSummary.Summary.Total)
+	suite.Equal(0, nativeSummary.Summary.FixableSummary[vuln.High])
 }
 
 // TestSummaryGenerateSummaryWrongMime ...
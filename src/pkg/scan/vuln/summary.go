@@ -40,6 +40,8 @@ type VulnerabilitySummary struct {
 	Total   int             `json:"total"`
 	Fixable int             `json:"fixable"`
 	Summary SeveritySummary `json:"summary"`
+	// FixableSummary is the numbers of the fixable vulnerabilities of each severity level
+	FixableSummary SeveritySummary `json:"fixable_summary,omitempty"`
 }
 
 // SeveritySummary ...
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/registryctl/storage"
+	"github.com/gorilla/mux"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	driver     storage.Driver
+	driverErr  error
+	driverOnce sync.Once
+)
+
+// getDriver returns the storage driver created with the registry configuration
+var getDriver = func() (storage.Driver, error) {
+	driverOnce.Do(func() {
+		driver, driverErr = storage.NewDriverFromConfig(regConf)
+	})
+	return driver, driverErr
+}
+
+// DeleteBlob deletes the data and the repository links of the blob from the storage of registry, it's used by the online garbage collection
+func DeleteBlob(w http.ResponseWriter, r *http.Request) {
+	dgt, err := digest.Parse(mux.Vars(r)["reference"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := getDriver()
+	if err != nil {
+		log.Errorf("failed to get the storage driver of registry: %v", err)
+		handleInternalServerError(w)
+		return
+	}
+
+	if err := d.DeleteBlob(r.Context(), dgt); err != nil {
+		if _, ok := err.(storagedriver.PathNotFoundError); ok {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		log.Errorf("failed to delete blob %s: %v", dgt, err)
+		handleInternalServerError(w)
+		return
+	}
+
+	log.Debugf("blob %s deleted", dgt)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/docker/distribution/registry/storage/driver/inmemory"
+	"github.com/goharbor/harbor/src/registryctl/storage"
+	"github.com/gorilla/mux"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteBlob(t *testing.T) {
+	mem := inmemory.New()
+	getDriver = func() (storage.Driver, error) {
+		return storage.NewDriver(mem), nil
+	}
+
+	dgt := digest.FromString("blob")
+	dataPath := path.Join(storage.BlobDataPath(dgt), "data")
+	require.Nil(t, mem.PutContent(context.Background(), dataPath, []byte("blob")))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/registry/blob/{reference}", DeleteBlob).Methods(http.MethodDelete)
+
+	// delete the existing blob
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/registry/blob/"+dgt.String(), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	_, err := mem.Stat(context.Background(), dataPath)
+	assert.NotNil(t, err)
+
+	// delete it again
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// invalid digest
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/api/registry/blob/invalid", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -16,6 +16,8 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -36,9 +38,25 @@ type GCResult struct {
 	EndTime   time.Time `json:"endtime"`
 }
 
+// GCOptions are the options of the registry garbage collection
+type GCOptions struct {
+	// DeleteUntagged deletes the manifests which are not currently referenced by any tag
+	DeleteUntagged bool `json:"delete_untagged"`
+}
+
 // StartGC ...
 func StartGC(w http.ResponseWriter, r *http.Request) {
-	cmd := exec.Command("/bin/bash", "-c", "registry garbage-collect --delete-untagged=true "+regConf)
+	// the untagged manifests are deleted if no options provided to keep the compatibility with the old clients
+	opts := &GCOptions{DeleteUntagged: true}
+	if r.ContentLength != 0 && r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(opts); err != nil {
+			log.Errorf("failed to decode the gc options: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	cmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("registry garbage-collect --delete-untagged=%t %s", opts.DeleteUntagged, regConf))
 	var outBuf, errBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &errBuf
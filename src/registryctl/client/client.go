@@ -15,8 +15,10 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -32,8 +34,11 @@ import (
 type Client interface {
 	// Health tests the connection with registry server
 	Health() error
-	// StartGC enable the gc of registry server
-	StartGC() (*api.GCResult, error)
+	// StartGC enable the gc of registry server, the default options of registryctl are used if opts is nil
+	StartGC(opts *api.GCOptions) (*api.GCResult, error)
+	// DeleteBlob deletes the data and the repository links of the blob from the storage of registry,
+	// it's not an error if the blob doesn't exist
+	DeleteBlob(reference string) error
 }
 
 type client struct {
@@ -72,11 +77,20 @@ func (c *client) Health() error {
 }
 
 // StartGC ...
-func (c *client) StartGC() (*api.GCResult, error) {
+func (c *client) StartGC(opts *api.GCOptions) (*api.GCResult, error) {
 	url := c.baseURL + "/api/registry/gc"
 	gcr := &api.GCResult{}
 
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+	var body io.Reader
+	if opts != nil {
+		data, err := json.Marshal(opts)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -100,3 +114,24 @@ func (c *client) StartGC() (*api.GCResult, error) {
 
 	return gcr, nil
 }
+
+// DeleteBlob ...
+func (c *client) DeleteBlob(reference string) error {
+	url := c.baseURL + "/api/registry/blob/" + reference
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		log.Errorf("Failed to delete blob %s: %d", reference, resp.StatusCode)
+		return fmt.Errorf("Failed to delete blob %s: %d", reference, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -33,6 +33,8 @@ type Configuration struct {
 		Cert string `yaml:"cert"`
 		Key  string `yaml:"key"`
 	} `yaml:"https_config,omitempty"`
+	// The address the metrics are exposed on, e.g. ":9090", empty means disabled
+	MetricAddr string `yaml:"metric_addr,omitempty"`
 }
 
 // Load the configuration options from the specified yaml file.
@@ -100,4 +102,9 @@ func (c *Configuration) loadEnvs() {
 		c.LogLevel = loggerLevel
 	}
 
+	metricAddr := os.Getenv("REGISTRYCTL_METRIC_ADDR")
+	if len(metricAddr) != 0 {
+		c.MetricAddr = metricAddr
+	}
+
 }
@@ -31,6 +31,7 @@ func TestConfigLoadingWithEnv(t *testing.T) {
 	os.Setenv("REGISTRYCTL_PROTOCOL", "https")
 	os.Setenv("PORT", "1000")
 	os.Setenv("LOG_LEVEL", "DEBUG")
+	os.Setenv("REGISTRYCTL_METRIC_ADDR", ":9090")
 
 	cfg := &Configuration{}
 	err := cfg.Load("../config_test.yml", true)
@@ -38,6 +39,7 @@ func TestConfigLoadingWithEnv(t *testing.T) {
 	assert.Equal(t, "https", cfg.Protocol)
 	assert.Equal(t, "1000", cfg.Port)
 	assert.Equal(t, "DEBUG", cfg.LogLevel)
+	assert.Equal(t, ":9090", cfg.MetricAddr)
 }
 
 func TestConfigLoadingWithYml(t *testing.T) {
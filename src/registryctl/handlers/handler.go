@@ -19,12 +19,13 @@ import (
 	"os"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/registryctl/auth"
 	gorilla_handlers "github.com/gorilla/handlers"
 )
 
-// NewHandlerChain returns a gorilla router which is wrapped by  authenticate handler
-// and logging handler
+// NewHandlerChain returns a gorilla router which is wrapped by  authenticate handler,
+// metrics handler and logging handler
 func NewHandlerChain() http.Handler {
 	h := newRouter()
 	secrets := map[string]string{
@@ -34,6 +35,7 @@ func NewHandlerChain() http.Handler {
 		"/api/health": true,
 	}
 	h = newAuthHandler(auth.NewSecretHandler(secrets), h, insecureAPIs)
+	h = metrics.Instrument("registryctl", h)
 	h = gorilla_handlers.LoggingHandler(os.Stdout, h)
 	return h
 }
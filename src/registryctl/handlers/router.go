@@ -17,13 +17,16 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/registryctl/api"
 	"github.com/gorilla/mux"
 )
 
 func newRouter() http.Handler {
 	r := mux.NewRouter()
+	r.Use(metrics.MuxMiddleware)
 	r.HandleFunc("/api/registry/gc", api.StartGC).Methods("POST")
+	r.HandleFunc("/api/registry/blob/{reference}", api.DeleteBlob).Methods("DELETE")
 	r.HandleFunc("/api/health", api.Health).Methods("GET")
 	return r
 }
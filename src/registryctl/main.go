@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/registryctl/config"
 	"github.com/goharbor/harbor/src/registryctl/handlers"
 )
@@ -82,6 +83,8 @@ func main() {
 		log.Fatalf("Failed to load configurations with error: %s\n", err)
 	}
 
+	metrics.Serve(config.DefaultConfig.MetricAddr)
+
 	regCtl := &RegistryCtl{
 		ServerConf: *config.DefaultConfig,
 		Handler:    handlers.NewHandlerChain(),
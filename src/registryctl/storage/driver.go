@@ -0,0 +1,184 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"github.com/docker/distribution/registry/storage/driver/factory"
+	"github.com/opencontainers/go-digest"
+	yaml "gopkg.in/yaml.v2"
+
+	// register the storage drivers supported by the online garbage collection
+	_ "github.com/docker/distribution/registry/storage/driver/filesystem"
+	_ "github.com/docker/distribution/registry/storage/driver/inmemory"
+	_ "github.com/docker/distribution/registry/storage/driver/s3-aws"
+)
+
+const (
+	// the root of the registry v2 data in the storage
+	rootPath = "/docker/registry/v2"
+)
+
+// the sections of the storage configuration of registry which are not storage drivers
+var nonDriverSections = map[string]bool{
+	"cache":       true,
+	"delete":      true,
+	"maintenance": true,
+	"redirect":    true,
+}
+
+// Driver deletes the data of blobs from the storage of registry
+type Driver interface {
+	// DeleteBlob removes the links of the blob with the digest from all the repositories and then the data of it,
+	// returns an error of type storagedriver.PathNotFoundError if the blob doesn't exist
+	DeleteBlob(ctx context.Context, dgt digest.Digest) error
+}
+
+type driver struct {
+	storage storagedriver.StorageDriver
+}
+
+// NewDriver returns a driver on top of the registry storage driver
+func NewDriver(storage storagedriver.StorageDriver) Driver {
+	return &driver{
+		storage: storage,
+	}
+}
+
+// NewDriverFromConfig creates the driver with the storage section of the registry configuration file
+func NewDriverFromConfig(configPath string) (Driver, error) {
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := struct {
+		Storage map[string]map[string]interface{} `yaml:"storage"`
+	}{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	for name, params := range cfg.Storage {
+		if nonDriverSections[name] {
+			continue
+		}
+		storage, err := factory.Create(name, normalize(params))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create storage driver %s: %v", name, err)
+		}
+		return NewDriver(storage), nil
+	}
+
+	return nil, fmt.Errorf("no storage driver found in %s", configPath)
+}
+
+// DeleteBlob ...
+func (d *driver) DeleteBlob(ctx context.Context, dgt digest.Digest) error {
+	if err := dgt.Validate(); err != nil {
+		return err
+	}
+
+	// the links are removed first, so no repository refers to the blob if the data fails to be deleted,
+	// otherwise the registry serves the blob of the repository with the stale link as existing
+	repositories, err := d.repositories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, repository := range repositories {
+		for _, link := range []string{LayerLinkPath(repository, dgt), RevisionLinkPath(repository, dgt)} {
+			if err := d.storage.Delete(ctx, link); err != nil {
+				if _, ok := err.(storagedriver.PathNotFoundError); ok {
+					continue
+				}
+				return err
+			}
+		}
+	}
+
+	return d.storage.Delete(ctx, BlobDataPath(dgt))
+}
+
+// repositories returns the names of all the repositories in the storage, the directory containing
+// the "_layers", "_manifests" or "_uploads" directories is a repository
+func (d *driver) repositories(ctx context.Context) ([]string, error) {
+	root := path.Join(rootPath, "repositories")
+	found := map[string]bool{}
+	var repositories []string
+	err := d.storage.Walk(ctx, root, func(fileInfo storagedriver.FileInfo) error {
+		if !fileInfo.IsDir() || !strings.HasPrefix(path.Base(fileInfo.Path()), "_") {
+			return nil
+		}
+		repository := strings.TrimPrefix(path.Dir(fileInfo.Path()), root+"/")
+		if !found[repository] {
+			found[repository] = true
+			repositories = append(repositories, repository)
+		}
+		return storagedriver.ErrSkipDir
+	})
+	if err != nil {
+		if _, ok := err.(storagedriver.PathNotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return repositories, nil
+}
+
+// BlobDataPath returns the directory of the blob data in the storage,
+// e.g. /docker/registry/v2/blobs/sha256/ab/abcd.../
+func BlobDataPath(dgt digest.Digest) string {
+	hex := dgt.Hex()
+	return path.Join(rootPath, "blobs", dgt.Algorithm().String(), hex[:2], hex)
+}
+
+// LayerLinkPath returns the directory of the link to the blob in the repository,
+// e.g. /docker/registry/v2/repositories/library/hello-world/_layers/sha256/abcd.../
+func LayerLinkPath(repository string, dgt digest.Digest) string {
+	return path.Join(rootPath, "repositories", repository, "_layers", dgt.Algorithm().String(), dgt.Hex())
+}
+
+// RevisionLinkPath returns the directory of the link to the manifest blob in the repository,
+// e.g. /docker/registry/v2/repositories/library/hello-world/_manifests/revisions/sha256/abcd.../
+func RevisionLinkPath(repository string, dgt digest.Digest) string {
+	return path.Join(rootPath, "repositories", repository, "_manifests", "revisions", dgt.Algorithm().String(), dgt.Hex())
+}
+
+// normalize converts the keys of the maps unmarshalled by yaml to be string as required by the storage drivers
+func normalize(params map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for k, v := range params {
+		result[k] = normalizeValue(v)
+	}
+	return result
+}
+
+func normalizeValue(v interface{}) interface{} {
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return v
+	}
+	result := map[string]interface{}{}
+	for k, v := range m {
+		result[fmt.Sprintf("%v", k)] = normalizeValue(v)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	storagedriver "github.com/docker/distribution/registry/storage/driver"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobDataPath(t *testing.T) {
+	dgt := digest.Digest("sha256:c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc")
+	assert.Equal(t, "/docker/registry/v2/blobs/sha256/c5/c54a2cc56cbb2f04003c1cd4507e118af7c0d340fe7e2720f70976c4b75237dc",
+		BlobDataPath(dgt))
+}
+
+func TestNewDriverFromConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "registry")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	cfg := fmt.Sprintf(`version: 0.1
+storage:
+  cache:
+    layerinfo: redis
+  filesystem:
+    rootdirectory: %s
+  maintenance:
+    uploadpurging:
+      enabled: false
+  delete:
+    enabled: true
+`, root)
+	cfgPath := filepath.Join(root, "config.yml")
+	require.Nil(t, ioutil.WriteFile(cfgPath, []byte(cfg), 0600))
+
+	d, err := NewDriverFromConfig(cfgPath)
+	require.Nil(t, err)
+
+	dgt := digest.FromString("blob")
+	dataPath := filepath.Join(root, BlobDataPath(dgt), "data")
+	require.Nil(t, os.MkdirAll(filepath.Dir(dataPath), 0700))
+	require.Nil(t, ioutil.WriteFile(dataPath, []byte("blob"), 0600))
+
+	// the links of the blob in the repositories and a link of another blob
+	links := []string{
+		filepath.Join(root, LayerLinkPath("library/hello-world", dgt), "link"),
+		filepath.Join(root, RevisionLinkPath("library/hello-world", dgt), "link"),
+		filepath.Join(root, LayerLinkPath("photon", dgt), "link"),
+	}
+	other := filepath.Join(root, LayerLinkPath("photon", digest.FromString("other")), "link")
+	for _, link := range append(links, other) {
+		require.Nil(t, os.MkdirAll(filepath.Dir(link), 0700))
+		require.Nil(t, ioutil.WriteFile(link, []byte(dgt), 0600))
+	}
+
+	require.Nil(t, d.DeleteBlob(context.Background(), dgt))
+	_, err = os.Stat(dataPath)
+	assert.True(t, os.IsNotExist(err))
+	for _, link := range links {
+		_, err = os.Stat(link)
+		assert.True(t, os.IsNotExist(err))
+	}
+	_, err = os.Stat(other)
+	assert.Nil(t, err)
+
+	err = d.DeleteBlob(context.Background(), dgt)
+	_, ok := err.(storagedriver.PathNotFoundError)
+	assert.True(t, ok)
+}
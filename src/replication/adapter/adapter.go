@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/distribution"
 	"github.com/goharbor/harbor/src/replication/filter"
@@ -63,6 +64,34 @@ type ImageRegistry interface {
 	PushBlob(repository, digest string, size int64, blob io.Reader) error
 }
 
+// BlobMounter defines the capability of mounting the blob from another repository
+// of the same registry rather than uploading the content again
+type BlobMounter interface {
+	// MountBlob mounts the blob from the repository "from" to the repository, returns false if the
+	// registry doesn't mount it, e.g. the blob doesn't exist in the repository "from"
+	MountBlob(repository, digest, from string) (mounted bool, err error)
+}
+
+// ArtifactMetadataRegistry is implemented by the registries which support replicating
+// the metadata(labels, scan reports) of the artifacts
+type ArtifactMetadataRegistry interface {
+	// the metadata specified by "replication" is pulled, others are left empty
+	PullArtifactMetadata(repository, tag string, replication *model.MetadataReplication) (*model.ArtifactMetadata, error)
+	PushArtifactMetadata(repository, tag string, metadata *model.ArtifactMetadata) error
+}
+
+// ManifestListKeeper is implemented by the registries which store the manifest lists as they are,
+// the whole manifest lists are copied to them rather than one of the manifests in the lists
+type ManifestListKeeper interface {
+	KeepManifestLists() bool
+}
+
+// Flusher is implemented by the registries which buffer the content pushed to them,
+// the transfer flushes the content once it completes
+type Flusher interface {
+	Flush() error
+}
+
 // ChartRegistry defines the capabilities that a chart registry should have
 type ChartRegistry interface {
 	FetchCharts(filters []*model.Filter) ([]*model.Resource, error)
@@ -103,6 +132,9 @@ type VTag struct {
 	ResourceType string   `json:"resource_type"`
 	Name         string   `json:"name"`
 	Labels       []string `json:"labels"`
+	// the digest and push time are empty if the registry doesn't provide them
+	Digest   string    `json:"digest"`
+	PushTime time.Time `json:"push_time"`
 }
 
 // GetFilterableType returns the filterable type
@@ -125,6 +157,16 @@ func (v *VTag) GetLabels() []string {
 	return v.Labels
 }
 
+// GetDigest returns the digest
+func (v *VTag) GetDigest() string {
+	return v.Digest
+}
+
+// GetPushTime returns the push time
+func (v *VTag) GetPushTime() time.Time {
+	return v.PushTime
+}
+
 // RegisterFactory registers one adapter factory to the registry
 func RegisterFactory(t model.RegistryType, factory Factory) error {
 	if len(t) == 0 {
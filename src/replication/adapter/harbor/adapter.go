@@ -109,6 +109,26 @@ func (a *adapter) Info() (*model.RegistryInfo, error) {
 				Type:  model.FilterTypeTag,
 				Style: model.FilterStyleTypeText,
 			},
+			{
+				Type:  model.FilterTypeNameExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagRegex,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeDigest,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypePushedAfter,
+				Style: model.FilterStyleTypeText,
+			},
 		},
 		SupportedTriggers: []model.TriggerType{
 			model.TriggerTypeManual,
@@ -143,7 +163,12 @@ func (a *adapter) Info() (*model.RegistryInfo, error) {
 			Style:  model.FilterStyleTypeList,
 			Values: ls,
 		}
-		info.SupportedResourceFilters = append(info.SupportedResourceFilters, labelFilter)
+		labelExcludeFilter := &model.FilterStyle{
+			Type:   model.FilterTypeLabelExclude,
+			Style:  model.FilterStyleTypeList,
+			Values: ls,
+		}
+		info.SupportedResourceFilters = append(info.SupportedResourceFilters, labelFilter, labelExcludeFilter)
 	}
 	return info, nil
 }
@@ -22,6 +22,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
 	adp "github.com/goharbor/harbor/src/replication/adapter"
@@ -29,12 +30,19 @@ import (
 )
 
 type label struct {
-	Name string `json:"name"`
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	Scope       string `json:"scope"`
+	ProjectID   int64  `json:"project_id"`
 }
 
 type chartVersion struct {
-	Version string   `json:"version"`
-	Labels  []*label `json:"labels"`
+	Version string    `json:"version"`
+	Labels  []*label  `json:"labels"`
+	Digest  string    `json:"digest"`
+	Created time.Time `json:"created"`
 }
 
 type chartVersionDetail struct {
@@ -89,6 +97,8 @@ func (a *adapter) FetchCharts(filters []*model.Filter) ([]*model.Resource, error
 					Name:         version.Version,
 					Labels:       labels,
 					ResourceType: string(model.ResourceTypeChart),
+					Digest:       version.Digest,
+					PushTime:     version.Created,
 				})
 			}
 			for _, filter := range filters {
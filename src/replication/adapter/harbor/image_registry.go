@@ -17,6 +17,7 @@ package harbor
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/common/utils/log"
@@ -150,6 +151,12 @@ func (a *adapter) DeleteManifest(repository, reference string) error {
 	return a.client.Delete(url)
 }
 
+// KeepManifestLists keeps the manifest lists and OCI image indexes as Harbor stores them,
+// so the digests of the images are the same as the source ones
+func (a *adapter) KeepManifestLists() bool {
+	return true
+}
+
 func (a *adapter) getTags(repository string) ([]*adp.VTag, error) {
 	url := fmt.Sprintf("%s/api/repositories/%s/tags", a.getURL(), repository)
 	tags := []*struct {
@@ -157,6 +164,8 @@ func (a *adapter) getTags(repository string) ([]*adp.VTag, error) {
 		Labels []*struct {
 			Name string `json:"name"`
 		}
+		Digest   string    `json:"digest"`
+		PushTime time.Time `json:"push_time"`
 	}{}
 	if err := a.client.Get(url, &tags); err != nil {
 		return nil, err
@@ -171,6 +180,8 @@ func (a *adapter) getTags(repository string) ([]*adp.VTag, error) {
 			Name:         tag.Name,
 			Labels:       labels,
 			ResourceType: string(model.ResourceTypeImage),
+			Digest:       tag.Digest,
+			PushTime:     tag.PushTime,
 		})
 	}
 	return vTags, nil
@@ -0,0 +1,166 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package harbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication/model"
+)
+
+// PullArtifactMetadata pulls the labels and the scan reports of the image specified by
+// the repository and tag
+func (a *adapter) PullArtifactMetadata(repository, tag string, replication *model.MetadataReplication) (*model.ArtifactMetadata, error) {
+	metadata := &model.ArtifactMetadata{}
+	if replication == nil {
+		return metadata, nil
+	}
+	if replication.Labels {
+		url := fmt.Sprintf("%s/api/repositories/%s/tags/%s/labels", a.getURL(), repository, tag)
+		labels := []*label{}
+		if err := a.client.Get(url, &labels); err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			metadata.Labels = append(metadata.Labels, &model.Label{
+				Name:        l.Name,
+				Description: l.Description,
+				Color:       l.Color,
+				Scope:       l.Scope,
+			})
+		}
+	}
+	if replication.ScanReports {
+		url := fmt.Sprintf("%s/api/repositories/%s/tags/%s/scan", a.getURL(), repository, tag)
+		reports := map[string]json.RawMessage{}
+		if err := a.client.Get(url, &reports); err != nil {
+			// no scanner is configured for the project or the image hasn't been scanned
+			if e, ok := err.(*common_http.Error); ok &&
+				(e.Code == http.StatusNotFound || e.Code == http.StatusPreconditionFailed) {
+				log.Debugf("no scan report found for %s:%s: %v", repository, tag, err)
+				return metadata, nil
+			}
+			return nil, err
+		}
+		if len(reports) > 0 {
+			metadata.ScanReports = reports
+		}
+	}
+	return metadata, nil
+}
+
+// PushArtifactMetadata attaches the labels to the image specified by the repository and tag,
+// the labels that don't exist are created first, and imports the scan reports of the image
+func (a *adapter) PushArtifactMetadata(repository, tag string, metadata *model.ArtifactMetadata) error {
+	if metadata == nil {
+		return nil
+	}
+	if len(metadata.Labels) > 0 {
+		projectName, _ := utils.ParseRepository(repository)
+		project, err := a.getProject(projectName)
+		if err != nil {
+			return err
+		}
+		if project == nil {
+			return fmt.Errorf("project %s not found", projectName)
+		}
+		for _, l := range metadata.Labels {
+			id, err := a.ensureLabel(l, project.ID)
+			if err != nil {
+				return err
+			}
+			url := fmt.Sprintf("%s/api/repositories/%s/tags/%s/labels", a.getURL(), repository, tag)
+			if err = a.client.Post(url, struct {
+				ID int64 `json:"id"`
+			}{
+				ID: id,
+			}); err != nil {
+				// the label is already attached to the image
+				if e, ok := err.(*common_http.Error); ok && e.Code == http.StatusConflict {
+					continue
+				}
+				return err
+			}
+		}
+	}
+	if len(metadata.ScanReports) > 0 {
+		url := fmt.Sprintf("%s/api/repositories/%s/tags/%s/scan", a.getURL(), repository, tag)
+		if err := a.client.Put(url, metadata.ScanReports); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the ID of the label, creates the label if it doesn't exist
+func (a *adapter) ensureLabel(l *model.Label, projectID int64) (int64, error) {
+	lb, err := a.getLabel(l.Name, l.Scope, projectID)
+	if err != nil {
+		return 0, err
+	}
+	if lb != nil {
+		return lb.ID, nil
+	}
+	lb = &label{
+		Name:        l.Name,
+		Description: l.Description,
+		Color:       l.Color,
+		Scope:       l.Scope,
+	}
+	if l.Scope == "p" {
+		lb.ProjectID = projectID
+	}
+	if err = a.client.Post(fmt.Sprintf("%s/api/labels", a.getURL()), lb); err != nil {
+		// the label may be created by other tasks concurrently
+		if e, ok := err.(*common_http.Error); !ok || e.Code != http.StatusConflict {
+			return 0, err
+		}
+	}
+	lb, err = a.getLabel(l.Name, l.Scope, projectID)
+	if err != nil {
+		return 0, err
+	}
+	if lb == nil {
+		return 0, fmt.Errorf("label %s not found after creating", l.Name)
+	}
+	return lb.ID, nil
+}
+
+func (a *adapter) getLabel(name, scope string, projectID int64) (*label, error) {
+	query := url.Values{}
+	query.Set("scope", scope)
+	query.Set("name", name)
+	if scope == "p" {
+		query.Set("project_id", strconv.FormatInt(projectID, 10))
+	}
+	labels := []*label{}
+	if err := a.client.GetAndIteratePagination(fmt.Sprintf("%s/api/labels?%s", a.getURL(), query.Encode()), &labels); err != nil {
+		return nil, err
+	}
+	// the labels are matched by name fuzzily
+	for _, l := range labels {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,196 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package harbor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullArtifactMetadata(t *testing.T) {
+	server := test.NewServer([]*test.RequestHandlerMapping{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/repositories/library/hello-world/tags/1.0/labels",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				data := `[{
+					"id": 1,
+					"name": "release",
+					"color": "#FFFFFF",
+					"scope": "g"
+				}]`
+				w.Write([]byte(data))
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/repositories/library/hello-world/tags/1.0/scan",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				data := `{
+					"application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0": {"severity": "High"}
+				}`
+				w.Write([]byte(data))
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/repositories/library/hello-world/tags/2.0/scan",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+			},
+		},
+	}...)
+	defer server.Close()
+	registry := &model.Registry{
+		URL: server.URL,
+	}
+	adapter, err := newAdapter(registry)
+	require.Nil(t, err)
+	var _ adp.ArtifactMetadataRegistry = adapter
+
+	// nothing is replicated
+	metadata, err := adapter.PullArtifactMetadata("library/hello-world", "1.0", nil)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(metadata.Labels))
+	assert.Equal(t, 0, len(metadata.ScanReports))
+
+	// labels and scan reports
+	metadata, err = adapter.PullArtifactMetadata("library/hello-world", "1.0",
+		&model.MetadataReplication{
+			Labels:      true,
+			ScanReports: true,
+		})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(metadata.Labels))
+	assert.Equal(t, "release", metadata.Labels[0].Name)
+	assert.Equal(t, "g", metadata.Labels[0].Scope)
+	require.Equal(t, 1, len(metadata.ScanReports))
+	assert.JSONEq(t, `{"severity": "High"}`,
+		string(metadata.ScanReports["application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"]))
+
+	// no scanner configured
+	metadata, err = adapter.PullArtifactMetadata("library/hello-world", "2.0",
+		&model.MetadataReplication{
+			ScanReports: true,
+		})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(metadata.ScanReports))
+}
+
+func TestPushArtifactMetadata(t *testing.T) {
+	labelCreated := false
+	attached := []int64{}
+	var reports map[string]json.RawMessage
+	server := test.NewServer([]*test.RequestHandlerMapping{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/projects",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				data := `[{
+					"project_id": 2,
+					"name": "library"
+				}]`
+				w.Write([]byte(data))
+			},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/labels",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("scope") == "g" {
+					if r.URL.Query().Get("name") == "qa&test" {
+						w.Write([]byte(`[{"id": 4, "name": "qa&test", "scope": "g"}]`))
+						return
+					}
+					w.Write([]byte(`[{"id": 1, "name": "release-candidate", "scope": "g"},{"id": 2, "name": "release", "scope": "g"}]`))
+					return
+				}
+				if !labelCreated || r.URL.Query().Get("project_id") != "2" {
+					w.Write([]byte(`[]`))
+					return
+				}
+				w.Write([]byte(`[{"id": 3, "name": "dev", "scope": "p", "project_id": 2}]`))
+			},
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/labels",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				labelCreated = true
+				w.WriteHeader(http.StatusCreated)
+			},
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/repositories/library/hello-world/tags/1.0/labels",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				lb := &label{}
+				data, _ := ioutil.ReadAll(r.Body)
+				json.Unmarshal(data, lb)
+				attached = append(attached, lb.ID)
+				if lb.ID == 2 {
+					// already attached
+					w.WriteHeader(http.StatusConflict)
+				}
+			},
+		},
+		{
+			Method:  http.MethodPut,
+			Pattern: "/api/repositories/library/hello-world/tags/1.0/scan",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				data, _ := ioutil.ReadAll(r.Body)
+				json.Unmarshal(data, &reports)
+			},
+		},
+	}...)
+	defer server.Close()
+	registry := &model.Registry{
+		URL: server.URL,
+	}
+	adapter, err := newAdapter(registry)
+	require.Nil(t, err)
+
+	err = adapter.PushArtifactMetadata("library/hello-world", "1.0", &model.ArtifactMetadata{
+		Labels: []*model.Label{
+			{
+				Name:  "release",
+				Scope: "g",
+			},
+			{
+				Name:  "dev",
+				Scope: "p",
+			},
+			{
+				Name:  "qa&test",
+				Scope: "g",
+			},
+		},
+		ScanReports: map[string]json.RawMessage{
+			"application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0": json.RawMessage(`{"severity":"High"}`),
+		},
+	})
+	require.Nil(t, err)
+	assert.True(t, labelCreated)
+	assert.Equal(t, []int64{2, 3, 4}, attached)
+	assert.Equal(t, 1, len(reports))
+}
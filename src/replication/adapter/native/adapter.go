@@ -43,6 +43,7 @@ func init() {
 }
 
 var _ adp.Adapter = &Adapter{}
+var _ adp.BlobMounter = &Adapter{}
 
 type factory struct {
 }
@@ -128,6 +129,18 @@ func (a *Adapter) Info() (info *model.RegistryInfo, err error) {
 				Type:  model.FilterTypeTag,
 				Style: model.FilterStyleTypeText,
 			},
+			{
+				Type:  model.FilterTypeNameExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagRegex,
+				Style: model.FilterStyleTypeText,
+			},
 		},
 		SupportedTriggers: []model.TriggerType{
 			model.TriggerTypeManual,
@@ -364,6 +377,15 @@ func (a *Adapter) PushBlob(repository, digest string, size int64, blob io.Reader
 	return client.PushBlob(digest, size, blob)
 }
 
+// MountBlob ...
+func (a *Adapter) MountBlob(repository, digest, from string) (bool, error) {
+	client, err := a.getClient(repository)
+	if err != nil {
+		return false, err
+	}
+	return client.TryMountBlob(digest, from)
+}
+
 func isDigest(str string) bool {
 	return strings.Contains(str, ":")
 }
@@ -60,7 +60,7 @@ func Test_native_Info(t *testing.T) {
 	assert.NotNil(t, info)
 	assert.Equal(t, model.RegistryTypeDockerRegistry, info.Type)
 	assert.Equal(t, 1, len(info.SupportedResourceTypes))
-	assert.Equal(t, 2, len(info.SupportedResourceFilters))
+	assert.Equal(t, 5, len(info.SupportedResourceFilters))
 	assert.Equal(t, 2, len(info.SupportedTriggers))
 	assert.Equal(t, model.ResourceTypeImage, info.SupportedResourceTypes[0])
 }
@@ -0,0 +1,347 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/adapter/native"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/goharbor/harbor/src/replication/util"
+)
+
+const (
+	// the docker repositories of Nexus serve the registry V2 API under the path
+	// "/repository/{name}", e.g. https://nexus.example.com/repository/docker-hosted
+	repositoryPath   = "/repository/"
+	defaultBlobStore = "default"
+)
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeNexus, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeNexus, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeNexus)
+}
+
+type factory struct {
+}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+// adapter for the docker repositories of Sonatype Nexus Repository Manager 3, the images are
+// listed by the search API of Nexus and transferred by the registry V2 API of the repository
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	// the base URL of Nexus, e.g. https://nexus.example.com
+	url string
+	// the name of the docker repository
+	repository string
+	client     *common_http.Client
+}
+
+var _ adp.Adapter = (*adapter)(nil)
+
+func newAdapter(registry *model.Registry) (*adapter, error) {
+	baseURL, repo, err := parseURL(registry.URL)
+	if err != nil {
+		return nil, err
+	}
+	dockerRegistryAdapter, err := native.NewAdapter(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiers := []modifier.Modifier{
+		&auth.UserAgentModifier{
+			UserAgent: adp.UserAgentReplication,
+		},
+	}
+	if registry.Credential != nil {
+		modifiers = append(modifiers, auth.NewBasicAuthCredential(
+			registry.Credential.AccessKey,
+			registry.Credential.AccessSecret))
+	}
+
+	return &adapter{
+		Adapter:    dockerRegistryAdapter,
+		registry:   registry,
+		url:        baseURL,
+		repository: repo,
+		client: common_http.NewClient(
+			&http.Client{
+				Transport: util.GetHTTPTransport(registry.Insecure),
+			},
+			modifiers...,
+		),
+	}, nil
+}
+
+// parseURL splits the URL of the docker repository into the base URL of Nexus and
+// the name of the repository
+func parseURL(u string) (string, string, error) {
+	u = strings.TrimRight(u, "/")
+	i := strings.Index(u, repositoryPath)
+	if i == -1 {
+		return "", "", fmt.Errorf("invalid URL %s of the Nexus docker repository, the URL must be in the format of %s",
+			u, "https://nexus.example.com/repository/{repository-name}")
+	}
+	repo := u[i+len(repositoryPath):]
+	if len(repo) == 0 || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid repository name in the URL %s", u)
+	}
+	return u[:i], repo, nil
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeNexus,
+		SupportedResourceTypes: []model.ResourceType{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeNameExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagRegex,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeDigest,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []model.TriggerType{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// PrepareForPush creates the docker hosted repository if it doesn't exist, the namespaces
+// of the images needn't to be created as they're part of the image names in Nexus
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	for _, resource := range resources {
+		if resource == nil {
+			return errors.New("the resource cannot be null")
+		}
+		if resource.Metadata == nil {
+			return errors.New("the metadata of resource cannot be null")
+		}
+		if resource.Metadata.Repository == nil {
+			return errors.New("the namespace of resource cannot be null")
+		}
+		if len(resource.Metadata.Repository.Name) == 0 {
+			return errors.New("the name of namespace cannot be null")
+		}
+	}
+
+	repositories := []*repository{}
+	if err := a.client.Get(fmt.Sprintf("%s/service/rest/v1/repositories", a.url), &repositories); err != nil {
+		return err
+	}
+	for _, repo := range repositories {
+		if repo.Name != a.repository {
+			continue
+		}
+		if repo.Format != "docker" {
+			return fmt.Errorf("the repository %s isn't a docker repository", a.repository)
+		}
+		log.Debugf("the repository %s already exists in Nexus, skip creating it", a.repository)
+		return nil
+	}
+
+	// this operation needs admin
+	repo := &hostedRepository{
+		Name:   a.repository,
+		Online: true,
+		Storage: &storageConfig{
+			BlobStoreName:               defaultBlobStore,
+			StrictContentTypeValidation: true,
+			WritePolicy:                 "ALLOW",
+		},
+		Docker: &dockerConfig{
+			ForceBasicAuth: true,
+		},
+	}
+	if err := a.client.Post(fmt.Sprintf("%s/service/rest/v1/repositories/docker/hosted", a.url), repo); err != nil {
+		log.Errorf("failed to create the repository %s in Nexus: %v", a.repository, err)
+		return err
+	}
+	log.Debugf("the repository %s created in Nexus", a.repository)
+	return nil
+}
+
+// FetchImages lists the images by the search API of Nexus, the name and tag filters are
+// pushed down to the search API where possible and applied again on the results
+func (a *adapter) FetchImages(filters []*model.Filter) ([]*model.Resource, error) {
+	names, version := searchConditions(filters)
+	tags := map[string][]*adp.VTag{}
+	for _, name := range names {
+		components, err := a.search(name, version)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range components {
+			vTag := &adp.VTag{
+				ResourceType: string(model.ResourceTypeImage),
+				Name:         c.Version,
+			}
+			// the checksum of the manifest asset is the digest of the image
+			for _, as := range c.Assets {
+				if strings.HasSuffix(as.Path, "/manifests/"+c.Version) {
+					if sha256, ok := as.Checksum["sha256"]; ok {
+						vTag.Digest = "sha256:" + sha256
+					}
+					break
+				}
+			}
+			tags[c.Name] = append(tags[c.Name], vTag)
+		}
+	}
+
+	repositories := []*adp.Repository{}
+	for name := range tags {
+		repositories = append(repositories, &adp.Repository{
+			ResourceType: string(model.ResourceTypeImage),
+			Name:         name,
+		})
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+	for _, filter := range filters {
+		if err := filter.DoFilter(&repositories); err != nil {
+			return nil, err
+		}
+	}
+
+	var resources []*model.Resource
+	for _, repo := range repositories {
+		vTags := tags[repo.Name]
+		for _, filter := range filters {
+			if err := filter.DoFilter(&vTags); err != nil {
+				return nil, err
+			}
+		}
+		if len(vTags) == 0 {
+			continue
+		}
+		names := []string{}
+		for _, vTag := range vTags {
+			names = append(names, vTag.Name)
+		}
+		resources = append(resources, &model.Resource{
+			Type:     model.ResourceTypeImage,
+			Registry: a.registry,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: repo.Name,
+				},
+				Vtags: names,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// searchConditions returns the names and version used to search the components by the name
+// and tag filters, an empty string means no condition
+func searchConditions(filters []*model.Filter) ([]string, string) {
+	names := []string{""}
+	version := ""
+	for _, filter := range filters {
+		pattern, ok := filter.Value.(string)
+		if !ok {
+			continue
+		}
+		switch filter.Type {
+		case model.FilterTypeName:
+			if paths, ok := util.IsSpecificPath(pattern); ok {
+				names = paths
+			} else if wildcard, ok := util.WildcardPattern(pattern); ok {
+				names = []string{wildcard}
+			}
+		case model.FilterTypeTag:
+			if tags, ok := util.IsSpecificPathComponent(pattern); ok && len(tags) == 1 {
+				version = tags[0]
+			} else if wildcard, ok := util.WildcardPattern(pattern); ok {
+				version = wildcard
+			}
+		}
+	}
+	return names, version
+}
+
+// search the components of the docker repository, iterates the pages by the continuation token
+func (a *adapter) search(name, version string) ([]*component, error) {
+	query := url.Values{}
+	query.Set("repository", a.repository)
+	query.Set("format", "docker")
+	if len(name) > 0 {
+		query.Set("name", name)
+	}
+	if len(version) > 0 {
+		query.Set("version", version)
+	}
+	components := []*component{}
+	for {
+		result := &searchResult{}
+		if err := a.client.Get(fmt.Sprintf("%s/service/rest/v1/search?%s", a.url, query.Encode()), result); err != nil {
+			return nil, err
+		}
+		components = append(components, result.Items...)
+		if len(result.ContinuationToken) == 0 {
+			break
+		}
+		query.Set("continuationToken", result.ContinuationToken)
+	}
+	return components, nil
+}
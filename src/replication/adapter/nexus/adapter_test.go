@@ -0,0 +1,244 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getMockAdapter(t *testing.T, created *hostedRepository, queries *[]string) (*adapter, *httptest.Server) {
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/service/rest/v1/repositories",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`[
+					{"name": "maven-releases", "format": "maven2", "type": "hosted"},
+					{"name": "docker-hosted", "format": "docker", "type": "hosted"}
+				]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodPost,
+			Pattern: "/service/rest/v1/repositories/docker/hosted",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				data, _ := ioutil.ReadAll(r.Body)
+				json.Unmarshal(data, created)
+				w.WriteHeader(http.StatusCreated)
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/service/rest/v1/search",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				*queries = append(*queries, r.URL.RawQuery)
+				if r.URL.Query().Get("continuationToken") == "" {
+					w.Write([]byte(`{
+						"items": [
+							{
+								"repository": "docker-hosted",
+								"format": "docker",
+								"name": "library/hello-world",
+								"version": "1.0",
+								"assets": [{
+									"path": "v2/library/hello-world/manifests/1.0",
+									"checksum": {"sha1": "abc", "sha256": "0123456789"}
+								}]
+							},
+							{
+								"repository": "docker-hosted",
+								"format": "docker",
+								"name": "library/busybox",
+								"version": "1.0"
+							}
+						],
+						"continuationToken": "next"
+					}`))
+					return
+				}
+				w.Write([]byte(`{
+					"items": [
+						{
+							"repository": "docker-hosted",
+							"format": "docker",
+							"name": "library/hello-world",
+							"version": "2.0"
+						}
+					],
+					"continuationToken": null
+				}`))
+			},
+		},
+	)
+
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeNexus,
+		URL:  server.URL + "/repository/docker-hosted",
+		Credential: &model.Credential{
+			AccessKey:    "admin",
+			AccessSecret: "admin123",
+		},
+	})
+	require.Nil(t, err)
+	return a, server
+}
+
+func TestParseURL(t *testing.T) {
+	_, _, err := parseURL("https://nexus.example.com")
+	assert.NotNil(t, err)
+
+	_, _, err = parseURL("https://nexus.example.com/repository/")
+	assert.NotNil(t, err)
+
+	base, repo, err := parseURL("https://nexus.example.com/repository/docker-hosted/")
+	require.Nil(t, err)
+	assert.Equal(t, "https://nexus.example.com", base)
+	assert.Equal(t, "docker-hosted", repo)
+}
+
+func TestInfo(t *testing.T) {
+	factory, err := adp.GetFactory(model.RegistryTypeNexus)
+	require.Nil(t, err)
+	require.NotNil(t, factory)
+
+	a, s := getMockAdapter(t, &hostedRepository{}, &[]string{})
+	defer s.Close()
+	info, err := a.Info()
+	require.Nil(t, err)
+	assert.EqualValues(t, model.RegistryTypeNexus, info.Type)
+	assert.EqualValues(t, 1, len(info.SupportedResourceTypes))
+	assert.EqualValues(t, model.ResourceTypeImage, info.SupportedResourceTypes[0])
+}
+
+func TestPrepareForPush(t *testing.T) {
+	created := &hostedRepository{}
+	a, s := getMockAdapter(t, created, &[]string{})
+	defer s.Close()
+	resources := []*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+			},
+		},
+	}
+	// the repository exists
+	err := a.PrepareForPush(resources)
+	require.Nil(t, err)
+	assert.Equal(t, "", created.Name)
+
+	// the repository doesn't exist
+	a.repository = "docker-new"
+	err = a.PrepareForPush(resources)
+	require.Nil(t, err)
+	assert.Equal(t, "docker-new", created.Name)
+	assert.True(t, created.Online)
+
+	// not a docker repository
+	a.repository = "maven-releases"
+	err = a.PrepareForPush(resources)
+	assert.NotNil(t, err)
+}
+
+func TestFetchImages(t *testing.T) {
+	queries := []string{}
+	a, s := getMockAdapter(t, &hostedRepository{}, &queries)
+	defer s.Close()
+
+	resources, err := a.FetchImages(nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(resources))
+	assert.Equal(t, "library/busybox", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Vtags)
+	assert.Equal(t, "library/hello-world", resources[1].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0", "2.0"}, resources[1].Metadata.Vtags)
+	require.Equal(t, 2, len(queries))
+	assert.Equal(t, "format=docker&repository=docker-hosted", queries[0])
+	assert.Equal(t, "continuationToken=next&format=docker&repository=docker-hosted", queries[1])
+
+	// the filters are pushed down and applied to the results
+	queries = queries[:0]
+	resources, err = a.FetchImages([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/hello-*",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "1.0",
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, "library/hello-world", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Vtags)
+	require.Equal(t, 2, len(queries))
+	assert.Equal(t, "format=docker&name=library%2Fhello-%2A&repository=docker-hosted&version=1.0", queries[0])
+
+	// digest
+	resources, err = a.FetchImages([]*model.Filter{
+		{
+			Type:  model.FilterTypeDigest,
+			Value: "sha256:0123456789",
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Vtags)
+}
+
+func TestSearchConditions(t *testing.T) {
+	names, version := searchConditions(nil)
+	assert.Equal(t, []string{""}, names)
+	assert.Equal(t, "", version)
+
+	names, version = searchConditions([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/{hello-world,busybox}",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "{1.0,2.0}",
+		},
+	})
+	assert.Equal(t, []string{"library/hello-world", "library/busybox"}, names)
+	assert.Equal(t, "", version)
+
+	names, version = searchConditions([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/**",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "v1.*",
+		},
+	})
+	assert.Equal(t, []string{"library/*"}, names)
+	assert.Equal(t, "v1.*", version)
+}
@@ -0,0 +1,61 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+// the component returned by the search API, for docker repositories, the name
+// of the component is the name of the image and the version is the tag
+type component struct {
+	ID         string   `json:"id"`
+	Repository string   `json:"repository"`
+	Format     string   `json:"format"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Assets     []*asset `json:"assets"`
+}
+
+type asset struct {
+	Path     string            `json:"path"`
+	Checksum map[string]string `json:"checksum"`
+}
+
+type searchResult struct {
+	Items             []*component `json:"items"`
+	ContinuationToken string       `json:"continuationToken"`
+}
+
+type repository struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+}
+
+type hostedRepository struct {
+	Name    string         `json:"name"`
+	Online  bool           `json:"online"`
+	Storage *storageConfig `json:"storage"`
+	Docker  *dockerConfig  `json:"docker"`
+}
+
+type storageConfig struct {
+	BlobStoreName               string `json:"blobStoreName"`
+	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	WritePolicy                 string `json:"writePolicy"`
+}
+
+type dockerConfig struct {
+	V1Enabled      bool `json:"v1Enabled"`
+	ForceBasicAuth bool `json:"forceBasicAuth"`
+}
@@ -0,0 +1,508 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ocilayout provides the adapter which replicates the images to and from an OCI
+// image layout on the local file system, e.g. a volume mounted into both the core and
+// the jobservice containers. It's used to transfer the images into the air-gapped
+// environments: a push-based policy exports the images to the layout and a pull-based
+// policy of another Harbor imports them, the digests are kept as the manifests are copied
+// as-is.
+//
+// The URL of the registry is "file://" followed by the absolute path of the layout, which
+// must be under the root directory of the layouts: "/oci_layouts" or the one specified by
+// the environment variable "OCI_LAYOUT_ROOT". The layout is stored as a tarball if the
+// path ends with ".tar", otherwise as a directory. The tarball is rewritten once when the
+// transfer completes.
+// The images are referenced in the index of the layout by the annotation
+// "org.opencontainers.image.ref.name" in the format of "repository:tag".
+package ocilayout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	scheme = "file://"
+	// the suffix of the path of the layouts stored as tarballs
+	tarballSuffix = ".tar"
+	// the root directory of the layouts, it's mounted into both the core and jobservice containers
+	defaultRoot = "/oci_layouts"
+)
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeOCILayout, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeOCILayout, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeOCILayout)
+}
+
+type factory struct {
+}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+type adapter struct {
+	registry *model.Registry
+	path     string
+	store    store
+}
+
+func newAdapter(registry *model.Registry) (*adapter, error) {
+	path, err := parsePath(registry.URL)
+	if err != nil {
+		return nil, err
+	}
+	var s store
+	if strings.HasSuffix(path, tarballSuffix) {
+		s = &tarStore{path: path}
+	} else {
+		s = &dirStore{root: path}
+	}
+	return &adapter{
+		registry: registry,
+		path:     path,
+		store:    s,
+	}, nil
+}
+
+// ValidateURL checks whether the URL refers to a layout under the root directory of the layouts
+func ValidateURL(url string) error {
+	_, err := parsePath(url)
+	return err
+}
+
+// rootDir returns the root directory of the layouts
+func rootDir() string {
+	if root := os.Getenv("OCI_LAYOUT_ROOT"); len(root) > 0 {
+		return filepath.Clean(root)
+	}
+	return defaultRoot
+}
+
+// parsePath returns the path of the layout specified by the URL "file:///path/to/layout", the
+// path must be under the root directory of the layouts even if the symbolic links are followed
+func parsePath(url string) (string, error) {
+	if !strings.HasPrefix(url, scheme) {
+		return "", fmt.Errorf("invalid URL %s of the OCI layout, it must start with %s", url, scheme)
+	}
+	path := strings.TrimPrefix(url, scheme)
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("invalid URL %s of the OCI layout, the path must be absolute", url)
+	}
+	path = filepath.Clean(path)
+	root := rootDir()
+	if !isUnder(path, root) {
+		return "", fmt.Errorf("invalid URL %s of the OCI layout, the path must be under %s", url, root)
+	}
+	resolvedRoot, err := resolvePath(root)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", err
+	}
+	if !isUnder(resolved, resolvedRoot) {
+		return "", fmt.Errorf("invalid URL %s of the OCI layout, the path must be under %s", url, root)
+	}
+	return path, nil
+}
+
+// isUnder returns whether the path is under the directory, the directory itself isn't included
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath follows the symbolic links of the existing part of the path
+func resolvePath(path string) (string, error) {
+	existing, rest := path, ""
+	for {
+		_, err := os.Lstat(existing)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, rest), nil
+}
+
+// Info returns information of the registry
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeOCILayout,
+		SupportedResourceTypes: []model.ResourceType{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeNameExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagRegex,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeDigest,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypePushedAfter,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []model.TriggerType{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// HealthCheck checks whether the layout exists or can be created under its parent directory
+func (a *adapter) HealthCheck() (model.HealthStatus, error) {
+	exist, err := a.store.exist()
+	if err != nil {
+		log.Errorf("failed to check the OCI layout %s: %v", a.path, err)
+		return model.Unhealthy, nil
+	}
+	if exist {
+		return model.Healthy, nil
+	}
+	info, err := os.Stat(filepath.Dir(a.path))
+	if err != nil || !info.IsDir() {
+		log.Errorf("neither the OCI layout %s nor its parent directory exists: %v", a.path, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// PrepareForPush creates the layout if it doesn't exist
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	return a.store.init()
+}
+
+// FetchImages lists the images referenced by the index of the layout
+func (a *adapter) FetchImages(filters []*model.Filter) ([]*model.Resource, error) {
+	index, err := a.store.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string][]*adp.VTag{}
+	for _, manifest := range index.Manifests {
+		repository, tag, ok := parseRefName(manifest.Annotations[v1.AnnotationRefName])
+		if !ok {
+			log.Debugf("the manifest %s isn't referenced in the format of \"repository:tag\", skip", manifest.Digest)
+			continue
+		}
+		vTag := &adp.VTag{
+			ResourceType: string(model.ResourceTypeImage),
+			Name:         tag,
+			Digest:       manifest.Digest.String(),
+		}
+		if created, err := time.Parse(time.RFC3339, manifest.Annotations[v1.AnnotationCreated]); err == nil {
+			vTag.PushTime = created
+		}
+		tags[repository] = append(tags[repository], vTag)
+	}
+
+	repositories := []*adp.Repository{}
+	for repository := range tags {
+		repositories = append(repositories, &adp.Repository{
+			ResourceType: string(model.ResourceTypeImage),
+			Name:         repository,
+		})
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+	for _, filter := range filters {
+		if err = filter.DoFilter(&repositories); err != nil {
+			return nil, err
+		}
+	}
+
+	resources := []*model.Resource{}
+	for _, repository := range repositories {
+		vTags := tags[repository.Name]
+		for _, filter := range filters {
+			if err = filter.DoFilter(&vTags); err != nil {
+				return nil, err
+			}
+		}
+		if len(vTags) == 0 {
+			continue
+		}
+		names := []string{}
+		for _, vTag := range vTags {
+			names = append(names, vTag.Name)
+		}
+		resources = append(resources, &model.Resource{
+			Type:     model.ResourceTypeImage,
+			Registry: a.registry,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: repository.Name,
+				},
+				Vtags: names,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// ManifestExist checks the existence of the manifest, the "reference" can be a tag or digest
+func (a *adapter) ManifestExist(repository, reference string) (bool, string, error) {
+	if dgst, err := digest.Parse(reference); err == nil {
+		exist, err := a.store.blobExist(dgst)
+		if err != nil {
+			return false, "", err
+		}
+		return exist, reference, nil
+	}
+	descriptor, err := a.getDescriptor(repository, reference)
+	if err != nil {
+		return false, "", err
+	}
+	if descriptor == nil {
+		return false, "", nil
+	}
+	return true, descriptor.Digest.String(), nil
+}
+
+// PullManifest returns the manifest specified by the tag or digest, the manifests referenced by
+// digest are the ones listed in the manifest lists
+func (a *adapter) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
+	var dgst digest.Digest
+	mediaType := ""
+	if d, err := digest.Parse(reference); err == nil {
+		dgst = d
+	} else {
+		descriptor, err := a.getDescriptor(repository, reference)
+		if err != nil {
+			return nil, "", err
+		}
+		if descriptor == nil {
+			return nil, "", fmt.Errorf("the manifest of %s:%s not found", repository, reference)
+		}
+		dgst = descriptor.Digest
+		mediaType = descriptor.MediaType
+	}
+
+	_, blob, err := a.store.readBlob(dgst)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("the manifest %s of %s not found", dgst, repository)
+		}
+		return nil, "", err
+	}
+	defer blob.Close()
+	payload, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(mediaType) == 0 {
+		if mediaType, err = detectMediaType(payload); err != nil {
+			return nil, "", err
+		}
+	}
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return manifest, dgst.String(), nil
+}
+
+// PushManifest stores the manifest as a blob, the manifest pushed by tag is referenced by the index
+func (a *adapter) PushManifest(repository, reference, mediaType string, payload []byte) error {
+	dgst := digest.FromBytes(payload)
+	if err := a.store.writeBlob(dgst, int64(len(payload)), bytes.NewReader(payload)); err != nil {
+		return err
+	}
+	if _, err := digest.Parse(reference); err == nil {
+		return nil
+	}
+	refName := fmt.Sprintf("%s:%s", repository, reference)
+	return a.store.updateIndex(func(index *v1.Index) error {
+		index.Manifests = removeDescriptors(index.Manifests, repository, reference)
+		index.Manifests = append(index.Manifests, v1.Descriptor{
+			MediaType: mediaType,
+			Digest:    dgst,
+			Size:      int64(len(payload)),
+			Annotations: map[string]string{
+				v1.AnnotationRefName: refName,
+				v1.AnnotationCreated: time.Now().UTC().Format(time.RFC3339),
+			},
+		})
+		return nil
+	})
+}
+
+// DeleteManifest removes the references of the manifest from the index, the blobs are kept
+// as they may be referenced by other images
+func (a *adapter) DeleteManifest(repository, reference string) error {
+	return a.store.updateIndex(func(index *v1.Index) error {
+		index.Manifests = removeDescriptors(index.Manifests, repository, reference)
+		return nil
+	})
+}
+
+// BlobExist checks the existence of the blob, the blobs are shared by all the repositories
+func (a *adapter) BlobExist(repository, dgst string) (bool, error) {
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return false, err
+	}
+	return a.store.blobExist(d)
+}
+
+// PullBlob ...
+func (a *adapter) PullBlob(repository, dgst string) (int64, io.ReadCloser, error) {
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return 0, nil, err
+	}
+	return a.store.readBlob(d)
+}
+
+// PushBlob ...
+func (a *adapter) PushBlob(repository, dgst string, size int64, blob io.Reader) error {
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return err
+	}
+	return a.store.writeBlob(d, size, blob)
+}
+
+// KeepManifestLists keeps the manifest lists, so all the platforms of the images are carried by the layout
+func (a *adapter) KeepManifestLists() bool {
+	return true
+}
+
+// Flush writes the content buffered to the layout
+func (a *adapter) Flush() error {
+	return a.store.flush()
+}
+
+// returns the descriptor referenced by "repository:tag" in the index, nil if not found
+func (a *adapter) getDescriptor(repository, tag string) (*v1.Descriptor, error) {
+	index, err := a.store.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	refName := fmt.Sprintf("%s:%s", repository, tag)
+	for i := range index.Manifests {
+		if index.Manifests[i].Annotations[v1.AnnotationRefName] == refName {
+			return &index.Manifests[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// removes the descriptors referenced by "repository:reference", the "reference" can be
+// a tag or the digest of the manifests of the repository
+func removeDescriptors(descriptors []v1.Descriptor, repository, reference string) []v1.Descriptor {
+	result := []v1.Descriptor{}
+	for _, descriptor := range descriptors {
+		repo, tag, ok := parseRefName(descriptor.Annotations[v1.AnnotationRefName])
+		if ok && repo == repository &&
+			(tag == reference || descriptor.Digest.String() == reference) {
+			continue
+		}
+		result = append(result, descriptor)
+	}
+	return result
+}
+
+// parseRefName splits the reference name "repository:tag" in the index
+func parseRefName(refName string) (string, string, bool) {
+	i := strings.LastIndex(refName, ":")
+	if i <= 0 || i == len(refName)-1 || strings.Contains(refName[i+1:], "/") {
+		return "", "", false
+	}
+	return refName[:i], refName[i+1:], true
+}
+
+// detectMediaType detects the media type of the manifest referenced by digest whose
+// media type isn't recorded in the index
+func detectMediaType(payload []byte) (string, error) {
+	m := &struct {
+		SchemaVersion int    `json:"schemaVersion"`
+		MediaType     string `json:"mediaType"`
+	}{}
+	if err := json.Unmarshal(payload, m); err != nil {
+		return "", err
+	}
+	if len(m.MediaType) > 0 {
+		return m.MediaType, nil
+	}
+	if m.SchemaVersion == 1 {
+		return schema1.MediaTypeSignedManifest, nil
+	}
+	return "", errors.New("unknown media type of the manifest")
+}
@@ -0,0 +1,300 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// creates the root directory of the layouts for testing
+func setRoot(t *testing.T) (string, func()) {
+	root, err := ioutil.TempDir("", "oci-layout")
+	require.Nil(t, err)
+	require.Nil(t, os.Setenv("OCI_LAYOUT_ROOT", root))
+	return root, func() {
+		os.Unsetenv("OCI_LAYOUT_ROOT")
+		os.RemoveAll(root)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	root, clean := setRoot(t)
+	defer clean()
+
+	_, err := parsePath("http://127.0.0.1")
+	assert.NotNil(t, err)
+
+	_, err = parsePath("file://relative/path")
+	assert.NotNil(t, err)
+
+	// out of the root directory
+	_, err = parsePath("file:///data/bundle")
+	assert.NotNil(t, err)
+	_, err = parsePath("file://" + root)
+	assert.NotNil(t, err)
+	_, err = parsePath("file://" + root + "/../bundle")
+	assert.NotNil(t, err)
+
+	// the symbolic link to the outside of the root directory
+	outside, err := ioutil.TempDir("", "oci-layout")
+	require.Nil(t, err)
+	defer os.RemoveAll(outside)
+	require.Nil(t, os.Symlink(outside, filepath.Join(root, "link")))
+	_, err = parsePath("file://" + root + "/link/bundle")
+	assert.NotNil(t, err)
+
+	path, err := parsePath("file://" + root + "/bundle/")
+	require.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "bundle"), path)
+	assert.Nil(t, ValidateURL("file://"+root+"/sub/bundle.tar"))
+}
+
+func TestParseRefName(t *testing.T) {
+	_, _, ok := parseRefName("latest")
+	assert.False(t, ok)
+
+	_, _, ok = parseRefName("127.0.0.1:5000/library/hello-world")
+	assert.False(t, ok)
+
+	repository, tag, ok := parseRefName("library/hello-world:latest")
+	require.True(t, ok)
+	assert.Equal(t, "library/hello-world", repository)
+	assert.Equal(t, "latest", tag)
+}
+
+// push one image with one layer, returns the digest of the manifest
+func pushImage(t *testing.T, a *adapter, repository, tag string, layer []byte) string {
+	config := []byte(`{}`)
+	configDigest := digest.FromBytes(config)
+	layerDigest := digest.FromBytes(layer)
+	require.Nil(t, a.PushBlob(repository, configDigest.String(), int64(len(config)), bytes.NewReader(config)))
+	require.Nil(t, a.PushBlob(repository, layerDigest.String(), int64(len(layer)), bytes.NewReader(layer)))
+
+	manifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Digest:    configDigest,
+			Size:      int64(len(config)),
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: schema2.MediaTypeLayer,
+				Digest:    layerDigest,
+				Size:      int64(len(layer)),
+			},
+		},
+	})
+	require.Nil(t, err)
+	mediaType, payload, err := manifest.Payload()
+	require.Nil(t, err)
+	require.Nil(t, a.PushManifest(repository, tag, mediaType, payload))
+	return digest.FromBytes(payload).String()
+}
+
+func testAdapter(t *testing.T, url string) {
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeOCILayout,
+		URL:  url,
+	})
+	require.Nil(t, err)
+	var _ adp.ImageRegistry = a
+
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.EqualValues(t, model.Healthy, status)
+	require.Nil(t, a.PrepareForPush(nil))
+
+	dgst := pushImage(t, a, "library/hello-world", "1.0", []byte("layer1"))
+	pushImage(t, a, "library/hello-world", "2.0", []byte("layer2"))
+	pushImage(t, a, "library/busybox", "latest", []byte("layer1"))
+	// override the existing tag
+	dgst2 := pushImage(t, a, "library/busybox", "latest", []byte("layer3"))
+
+	// fetch images
+	resources, err := a.FetchImages(nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(resources))
+	assert.Equal(t, "library/busybox", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"latest"}, resources[0].Metadata.Vtags)
+	assert.Equal(t, "library/hello-world", resources[1].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0", "2.0"}, resources[1].Metadata.Vtags)
+
+	resources, err = a.FetchImages([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/hello-*",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "1.*",
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Vtags)
+
+	// manifests
+	exist, d, err := a.ManifestExist("library/hello-world", "1.0")
+	require.Nil(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, dgst, d)
+
+	exist, _, err = a.ManifestExist("library/hello-world", "3.0")
+	require.Nil(t, err)
+	assert.False(t, exist)
+
+	exist, d, err = a.ManifestExist("library/busybox", dgst2)
+	require.Nil(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, dgst2, d)
+
+	manifest, d, err := a.PullManifest("library/busybox", "latest", nil)
+	require.Nil(t, err)
+	assert.Equal(t, dgst2, d)
+	mediaType, _, err := manifest.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, schema2.MediaTypeManifest, mediaType)
+	require.Equal(t, 2, len(manifest.References()))
+
+	// pull by digest
+	manifest, d, err = a.PullManifest("library/hello-world", dgst, nil)
+	require.Nil(t, err)
+	assert.Equal(t, dgst, d)
+	mediaType, _, err = manifest.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, schema2.MediaTypeManifest, mediaType)
+
+	// blobs
+	layer := manifest.References()[1]
+	exist, err = a.BlobExist("library/hello-world", layer.Digest.String())
+	require.Nil(t, err)
+	assert.True(t, exist)
+	size, blob, err := a.PullBlob("library/hello-world", layer.Digest.String())
+	require.Nil(t, err)
+	data, err := ioutil.ReadAll(blob)
+	blob.Close()
+	require.Nil(t, err)
+	assert.Equal(t, int64(6), size)
+	assert.Equal(t, "layer1", string(data))
+
+	// the content doesn't match the digest
+	err = a.PushBlob("library/hello-world", digest.FromString("layer4").String(), 6, bytes.NewReader([]byte("layer5")))
+	assert.NotNil(t, err)
+	exist, err = a.BlobExist("library/hello-world", digest.FromString("layer4").String())
+	require.Nil(t, err)
+	assert.False(t, exist)
+
+	// delete
+	require.Nil(t, a.DeleteManifest("library/hello-world", "1.0"))
+	require.Nil(t, a.DeleteManifest("library/busybox", dgst2))
+	resources, err = a.FetchImages(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, []string{"2.0"}, resources[0].Metadata.Vtags)
+
+	// the content flushed can be read by others
+	require.Nil(t, a.Flush())
+	b, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeOCILayout,
+		URL:  url,
+	})
+	require.Nil(t, err)
+	resources, err = b.FetchImages(nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, "library/hello-world", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"2.0"}, resources[0].Metadata.Vtags)
+	manifest, _, err = b.PullManifest("library/hello-world", "2.0", nil)
+	require.Nil(t, err)
+	_, blob, err = b.PullBlob("library/hello-world", manifest.References()[1].Digest.String())
+	require.Nil(t, err)
+	data, err = ioutil.ReadAll(blob)
+	blob.Close()
+	require.Nil(t, err)
+	assert.Equal(t, "layer2", string(data))
+}
+
+func TestDirectoryLayout(t *testing.T) {
+	dir, clean := setRoot(t)
+	defer clean()
+
+	testAdapter(t, "file://"+filepath.Join(dir, "bundle"))
+
+	_, err := os.Stat(filepath.Join(dir, "bundle", "oci-layout"))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "bundle", "index.json"))
+	assert.Nil(t, err)
+}
+
+func TestTarballLayout(t *testing.T) {
+	dir, clean := setRoot(t)
+	defer clean()
+
+	testAdapter(t, "file://"+filepath.Join(dir, "bundle.tar"))
+
+	// the tarball can be read by the standard tar readers and each entry is written once
+	f, err := os.Open(filepath.Join(dir, "bundle.tar"))
+	require.Nil(t, err)
+	defer f.Close()
+	names := map[string]int{}
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		names[header.Name]++
+	}
+	assert.Equal(t, 1, names["oci-layout"])
+	assert.Equal(t, 1, names["index.json"])
+	for name, count := range names {
+		assert.Equal(t, 1, count, name)
+	}
+
+	// the staging directory is removed
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+}
+
+func TestHealthCheck(t *testing.T) {
+	dir, clean := setRoot(t)
+	defer clean()
+
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeOCILayout,
+		URL:  "file://" + dir + "/not/existing/path/bundle",
+	})
+	require.Nil(t, err)
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.EqualValues(t, model.Unhealthy, status)
+}
@@ -0,0 +1,254 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	indexFile  = "index.json"
+	blobsDir   = "blobs"
+	layoutMode = 0644
+)
+
+// store reads and writes the content of one OCI image layout
+type store interface {
+	// init creates the layout if it doesn't exist
+	init() error
+	// exist returns whether the layout exists
+	exist() (bool, error)
+	// readIndex returns the index of the layout, an empty index is returned
+	// if the layout doesn't exist
+	readIndex() (*v1.Index, error)
+	// updateIndex updates the index of the layout by the function "update", the
+	// reading and writing of the index are serialized
+	updateIndex(update func(index *v1.Index) error) error
+	blobExist(dgst digest.Digest) (bool, error)
+	readBlob(dgst digest.Digest) (int64, io.ReadCloser, error)
+	// writeBlob writes the blob and verifies its digest, the existing blob is skipped
+	writeBlob(dgst digest.Digest, size int64, blob io.Reader) error
+	// flush writes the content buffered to the layout
+	flush() error
+}
+
+// the locks serialize the writing of the same layout by the jobs running in the process
+var locks = struct {
+	sync.Mutex
+	m map[string]*sync.Mutex
+}{
+	m: map[string]*sync.Mutex{},
+}
+
+func lock(path string) func() {
+	locks.Lock()
+	l, exist := locks.m[path]
+	if !exist {
+		l = &sync.Mutex{}
+		locks.m[path] = l
+	}
+	locks.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+func newIndex() *v1.Index {
+	return &v1.Index{
+		Versioned: specs.Versioned{
+			SchemaVersion: 2,
+		},
+	}
+}
+
+func layoutContent() ([]byte, error) {
+	return json.Marshal(&v1.ImageLayout{
+		Version: v1.ImageLayoutVersion,
+	})
+}
+
+// the path of the blob relative to the root of the layout
+func blobPath(dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return filepath.Join(blobsDir, dgst.Algorithm().String(), dgst.Hex()), nil
+}
+
+// dirStore stores the layout in a directory
+type dirStore struct {
+	root string
+}
+
+func (d *dirStore) init() error {
+	if err := os.MkdirAll(filepath.Join(d.root, blobsDir), 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(d.root, v1.ImageLayoutFile)
+	_, err := os.Stat(path)
+	if err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	content, err := layoutContent()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, layoutMode)
+}
+
+func (d *dirStore) exist() (bool, error) {
+	_, err := os.Stat(filepath.Join(d.root, v1.ImageLayoutFile))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (d *dirStore) readIndex() (*v1.Index, error) {
+	data, err := ioutil.ReadFile(filepath.Join(d.root, indexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newIndex(), nil
+		}
+		return nil, err
+	}
+	index := &v1.Index{}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of the layout %s: %v", d.root, err)
+	}
+	return index, nil
+}
+
+func (d *dirStore) updateIndex(update func(index *v1.Index) error) error {
+	defer lock(d.root)()
+	index, err := d.readIndex()
+	if err != nil {
+		return err
+	}
+	if err = update(index); err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return d.writeFile(indexFile, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+func (d *dirStore) blobExist(dgst digest.Digest) (bool, error) {
+	path, err := blobPath(dgst)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(filepath.Join(d.root, path))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (d *dirStore) readBlob(dgst digest.Digest) (int64, io.ReadCloser, error) {
+	path, err := blobPath(dgst)
+	if err != nil {
+		return 0, nil, err
+	}
+	f, err := os.Open(filepath.Join(d.root, path))
+	if err != nil {
+		return 0, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, nil, err
+	}
+	return info.Size(), f, nil
+}
+
+func (d *dirStore) writeBlob(dgst digest.Digest, size int64, blob io.Reader) error {
+	exist, err := d.blobExist(dgst)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	path, err := blobPath(dgst)
+	if err != nil {
+		return err
+	}
+	return d.writeFile(path, func(f *os.File) error {
+		verifier := dgst.Verifier()
+		n, err := io.Copy(f, io.TeeReader(blob, verifier))
+		if err != nil {
+			return err
+		}
+		if n != size {
+			return fmt.Errorf("the size of the blob %s is %d, expected %d", dgst, n, size)
+		}
+		if !verifier.Verified() {
+			return fmt.Errorf("the content of the blob doesn't match the digest %s", dgst)
+		}
+		return nil
+	})
+}
+
+// the content is written to the directory directly
+func (d *dirStore) flush() error {
+	return nil
+}
+
+// writeFile writes the file to a temporary file and renames it when the writing
+// completes, so the readers never see a partial file
+func (d *dirStore) writeFile(path string, write func(f *os.File) error) error {
+	path = filepath.Join(d.root, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Chmod(layoutMode); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
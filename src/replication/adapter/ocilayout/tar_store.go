@@ -0,0 +1,420 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type tarEntry struct {
+	offset int64
+	size   int64
+}
+
+// tarStore stores the layout in a tarball. The tarball is scanned once and the blobs pushed
+// are staged in a directory next to it, "flush" rewrites the tarball with the staged blobs
+// and the updated index once and replaces the original one
+type tarStore struct {
+	path string
+
+	mu     sync.Mutex
+	loaded bool
+	// the tarball is kept open, so the entries can be read even if the tarball is replaced by others
+	file *os.File
+	// the entries of the tarball, the last one wins if there are entries of the same name
+	entries map[string]*tarEntry
+	index   *v1.Index
+	// the updates of the index since the last flush, they're applied to the index read
+	// when flushing as the tarball may be rewritten by others meanwhile
+	updates []func(index *v1.Index) error
+	// the directory where the blobs pushed are staged, nil if no blob is pushed
+	staging *dirStore
+}
+
+// scan opens the tarball and reads the headers of the entries, the file returned is nil
+// if the tarball doesn't exist. The caller must close the file
+func (t *tarStore) scan() (*os.File, map[string]*tarEntry, error) {
+	entries := map[string]*tarEntry{}
+	f, err := os.Open(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, entries, nil
+		}
+		return nil, nil, err
+	}
+
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("failed to read the tarball %s: %v", t.path, err)
+		}
+		// the reader doesn't buffer, the file offset is the start of the entry content
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		entries[filepath.Clean(header.Name)] = &tarEntry{
+			offset: offset,
+			size:   header.Size,
+		}
+	}
+	return f, entries, nil
+}
+
+// the reader of the entry is closed along with the file
+func open(f *os.File, entry *tarEntry) io.ReadCloser {
+	return ioutil.NopCloser(io.NewSectionReader(f, entry.offset, entry.size))
+}
+
+// readIndexOf reads the index from the entries of the tarball
+func (t *tarStore) readIndexOf(f *os.File, entries map[string]*tarEntry) (*v1.Index, error) {
+	entry, exist := entries[indexFile]
+	if !exist {
+		return newIndex(), nil
+	}
+	data, err := ioutil.ReadAll(open(f, entry))
+	if err != nil {
+		return nil, err
+	}
+	index := &v1.Index{}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of the layout %s: %v", t.path, err)
+	}
+	return index, nil
+}
+
+// load scans the tarball if it isn't scanned. The caller must hold the mutex
+func (t *tarStore) load() error {
+	if t.loaded {
+		return nil
+	}
+	f, entries, err := t.scan()
+	if err != nil {
+		return err
+	}
+	index, err := t.readIndexOf(f, entries)
+	if err != nil {
+		if f != nil {
+			f.Close()
+		}
+		return err
+	}
+	t.file = f
+	t.entries = entries
+	t.index = index
+	t.loaded = true
+	return nil
+}
+
+// pack writes a new tarball with the layout file, the blobs of the entries and the staged
+// ones and the index, then replaces the original tarball. The caller must hold the lock
+// of the tarball
+func (t *tarStore) pack(src *os.File, entries map[string]*tarEntry, index *v1.Index) error {
+	f, err := ioutil.TempFile(filepath.Dir(t.path), "."+filepath.Base(t.path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = t.writeTarball(f, src, entries, index); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Chmod(layoutMode); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), t.path)
+}
+
+func (t *tarStore) writeTarball(f, src *os.File, entries map[string]*tarEntry, index *v1.Index) error {
+	writer := tar.NewWriter(f)
+	layout, err := layoutContent()
+	if err != nil {
+		return err
+	}
+	if err = writeEntry(writer, v1.ImageLayoutFile, int64(len(layout)), bytes.NewReader(layout)); err != nil {
+		return err
+	}
+
+	// the blobs of the original tarball
+	for name, entry := range entries {
+		if !strings.HasPrefix(name, blobsDir+string(filepath.Separator)) {
+			continue
+		}
+		if err = writeEntry(writer, name, entry.size, open(src, entry)); err != nil {
+			return err
+		}
+	}
+
+	// the staged blobs
+	if t.staging != nil {
+		root := t.staging.root
+		err = filepath.Walk(filepath.Join(root, blobsDir), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// skip the temporary files of the blobs which failed to be written
+			if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".tmp-") {
+				return nil
+			}
+			name, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			if _, exist := entries[name]; exist {
+				return nil
+			}
+			blob, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer blob.Close()
+			return writeEntry(writer, name, info.Size(), blob)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err = writeEntry(writer, indexFile, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func writeEntry(writer *tar.Writer, name string, size int64, content io.Reader) error {
+	if err := writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filepath.ToSlash(name),
+		Size:     size,
+		Mode:     layoutMode,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := io.Copy(writer, content)
+	return err
+}
+
+func (t *tarStore) init() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer lock(t.path)()
+	f, entries, err := t.scan()
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+	if _, exist := entries[v1.ImageLayoutFile]; exist {
+		return nil
+	}
+	if err = os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	index, err := t.readIndexOf(f, entries)
+	if err != nil {
+		return err
+	}
+	if err = t.pack(f, entries, index); err != nil {
+		return err
+	}
+	t.unload()
+	return nil
+}
+
+func (t *tarStore) exist() (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.load(); err != nil {
+		return false, err
+	}
+	_, exist := t.entries[v1.ImageLayoutFile]
+	return exist, nil
+}
+
+func (t *tarStore) readIndex() (*v1.Index, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	index := *t.index
+	index.Manifests = append([]v1.Descriptor{}, t.index.Manifests...)
+	return &index, nil
+}
+
+func (t *tarStore) updateIndex(update func(index *v1.Index) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.load(); err != nil {
+		return err
+	}
+	if err := update(t.index); err != nil {
+		return err
+	}
+	t.updates = append(t.updates, update)
+	return nil
+}
+
+func (t *tarStore) blobExist(dgst digest.Digest) (bool, error) {
+	path, err := blobPath(dgst)
+	if err != nil {
+		return false, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err = t.load(); err != nil {
+		return false, err
+	}
+	if _, exist := t.entries[path]; exist {
+		return true, nil
+	}
+	if t.staging == nil {
+		return false, nil
+	}
+	return t.staging.blobExist(dgst)
+}
+
+func (t *tarStore) readBlob(dgst digest.Digest) (int64, io.ReadCloser, error) {
+	path, err := blobPath(dgst)
+	if err != nil {
+		return 0, nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err = t.load(); err != nil {
+		return 0, nil, err
+	}
+	if entry, exist := t.entries[path]; exist {
+		return entry.size, open(t.file, entry), nil
+	}
+	if t.staging == nil {
+		return 0, nil, os.ErrNotExist
+	}
+	return t.staging.readBlob(dgst)
+}
+
+func (t *tarStore) writeBlob(dgst digest.Digest, size int64, blob io.Reader) error {
+	exist, err := t.blobExist(dgst)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	staging, err := t.stagingDir()
+	if err != nil {
+		return err
+	}
+	// the blobs are written to the staging directory concurrently
+	return staging.writeBlob(dgst, size, blob)
+}
+
+// stagingDir returns the directory where the blobs are staged, it's created next to the
+// tarball, so the blobs are on the same volume
+func (t *tarStore) stagingDir() (*dirStore, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.staging != nil {
+		return t.staging, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir(filepath.Dir(t.path), "."+filepath.Base(t.path)+".staging-")
+	if err != nil {
+		return nil, err
+	}
+	t.staging = &dirStore{root: dir}
+	return t.staging, nil
+}
+
+func (t *tarStore) flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.updates) == 0 && t.staging == nil {
+		return nil
+	}
+	defer lock(t.path)()
+	// read the tarball again as it may be rewritten by others since it's loaded
+	f, entries, err := t.scan()
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+	index, err := t.readIndexOf(f, entries)
+	if err != nil {
+		return err
+	}
+	for _, update := range t.updates {
+		if err = update(index); err != nil {
+			return err
+		}
+	}
+	if err = t.pack(f, entries, index); err != nil {
+		return err
+	}
+	if t.staging != nil {
+		if err = os.RemoveAll(t.staging.root); err != nil {
+			return err
+		}
+	}
+	t.unload()
+	t.updates = nil
+	t.staging = nil
+	return nil
+}
+
+// unload closes the tarball, it's scanned again when it's read next time. The caller must
+// hold the mutex
+func (t *tarStore) unload() {
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file = nil
+	t.entries = nil
+	t.index = nil
+	t.loaded = false
+}
@@ -0,0 +1,330 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry/auth"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/adapter/native"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/goharbor/harbor/src/replication/util"
+)
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeTencentTcr, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeTencentTcr, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeTencentTcr)
+}
+
+type factory struct {
+}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+// adapter for the enterprise instances of Tencent Container Registry, the images are listed
+// by the TCR API and transferred by the registry V2 API with the temporary token of the instance
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	client   *client
+}
+
+var _ adp.Adapter = (*adapter)(nil)
+
+func newAdapter(registry *model.Registry) (*adapter, error) {
+	if registry.Credential == nil {
+		return nil, errors.New("the SecretId and SecretKey of Tencent Cloud are required")
+	}
+	name, err := getInstanceName(registry.URL)
+	if err != nil {
+		return nil, err
+	}
+	client := newClient(apiEndpoint, name, registry.Credential.AccessKey,
+		registry.Credential.AccessSecret, util.GetHTTPTransport(registry.Insecure))
+	authorizer := auth.NewStandardTokenAuthorizer(&http.Client{
+		Transport: util.GetHTTPTransport(registry.Insecure),
+	}, newCredential(client))
+	nativeRegistry, err := native.NewAdapterWithCustomizedAuthorizer(registry, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &adapter{
+		Adapter:  nativeRegistry,
+		registry: registry,
+		client:   client,
+	}, nil
+}
+
+// getInstanceName returns the name of the TCR instance which is the first label of
+// the registry domain, e.g. "harbor" for https://harbor.tencentcloudcr.com
+func getInstanceName(u string) (string, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	host := parsed.Hostname()
+	if len(host) == 0 {
+		return "", fmt.Errorf("invalid URL %s of the TCR instance", u)
+	}
+	return strings.SplitN(host, ".", 2)[0], nil
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeTencentTcr,
+		SupportedResourceTypes: []model.ResourceType{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeNameExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagExclude,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTagRegex,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeDigest,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []model.TriggerType{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// PrepareForPush creates the namespaces that don't exist in the TCR instance
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	namespaces := map[string]struct{}{}
+	for _, resource := range resources {
+		if resource == nil {
+			return errors.New("the resource cannot be null")
+		}
+		if resource.Metadata == nil {
+			return errors.New("the metadata of resource cannot be null")
+		}
+		if resource.Metadata.Repository == nil {
+			return errors.New("the namespace of resource cannot be null")
+		}
+		if len(resource.Metadata.Repository.Name) == 0 {
+			return errors.New("the name of namespace cannot be null")
+		}
+		paths := strings.Split(resource.Metadata.Repository.Name, "/")
+		if len(paths) < 2 {
+			return fmt.Errorf("the repository %s must contain the namespace, e.g. library/%s",
+				resource.Metadata.Repository.Name, resource.Metadata.Repository.Name)
+		}
+		namespaces[paths[0]] = struct{}{}
+	}
+
+	existing, err := a.client.listNamespaces()
+	if err != nil {
+		return err
+	}
+	for _, ns := range existing {
+		delete(namespaces, ns.Name)
+	}
+	for ns := range namespaces {
+		if err := a.client.createNamespace(ns); err != nil {
+			return fmt.Errorf("failed to create the namespace %s in TCR: %v", ns, err)
+		}
+		log.Debugf("namespace %s created in TCR", ns)
+	}
+	return nil
+}
+
+// FetchImages lists the images by the TCR API, the specific repository names and tag in
+// the filters are pushed down to the API and all the filters are applied again on the results
+func (a *adapter) FetchImages(filters []*model.Filter) ([]*model.Resource, error) {
+	repositories, err := a.listRepositories(filters)
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range filters {
+		if err = filter.DoFilter(&repositories); err != nil {
+			return nil, err
+		}
+	}
+
+	version := ""
+	for _, filter := range filters {
+		if filter.Type != model.FilterTypeTag {
+			continue
+		}
+		if pattern, ok := filter.Value.(string); ok {
+			if tags, ok := util.IsSpecificPathComponent(pattern); ok && len(tags) == 1 {
+				version = tags[0]
+			}
+		}
+	}
+
+	rawResources := make([]*model.Resource, len(repositories))
+	runner := utils.NewLimitedConcurrentRunner(adp.MaxConcurrency)
+	defer runner.Cancel()
+	for i, r := range repositories {
+		index := i
+		repo := r
+		runner.AddTask(func() error {
+			paths := strings.SplitN(repo.Name, "/", 2)
+			images, err := a.client.listImages(paths[0], paths[1], version)
+			if err != nil {
+				return fmt.Errorf("failed to list the images of repository %s: %v", repo.Name, err)
+			}
+			vTags := []*adp.VTag{}
+			for _, image := range images {
+				vTags = append(vTags, &adp.VTag{
+					ResourceType: string(model.ResourceTypeImage),
+					Name:         image.ImageVersion,
+					Digest:       image.Digest,
+				})
+			}
+			for _, filter := range filters {
+				if err = filter.DoFilter(&vTags); err != nil {
+					return err
+				}
+			}
+			if len(vTags) == 0 {
+				return nil
+			}
+			tags := []string{}
+			for _, vTag := range vTags {
+				tags = append(tags, vTag.Name)
+			}
+			rawResources[index] = &model.Resource{
+				Type:     model.ResourceTypeImage,
+				Registry: a.registry,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: repo.Name,
+					},
+					Vtags: tags,
+				},
+			}
+			return nil
+		})
+	}
+	runner.Wait()
+	if runner.IsCancelled() {
+		return nil, errors.New("failed to list the images of the TCR instance")
+	}
+
+	var resources []*model.Resource
+	for _, r := range rawResources {
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// listRepositories lists the repositories that the name filter may match, the repositories
+// are queried one by one if the filter specifies the names, otherwise all are listed
+func (a *adapter) listRepositories(filters []*model.Filter) ([]*adp.Repository, error) {
+	var names []string
+	for _, filter := range filters {
+		if filter.Type != model.FilterTypeName {
+			continue
+		}
+		if pattern, ok := filter.Value.(string); ok {
+			if paths, ok := util.IsSpecificPath(pattern); ok {
+				names = paths
+			}
+		}
+	}
+
+	repositories := []*adp.Repository{}
+	addRepositories := func(ns string, repos []*repository, name string) {
+		for _, repo := range repos {
+			fullName := repo.Name
+			if !strings.HasPrefix(fullName, ns+"/") {
+				fullName = ns + "/" + fullName
+			}
+			// the repository name is matched fuzzily by the API
+			if len(name) > 0 && fullName != name {
+				continue
+			}
+			repositories = append(repositories, &adp.Repository{
+				ResourceType: string(model.ResourceTypeImage),
+				Name:         fullName,
+			})
+		}
+	}
+
+	if names != nil {
+		for _, name := range names {
+			paths := strings.SplitN(name, "/", 2)
+			// the repositories in TCR always have namespace
+			if len(paths) < 2 {
+				continue
+			}
+			repos, err := a.client.listRepositories(paths[0], paths[1])
+			if err != nil {
+				return nil, err
+			}
+			addRepositories(paths[0], repos, name)
+		}
+	} else {
+		namespaces, err := a.client.listNamespaces()
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range namespaces {
+			repos, err := a.client.listRepositories(ns.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			addRepositories(ns.Name, repos, "")
+		}
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].Name < repositories[j].Name
+	})
+	return repositories, nil
+}
@@ -0,0 +1,294 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcr
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTCR struct {
+	namespaces []string
+	// namespace -> repository names
+	repositories map[string][]string
+	// repository name -> image versions
+	images map[string][]string
+	// action -> the requests received
+	requests map[string][]map[string]interface{}
+}
+
+func (f *fakeTCR) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "TC3-HMAC-SHA256 Credential=secret-id/") ||
+		r.Header.Get("X-TC-Version") != apiVersion {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	action := r.Header.Get("X-TC-Action")
+	data, _ := ioutil.ReadAll(r.Body)
+	req := map[string]interface{}{}
+	json.Unmarshal(data, &req)
+	f.requests[action] = append(f.requests[action], req)
+
+	var resp interface{}
+	switch action {
+	case "DescribeInstances":
+		resp = map[string]interface{}{
+			"Registries": []map[string]interface{}{
+				{"RegistryId": "tcr-123", "RegistryName": "127", "RegionName": "ap-guangzhou"},
+			},
+			"TotalCount": 1,
+		}
+	case "DescribeNamespaces":
+		list := []map[string]interface{}{}
+		for _, ns := range f.namespaces {
+			list = append(list, map[string]interface{}{"Name": ns})
+		}
+		resp = map[string]interface{}{"NamespaceList": list, "TotalCount": len(list)}
+	case "CreateNamespace":
+		f.namespaces = append(f.namespaces, req["NamespaceName"].(string))
+		resp = map[string]interface{}{}
+	case "DescribeRepositories":
+		ns := req["NamespaceName"].(string)
+		name, _ := req["RepositoryName"].(string)
+		list := []map[string]interface{}{}
+		for _, repo := range f.repositories[ns] {
+			if strings.Contains(repo, name) {
+				list = append(list, map[string]interface{}{"Name": ns + "/" + repo, "Namespace": ns})
+			}
+		}
+		resp = map[string]interface{}{"RepositoryList": list, "TotalCount": len(list)}
+	case "DescribeImages":
+		repo := req["NamespaceName"].(string) + "/" + req["RepositoryName"].(string)
+		version, _ := req["ImageVersion"].(string)
+		list := []map[string]interface{}{}
+		for _, v := range f.images[repo] {
+			if strings.Contains(v, version) {
+				list = append(list, map[string]interface{}{"ImageVersion": v, "Digest": "sha256:" + v})
+			}
+		}
+		resp = map[string]interface{}{"ImageInfoList": list, "TotalCount": len(list)}
+	case "CreateInstanceToken":
+		resp = map[string]interface{}{
+			"Username": "tcr-user",
+			"Token":    "tcr-token",
+			"ExpTime":  time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond),
+		}
+	default:
+		resp = map[string]interface{}{
+			"Error": map[string]interface{}{"Code": "InvalidAction", "Message": "unknown action"},
+		}
+	}
+	data, _ = json.Marshal(map[string]interface{}{"Response": resp})
+	w.Write(data)
+}
+
+func getMockAdapter(t *testing.T) (*adapter, *fakeTCR, *httptest.Server) {
+	fake := &fakeTCR{
+		namespaces: []string{"library", "test"},
+		repositories: map[string][]string{
+			"library": {"hello-world", "hello-world-dev"},
+			"test":    {"busybox"},
+		},
+		images: map[string][]string{
+			"library/hello-world":     {"1.0", "2.0"},
+			"library/hello-world-dev": {"1.0"},
+			"test/busybox":            {"latest"},
+		},
+		requests: map[string][]map[string]interface{}{},
+	}
+	server := test.NewServer(&test.RequestHandlerMapping{
+		Method:  http.MethodPost,
+		Pattern: "/",
+		Handler: fake.handle,
+	})
+
+	endpoint := apiEndpoint
+	apiEndpoint = server.URL
+	defer func() {
+		apiEndpoint = endpoint
+	}()
+	a, err := newAdapter(&model.Registry{
+		Type: model.RegistryTypeTencentTcr,
+		URL:  server.URL,
+		Credential: &model.Credential{
+			AccessKey:    "secret-id",
+			AccessSecret: "secret-key",
+		},
+	})
+	require.Nil(t, err)
+	return a, fake, server
+}
+
+func TestGetInstanceName(t *testing.T) {
+	_, err := getInstanceName("harbor")
+	assert.NotNil(t, err)
+
+	name, err := getInstanceName("https://harbor.tencentcloudcr.com")
+	require.Nil(t, err)
+	assert.Equal(t, "harbor", name)
+}
+
+func TestInfo(t *testing.T) {
+	factory, err := adp.GetFactory(model.RegistryTypeTencentTcr)
+	require.Nil(t, err)
+	require.NotNil(t, factory)
+
+	a, _, s := getMockAdapter(t)
+	defer s.Close()
+	info, err := a.Info()
+	require.Nil(t, err)
+	assert.EqualValues(t, model.RegistryTypeTencentTcr, info.Type)
+	assert.EqualValues(t, 1, len(info.SupportedResourceTypes))
+	assert.EqualValues(t, model.ResourceTypeImage, info.SupportedResourceTypes[0])
+}
+
+func TestCall(t *testing.T) {
+	a, fake, s := getMockAdapter(t)
+	defer s.Close()
+
+	err := a.client.call("Unknown", "", map[string]string{}, &apiResponse{})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "InvalidAction")
+
+	// the instance is looked up only once
+	_, err = a.client.getInstance()
+	require.Nil(t, err)
+	ins, err := a.client.getInstance()
+	require.Nil(t, err)
+	assert.Equal(t, "tcr-123", ins.RegistryID)
+	assert.Equal(t, "ap-guangzhou", ins.RegionName)
+	assert.Equal(t, 1, len(fake.requests["DescribeInstances"]))
+}
+
+func TestPrepareForPush(t *testing.T) {
+	a, fake, s := getMockAdapter(t)
+	defer s.Close()
+
+	err := a.PrepareForPush([]*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "busybox",
+				},
+			},
+		},
+	})
+	assert.NotNil(t, err)
+
+	err = a.PrepareForPush([]*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+			},
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "prod/app/busybox",
+				},
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(fake.requests["CreateNamespace"]))
+	assert.Equal(t, "prod", fake.requests["CreateNamespace"][0]["NamespaceName"])
+	assert.Equal(t, "tcr-123", fake.requests["CreateNamespace"][0]["RegistryId"])
+}
+
+func TestFetchImages(t *testing.T) {
+	a, fake, s := getMockAdapter(t)
+	defer s.Close()
+
+	resources, err := a.FetchImages(nil)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(resources))
+	assert.Equal(t, "library/hello-world", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0", "2.0"}, resources[0].Metadata.Vtags)
+	assert.Equal(t, "library/hello-world-dev", resources[1].Metadata.Repository.Name)
+	assert.Equal(t, "test/busybox", resources[2].Metadata.Repository.Name)
+	assert.Equal(t, []string{"latest"}, resources[2].Metadata.Vtags)
+
+	// the specific names and tag are pushed down to the API
+	fake.requests = map[string][]map[string]interface{}{}
+	resources, err = a.FetchImages([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/hello-world",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "1.0",
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, "library/hello-world", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Vtags)
+	assert.Equal(t, 0, len(fake.requests["DescribeNamespaces"]))
+	require.Equal(t, 1, len(fake.requests["DescribeRepositories"]))
+	assert.Equal(t, "hello-world", fake.requests["DescribeRepositories"][0]["RepositoryName"])
+	require.Equal(t, 1, len(fake.requests["DescribeImages"]))
+	assert.Equal(t, "1.0", fake.requests["DescribeImages"][0]["ImageVersion"])
+
+	// the patterns are applied on the results
+	resources, err = a.FetchImages([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "**/hello-*",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "2.*",
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(resources))
+	assert.Equal(t, "library/hello-world", resources[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"2.0"}, resources[0].Metadata.Vtags)
+}
+
+func TestCredential(t *testing.T) {
+	a, fake, s := getMockAdapter(t)
+	defer s.Close()
+
+	cred := newCredential(a.client)
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, "https://harbor.tencentcloudcr.com/v2/", nil)
+		require.Nil(t, err)
+		require.Nil(t, cred.Modify(req))
+		username, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "tcr-user", username)
+		assert.Equal(t, "tcr-token", password)
+	}
+	// the token is cached
+	assert.Equal(t, 1, len(fake.requests["CreateInstanceToken"]))
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcr
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/common/utils/log"
+)
+
+// the token is refreshed a while before it expires
+const tokenExpirationLeeway = 5 * time.Minute
+
+// credential gets a temporary username and token of the TCR instance via the TCR API
+// and uses them as the basic auth credential of the registry API
+type credential struct {
+	sync.Mutex
+	client    *client
+	username  string
+	token     string
+	expiredAt time.Time
+}
+
+var _ modifier.Modifier = &credential{}
+
+func newCredential(client *client) *credential {
+	return &credential{
+		client: client,
+	}
+}
+
+// Modify ...
+func (c *credential) Modify(r *http.Request) error {
+	c.Lock()
+	defer c.Unlock()
+	if !c.isTokenValid() {
+		log.Debugf("the temporary token of TCR instance %s expired, refresh it", c.client.name)
+		resp, err := c.client.createTemporaryToken()
+		if err != nil {
+			return err
+		}
+		c.username = resp.Username
+		c.token = resp.Token
+		c.expiredAt = time.Unix(0, resp.ExpTime*int64(time.Millisecond))
+	}
+	r.SetBasicAuth(c.username, c.token)
+	return nil
+}
+
+func (c *credential) isTokenValid() bool {
+	if len(c.token) == 0 {
+		return false
+	}
+	return time.Now().Add(tokenExpirationLeeway).Before(c.expiredAt)
+}
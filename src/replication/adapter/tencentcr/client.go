@@ -0,0 +1,295 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcr
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	service     = "tcr"
+	apiVersion  = "2019-09-24"
+	algorithm   = "TC3-HMAC-SHA256"
+	contentType = "application/json; charset=utf-8"
+	pageSize    = 100
+)
+
+// the endpoint of the TCR API, it's a variable to be replaced in the tests
+var apiEndpoint = "https://tcr.tencentcloudapi.com"
+
+// client calls the TCR API which is signed by the TC3-HMAC-SHA256 algorithm, see
+// https://cloud.tencent.com/document/api/1141/40541 for the details
+type client struct {
+	sync.Mutex
+	endpoint  string
+	secretID  string
+	secretKey string
+	// the name of the TCR instance, e.g. "harbor" for the instance "harbor.tencentcloudcr.com"
+	name     string
+	instance *instance
+	client   *http.Client
+}
+
+func newClient(endpoint, name, secretID, secretKey string, transport http.RoundTripper) *client {
+	return &client{
+		endpoint:  endpoint,
+		secretID:  secretID,
+		secretKey: secretKey,
+		name:      name,
+		client: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+type errorer interface {
+	err() error
+}
+
+func (r *apiResponse) err() error {
+	if r.Error == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %s (request ID: %s)", r.Error.Code, r.Error.Message, r.RequestID)
+}
+
+// call the action of the TCR API in the region, the response is decoded into resp
+func (c *client) call(action, region string, req interface{}, resp errorer) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("X-TC-Action", action)
+	request.Header.Set("X-TC-Version", apiVersion)
+	request.Header.Set("X-TC-Timestamp", strconv.FormatInt(timestamp, 10))
+	if len(region) > 0 {
+		request.Header.Set("X-TC-Region", region)
+	}
+	request.Header.Set("Authorization", sign(c.secretID, c.secretKey, u.Host, payload, timestamp))
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call %s, status code: %d, body: %s", action, response.StatusCode, string(data))
+	}
+	result := &struct {
+		Response errorer `json:"Response"`
+	}{
+		Response: resp,
+	}
+	if err = json.Unmarshal(data, result); err != nil {
+		return err
+	}
+	if err = resp.err(); err != nil {
+		return fmt.Errorf("failed to call %s: %v", action, err)
+	}
+	return nil
+}
+
+// sign returns the value of the "Authorization" header
+func sign(secretID, secretKey, host string, payload []byte, timestamp int64) string {
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\n", contentType, host)
+	signedHeaders := "content-type;host"
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
+		http.MethodPost, "/", "", canonicalHeaders, signedHeaders, sha256hex(payload))
+
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	scope := fmt.Sprintf("%s/%s/tc3_request", date, service)
+	stringToSign := fmt.Sprintf("%s\n%d\n%s\n%s", algorithm, timestamp, scope, sha256hex([]byte(canonicalRequest)))
+
+	secretDate := hmacSHA256([]byte("TC3"+secretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, secretID, scope, signedHeaders, signature)
+}
+
+func sha256hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// getInstance returns the TCR instance whose name is the first label of the registry
+// domain, the instance is cached once it's found
+func (c *client) getInstance() (*instance, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.instance != nil {
+		return c.instance, nil
+	}
+	resp := &describeInstancesResponse{}
+	err := c.call("DescribeInstances", "", &describeInstancesRequest{
+		Filters: []*filter{
+			{
+				Name:   "RegistryName",
+				Values: []string{c.name},
+			},
+		},
+		AllRegion: true,
+		Limit:     pageSize,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+	for _, ins := range resp.Registries {
+		if ins.RegistryName == c.name {
+			c.instance = ins
+			return ins, nil
+		}
+	}
+	return nil, fmt.Errorf("the TCR instance %s not found", c.name)
+}
+
+func (c *client) listNamespaces() ([]*namespace, error) {
+	ins, err := c.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []*namespace{}
+	for offset := int64(0); ; offset += pageSize {
+		resp := &describeNamespacesResponse{}
+		if err = c.call("DescribeNamespaces", ins.RegionName, &describeNamespacesRequest{
+			RegistryID: ins.RegistryID,
+			Offset:     offset,
+			Limit:      pageSize,
+		}, resp); err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, resp.NamespaceList...)
+		if len(resp.NamespaceList) == 0 || int64(len(namespaces)) >= resp.TotalCount {
+			break
+		}
+	}
+	return namespaces, nil
+}
+
+func (c *client) createNamespace(name string) error {
+	ins, err := c.getInstance()
+	if err != nil {
+		return err
+	}
+	return c.call("CreateNamespace", ins.RegionName, &createNamespaceRequest{
+		RegistryID:    ins.RegistryID,
+		NamespaceName: name,
+	}, &apiResponse{})
+}
+
+// listRepositories lists the repositories under the namespace, the name is matched fuzzily
+// by TCR and an empty name matches all
+func (c *client) listRepositories(ns, name string) ([]*repository, error) {
+	ins, err := c.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	repositories := []*repository{}
+	for offset := int64(0); ; offset += pageSize {
+		resp := &describeRepositoriesResponse{}
+		if err = c.call("DescribeRepositories", ins.RegionName, &describeRepositoriesRequest{
+			RegistryID:     ins.RegistryID,
+			NamespaceName:  ns,
+			RepositoryName: name,
+			Offset:         offset,
+			Limit:          pageSize,
+		}, resp); err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, resp.RepositoryList...)
+		if len(resp.RepositoryList) == 0 || int64(len(repositories)) >= resp.TotalCount {
+			break
+		}
+	}
+	return repositories, nil
+}
+
+// listImages lists the images of the repository, the version is matched fuzzily by TCR
+// and an empty version matches all
+func (c *client) listImages(ns, repo, version string) ([]*image, error) {
+	ins, err := c.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	images := []*image{}
+	for offset := int64(0); ; offset += pageSize {
+		resp := &describeImagesResponse{}
+		if err = c.call("DescribeImages", ins.RegionName, &describeImagesRequest{
+			RegistryID:     ins.RegistryID,
+			NamespaceName:  ns,
+			RepositoryName: repo,
+			ImageVersion:   version,
+			Offset:         offset,
+			Limit:          pageSize,
+		}, resp); err != nil {
+			return nil, err
+		}
+		images = append(images, resp.ImageInfoList...)
+		if len(resp.ImageInfoList) == 0 || int64(len(images)) >= resp.TotalCount {
+			break
+		}
+	}
+	return images, nil
+}
+
+// createTemporaryToken creates a temporary token to access the registry API of the instance
+func (c *client) createTemporaryToken() (*createInstanceTokenResponse, error) {
+	ins, err := c.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	resp := &createInstanceTokenResponse{}
+	if err = c.call("CreateInstanceToken", ins.RegionName, &createInstanceTokenRequest{
+		RegistryID: ins.RegistryID,
+		TokenType:  "temp",
+		Desc:       "harbor replication",
+	}, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
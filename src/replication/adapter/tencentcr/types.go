@@ -0,0 +1,131 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcr
+
+// the common part of the responses of the TCR API
+type apiError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+type apiResponse struct {
+	Error     *apiError `json:"Error"`
+	RequestID string    `json:"RequestId"`
+}
+
+type filter struct {
+	Name   string   `json:"Name"`
+	Values []string `json:"Values"`
+}
+
+type describeInstancesRequest struct {
+	Filters   []*filter `json:"Filters,omitempty"`
+	AllRegion bool      `json:"AllRegion"`
+	Offset    int64     `json:"Offset"`
+	Limit     int64     `json:"Limit"`
+}
+
+type instance struct {
+	RegistryID   string `json:"RegistryId"`
+	RegistryName string `json:"RegistryName"`
+	RegionName   string `json:"RegionName"`
+	PublicDomain string `json:"PublicDomain"`
+	Status       string `json:"Status"`
+}
+
+type describeInstancesResponse struct {
+	apiResponse
+	Registries []*instance `json:"Registries"`
+	TotalCount int64       `json:"TotalCount"`
+}
+
+type describeNamespacesRequest struct {
+	RegistryID    string `json:"RegistryId"`
+	NamespaceName string `json:"NamespaceName,omitempty"`
+	Offset        int64  `json:"Offset"`
+	Limit         int64  `json:"Limit"`
+}
+
+type namespace struct {
+	Name   string `json:"Name"`
+	Public bool   `json:"Public"`
+}
+
+type describeNamespacesResponse struct {
+	apiResponse
+	NamespaceList []*namespace `json:"NamespaceList"`
+	TotalCount    int64        `json:"TotalCount"`
+}
+
+type createNamespaceRequest struct {
+	RegistryID    string `json:"RegistryId"`
+	NamespaceName string `json:"NamespaceName"`
+	IsPublic      bool   `json:"IsPublic"`
+}
+
+type describeRepositoriesRequest struct {
+	RegistryID     string `json:"RegistryId"`
+	NamespaceName  string `json:"NamespaceName"`
+	RepositoryName string `json:"RepositoryName,omitempty"`
+	Offset         int64  `json:"Offset"`
+	Limit          int64  `json:"Limit"`
+}
+
+type repository struct {
+	// the name contains the namespace, e.g. "library/hello-world"
+	Name      string `json:"Name"`
+	Namespace string `json:"Namespace"`
+}
+
+type describeRepositoriesResponse struct {
+	apiResponse
+	RepositoryList []*repository `json:"RepositoryList"`
+	TotalCount     int64         `json:"TotalCount"`
+}
+
+type describeImagesRequest struct {
+	RegistryID     string `json:"RegistryId"`
+	NamespaceName  string `json:"NamespaceName"`
+	RepositoryName string `json:"RepositoryName"`
+	ImageVersion   string `json:"ImageVersion,omitempty"`
+	Offset         int64  `json:"Offset"`
+	Limit          int64  `json:"Limit"`
+}
+
+type image struct {
+	ImageVersion string `json:"ImageVersion"`
+	Digest       string `json:"Digest"`
+	Size         int64  `json:"Size"`
+}
+
+type describeImagesResponse struct {
+	apiResponse
+	ImageInfoList []*image `json:"ImageInfoList"`
+	TotalCount    int64    `json:"TotalCount"`
+}
+
+type createInstanceTokenRequest struct {
+	RegistryID string `json:"RegistryId"`
+	TokenType  string `json:"TokenType"`
+	Desc       string `json:"Desc"`
+}
+
+type createInstanceTokenResponse struct {
+	apiResponse
+	Username string `json:"Username"`
+	Token    string `json:"Token"`
+	// the expiration time in milliseconds
+	ExpTime int64 `json:"ExpTime"`
+}
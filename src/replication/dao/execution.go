@@ -353,3 +353,20 @@ func UpdateTaskStatus(id int64, status string, statusRevision int64, statusCondi
 func taskFinished(status string) bool {
 	return status == models.TaskStatusFailed || status == models.TaskStatusStopped || status == models.TaskStatusSucceed
 }
+
+// FinishExecution persists the final status and the statistics of the execution,
+// returns false if the execution has been finished by others
+func FinishExecution(execution *models.Execution) (bool, error) {
+	sql := `update replication_execution set status = ?, total = ?, failed = ?, succeed = ?,
+		in_progress = ?, stopped = ?, end_time = ? where id = ? and status = ?`
+	res, err := dao.GetOrmer().Raw(sql, execution.Status, execution.Total, execution.Failed, execution.Succeed,
+		execution.InProgress, execution.Stopped, execution.EndTime, execution.ID, models.ExecutionStatusInProgress).Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
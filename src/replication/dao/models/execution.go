@@ -92,6 +92,7 @@ var TaskPropsName = TaskFieldsName{
 	Status:       "Status",
 	StartTime:    "StartTime",
 	EndTime:      "EndTime",
+	RetryCount:   "RetryCount",
 }
 
 // TaskFieldsName defines the props of Task
@@ -105,6 +106,7 @@ type TaskFieldsName struct {
 	Status       string
 	StartTime    string
 	EndTime      string
+	RetryCount   string
 }
 
 // Task represent the tasks in one execution.
@@ -120,6 +122,11 @@ type Task struct {
 	StatusRevision int64     `orm:"column(status_revision)"`
 	StartTime      time.Time `orm:"column(start_time)" json:"start_time"`
 	EndTime        time.Time `orm:"column(end_time)" json:"end_time,omitempty"`
+	// the times the task is re-scheduled after it failed or stopped
+	RetryCount int `orm:"column(retry_count)" json:"retry_count"`
+	// the source and destination resources of the task in JSON without the registries,
+	// they're used to re-schedule the task
+	Resources string `orm:"column(resources)" json:"-"`
 }
 
 // TableName is required by by beego orm to map Execution to table replication_execution
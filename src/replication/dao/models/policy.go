@@ -11,7 +11,12 @@ type RepPolicy struct {
 	SrcRegistryID     int64     `orm:"column(src_registry_id)" json:"src_registry_id"`
 	DestRegistryID    int64     `orm:"column(dest_registry_id)" json:"dest_registry_id"`
 	DestNamespace     string    `orm:"column(dest_namespace)" json:"dest_namespace"`
+	NamespaceMapping  string    `orm:"column(namespace_mapping)" json:"namespace_mapping"`
 	Override          bool      `orm:"column(override)" json:"override"`
+	CopyConcurrency   int       `orm:"column(copy_concurrency)" json:"copy_concurrency"`
+	BandwidthLimit    int64     `orm:"column(bandwidth_limit)" json:"bandwidth_limit"`
+	TimeWindows       string    `orm:"column(time_windows)" json:"time_windows"`
+	ReplicateMetadata string    `orm:"column(replicate_metadata)" json:"replicate_metadata"`
 	Enabled           bool      `orm:"column(enabled)" json:"enabled"`
 	Trigger           string    `orm:"column(trigger)" json:"trigger"`
 	Filters           string    `orm:"column(filters)" json:"filters"`
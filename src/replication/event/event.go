@@ -22,10 +22,16 @@ const (
 	EventTypeImageDelete = "image_delete"
 	EventTypeChartUpload = "chart_upload"
 	EventTypeChartDelete = "chart_delete"
+	// the label events of the tags, the labels of the resource are
+	// the ones that the tag has after the label is added/removed
+	EventTypeImageLabelAdd    = "image_label_add"
+	EventTypeImageLabelRemove = "image_label_remove"
 )
 
 // Event is the model that defines the image/chart pull/push event
 type Event struct {
 	Type     string
 	Resource *model.Resource
+	// the name of the label added or removed, only for the label events
+	Label string
 }
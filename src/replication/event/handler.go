@@ -21,6 +21,7 @@ import (
 	"github.com/goharbor/harbor/src/replication/util"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/config"
 	"github.com/goharbor/harbor/src/replication/model"
 	"github.com/goharbor/harbor/src/replication/operation"
@@ -60,6 +61,8 @@ func (h *handler) Handle(event *Event) error {
 	case EventTypeImagePush, EventTypeChartUpload,
 		EventTypeImageDelete, EventTypeChartDelete:
 		policies, err = h.getRelatedPolicies(event.Resource)
+	case EventTypeImageLabelAdd, EventTypeImageLabelRemove:
+		return h.handleLabelEvent(event)
 	default:
 		return fmt.Errorf("unsupported event type %s", event.Type)
 	}
@@ -73,18 +76,86 @@ func (h *handler) Handle(event *Event) error {
 	}
 
 	for _, policy := range policies {
-		if err := PopulateRegistries(h.registryMgr, policy); err != nil {
+		if err := h.replicate(event.Type, policy, event.Resource); err != nil {
 			return err
 		}
-		id, err := h.opCtl.StartReplication(policy, event.Resource, model.TriggerTypeEventBased)
+	}
+	return nil
+}
+
+// the label events only matter to the policies that have label filters: the tag is
+// replicated if it begins to match the label filters and deleted from the destination
+// registry if it doesn't match any more
+func (h *handler) handleLabelEvent(event *Event) error {
+	if len(event.Label) == 0 {
+		return errors.New("invalid event: no label specified")
+	}
+	current := event.Resource.Metadata.Labels
+	previous := []string{}
+	for _, label := range current {
+		if !(event.Type == EventTypeImageLabelAdd && label == event.Label) {
+			previous = append(previous, label)
+		}
+	}
+	if event.Type == EventTypeImageLabelRemove {
+		previous = append(previous, event.Label)
+	}
+
+	policies, err := h.getRelatedPolicies(event.Resource)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if !hasLabelFilter(policy.Filters) {
+			continue
+		}
+		before, err := matchLabels(policy.Filters, previous)
+		if err != nil {
+			return err
+		}
+		after, err := matchLabels(policy.Filters, current)
 		if err != nil {
 			return err
 		}
-		log.Debugf("%s event received, the replication execution %d started", event.Type, id)
+		var resource *model.Resource
+		switch {
+		case !before && after:
+			resource = event.Resource
+		case before && !after && policy.Deletion:
+			// the labels that the tag had are carried by the deleted resource
+			// to make it pass the label filters of the deletion flow
+			resource = &model.Resource{
+				Type: event.Resource.Type,
+				Metadata: &model.ResourceMetadata{
+					Repository: event.Resource.Metadata.Repository,
+					Vtags:      event.Resource.Metadata.Vtags,
+					Labels:     previous,
+					Digest:     event.Resource.Metadata.Digest,
+				},
+				Deleted: true,
+			}
+		default:
+			continue
+		}
+		if err := h.replicate(event.Type, policy, resource); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func (h *handler) replicate(eventType string, policy *model.Policy, resource *model.Resource) error {
+	if err := PopulateRegistries(h.registryMgr, policy); err != nil {
+		return err
+	}
+	id, err := h.opCtl.StartReplication(policy, resource, model.TriggerTypeEventBased)
+	if err != nil {
+		return err
+	}
+	log.Debugf("%s event received, the replication execution %d started", eventType, id)
+	return nil
+}
+
 func (h *handler) getRelatedPolicies(resource *model.Resource) ([]*model.Policy, error) {
 	_, policies, err := h.policyCtl.List()
 	if err != nil {
@@ -146,6 +217,33 @@ func match(filters []*model.Filter, resource *model.Resource) (bool, error) {
 	return match, nil
 }
 
+func hasLabelFilter(filters []*model.Filter) bool {
+	for _, filter := range filters {
+		if filter.Type == model.FilterTypeLabel || filter.Type == model.FilterTypeLabelExclude {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLabels returns whether the tag with the labels matches the label filters
+func matchLabels(filters []*model.Filter, labels []string) (bool, error) {
+	vTags := []*adp.VTag{
+		{
+			Labels: labels,
+		},
+	}
+	for _, filter := range filters {
+		if filter.Type != model.FilterTypeLabel && filter.Type != model.FilterTypeLabelExclude {
+			continue
+		}
+		if err := filter.DoFilter(&vTags); err != nil {
+			return false, err
+		}
+	}
+	return len(vTags) > 0, nil
+}
+
 // PopulateRegistries populates the source registry and destination registry properties for policy
 func PopulateRegistries(registryMgr registry.Manager, policy *model.Policy) error {
 	if policy == nil {
@@ -24,14 +24,21 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type fakedOperationController struct{}
+type fakedOperationController struct {
+	// the resources that the replications are started for
+	resources []*model.Resource
+}
 
 func (f *fakedOperationController) StartReplication(policy *model.Policy, resource *model.Resource, trigger model.TriggerType) (int64, error) {
+	f.resources = append(f.resources, resource)
 	return 1, nil
 }
 func (f *fakedOperationController) StopReplication(int64) error {
 	return nil
 }
+func (f *fakedOperationController) RetryReplication(policy *model.Policy, executionID int64) (int, error) {
+	return 1, nil
+}
 func (f *fakedOperationController) ListExecutions(...*models.ExecutionQuery) (int64, []*models.Execution, error) {
 	return 0, nil, nil
 }
@@ -50,6 +57,9 @@ func (f *fakedOperationController) UpdateTaskStatus(id int64, status string, sta
 func (f *fakedOperationController) GetTaskLog(int64) ([]byte, error) {
 	return nil, nil
 }
+func (f *fakedOperationController) PreviewReplication(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error) {
+	return 0, nil, nil
+}
 
 type fakedPolicyController struct{}
 
@@ -163,6 +173,32 @@ func (f *fakedPolicyController) List(...*model.PolicyQuery) (int64, []*model.Pol
 				ID: 1,
 			},
 		},
+		// label filter
+		{
+			ID:       7,
+			Enabled:  true,
+			Deletion: true,
+			Trigger: &model.Trigger{
+				Type: model.TriggerTypeEventBased,
+			},
+			Filters: []*model.Filter{
+				{
+					Type:  model.FilterTypeName,
+					Value: "label/*",
+				},
+				{
+					Type:  model.FilterTypeLabel,
+					Value: []string{"prod"},
+				},
+				{
+					Type:  model.FilterTypeLabelExclude,
+					Value: []string{"deprecated"},
+				},
+			},
+			DestRegistry: &model.Registry{
+				ID: 1,
+			},
+		},
 	}
 	return int64(len(polices)), polices, nil
 }
@@ -299,3 +335,62 @@ func TestHandle(t *testing.T) {
 	})
 	require.Nil(t, err)
 }
+
+func TestHandleLabelEvent(t *testing.T) {
+	config.Config = &config.Configuration{}
+	opCtl := &fakedOperationController{}
+	handler := NewHandler(&fakedPolicyController{},
+		&fakedRegistryManager{},
+		opCtl)
+	newEvent := func(typ, label string, labels ...string) *Event {
+		return &Event{
+			Type: typ,
+			Resource: &model.Resource{
+				Type: model.ResourceTypeImage,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: "label/hello-world",
+					},
+					Vtags:  []string{"latest"},
+					Labels: labels,
+				},
+			},
+			Label: label,
+		}
+	}
+
+	// no label specified
+	err := handler.Handle(newEvent(EventTypeImageLabelAdd, ""))
+	require.NotNil(t, err)
+
+	// begin to match the label filters: replicate the tag
+	err = handler.Handle(newEvent(EventTypeImageLabelAdd, "prod", "prod"))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(opCtl.resources))
+	assert.False(t, opCtl.resources[0].Deleted)
+
+	// still match the label filters: do nothing
+	opCtl.resources = nil
+	err = handler.Handle(newEvent(EventTypeImageLabelAdd, "test", "prod", "test"))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(opCtl.resources))
+
+	// still doesn't match the label filters: do nothing
+	err = handler.Handle(newEvent(EventTypeImageLabelRemove, "test"))
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(opCtl.resources))
+
+	// doesn't match the label filters any more: delete the tag
+	err = handler.Handle(newEvent(EventTypeImageLabelRemove, "prod"))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(opCtl.resources))
+	assert.True(t, opCtl.resources[0].Deleted)
+	assert.Equal(t, []string{"prod"}, opCtl.resources[0].Metadata.Labels)
+
+	opCtl.resources = nil
+	err = handler.Handle(newEvent(EventTypeImageLabelAdd, "deprecated", "prod", "deprecated"))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(opCtl.resources))
+	assert.True(t, opCtl.resources[0].Deleted)
+	assert.Equal(t, []string{"prod"}, opCtl.resources[0].Metadata.Labels)
+}
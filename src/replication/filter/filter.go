@@ -17,6 +17,8 @@ package filter
 import (
 	"errors"
 	"reflect"
+	"regexp"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
 	"github.com/goharbor/harbor/src/replication/util"
@@ -41,6 +43,18 @@ type Filterable interface {
 	GetLabels() []string
 }
 
+// DigestFilterable is the Filterable whose digest is known
+type DigestFilterable interface {
+	Filterable
+	GetDigest() string
+}
+
+// PushTimeFilterable is the Filterable whose push time is known
+type PushTimeFilterable interface {
+	Filterable
+	GetPushTime() time.Time
+}
+
 // Filter defines the methods that a filter must implement
 type Filter interface {
 	// return whether the filter is applied to the specified Filterable
@@ -78,6 +92,52 @@ func NewVTagLabelFilter(labels []string) Filter {
 	}
 }
 
+// NewRepositoryNameExcludeFilter return a Filter to exclude the repositories whose names match the pattern
+func NewRepositoryNameExcludeFilter(pattern string) Filter {
+	return &nameFilter{
+		filterableType: FilterableTypeRepository,
+		pattern:        pattern,
+		exclude:        true,
+	}
+}
+
+// NewVTagNameExcludeFilter return a Filter to exclude the vtags whose names match the pattern
+func NewVTagNameExcludeFilter(pattern string) Filter {
+	return &nameFilter{
+		filterableType: FilterableTypeVTag,
+		pattern:        pattern,
+		exclude:        true,
+	}
+}
+
+// NewVTagRegexFilter return a Filter to filter the vtags according to the regular expression
+func NewVTagRegexFilter(pattern string) Filter {
+	return &regexFilter{
+		pattern: pattern,
+	}
+}
+
+// NewVTagDigestFilter return a Filter to filter the vtags according to the digest
+func NewVTagDigestFilter(digest string) Filter {
+	return &digestFilter{
+		digest: digest,
+	}
+}
+
+// NewVTagPushedAfterFilter return a Filter to filter the vtags pushed after the specified time
+func NewVTagPushedAfterFilter(t time.Time) Filter {
+	return &pushedAfterFilter{
+		time: t,
+	}
+}
+
+// NewVTagLabelExcludeFilter return a Filter to exclude the vtags which have any of the labels
+func NewVTagLabelExcludeFilter(labels []string) Filter {
+	return &labelExcludeFilter{
+		labels: labels,
+	}
+}
+
 type resourceTypeFilter struct {
 	resourceType string
 }
@@ -107,6 +167,8 @@ func (r *resourceTypeFilter) Filter(filterables ...Filterable) ([]Filterable, er
 type nameFilter struct {
 	filterableType FilterableType
 	pattern        string
+	// exclude the filterables matching the pattern rather than including them
+	exclude bool
 }
 
 func (n *nameFilter) ApplyTo(filterable Filterable) bool {
@@ -127,12 +189,12 @@ func (n *nameFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
 		if err != nil {
 			return nil, err
 		}
-		if match {
-			log.Debugf("%q matches the pattern %q of name filter", name, n.pattern)
+		if match != n.exclude {
+			log.Debugf("%q matches the name filter(pattern: %q, exclude: %t)", name, n.pattern, n.exclude)
 			result = append(result, filterable)
 			continue
 		}
-		log.Debugf("%q doesn't match the pattern %q of name filter, skip", name, n.pattern)
+		log.Debugf("%q doesn't match the name filter(pattern: %q, exclude: %t), skip", name, n.pattern, n.exclude)
 	}
 	return result, nil
 }
@@ -179,6 +241,99 @@ func (l *labelFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
 	return result, nil
 }
 
+// applyToVTag returns whether the filterable is a vtag
+func applyToVTag(filterable Filterable) bool {
+	return filterable != nil && filterable.GetFilterableType() == FilterableTypeVTag
+}
+
+type regexFilter struct {
+	pattern string
+}
+
+func (r *regexFilter) ApplyTo(filterable Filterable) bool {
+	return applyToVTag(filterable)
+}
+
+func (r *regexFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
+	re, err := regexp.Compile(r.pattern)
+	if err != nil {
+		return nil, err
+	}
+	result := []Filterable{}
+	for _, filterable := range filterables {
+		if re.MatchString(filterable.GetName()) {
+			result = append(result, filterable)
+		}
+	}
+	return result, nil
+}
+
+type digestFilter struct {
+	digest string
+}
+
+func (d *digestFilter) ApplyTo(filterable Filterable) bool {
+	return applyToVTag(filterable)
+}
+
+// the vtags whose digests are unknown are filtered out
+func (d *digestFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
+	result := []Filterable{}
+	for _, filterable := range filterables {
+		f, ok := filterable.(DigestFilterable)
+		if ok && f.GetDigest() == d.digest {
+			result = append(result, filterable)
+		}
+	}
+	return result, nil
+}
+
+type pushedAfterFilter struct {
+	time time.Time
+}
+
+func (p *pushedAfterFilter) ApplyTo(filterable Filterable) bool {
+	return applyToVTag(filterable)
+}
+
+// the vtags whose push time are unknown are filtered out
+func (p *pushedAfterFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
+	result := []Filterable{}
+	for _, filterable := range filterables {
+		f, ok := filterable.(PushTimeFilterable)
+		if ok && f.GetPushTime().After(p.time) {
+			result = append(result, filterable)
+		}
+	}
+	return result, nil
+}
+
+type labelExcludeFilter struct {
+	labels []string
+}
+
+func (l *labelExcludeFilter) ApplyTo(filterable Filterable) bool {
+	return applyToVTag(filterable)
+}
+
+func (l *labelExcludeFilter) Filter(filterables ...Filterable) ([]Filterable, error) {
+	excluded := map[string]struct{}{}
+	for _, label := range l.labels {
+		excluded[label] = struct{}{}
+	}
+	result := []Filterable{}
+FILTERABLES:
+	for _, filterable := range filterables {
+		for _, label := range filterable.GetLabels() {
+			if _, exist := excluded[label]; exist {
+				continue FILTERABLES
+			}
+		}
+		result = append(result, filterable)
+	}
+	return result, nil
+}
+
 // DoFilter is a util function to help filter filterables easily.
 // The parameter "filterables" must be a pointer points to a slice
 // whose elements must be Filterable. After applying all the "filters"
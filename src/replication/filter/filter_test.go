@@ -17,6 +17,7 @@ package filter
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,8 @@ type fakeFilterable struct {
 	resourceType   string
 	name           string
 	labels         []string
+	digest         string
+	pushTime       time.Time
 }
 
 func (f *fakeFilterable) GetFilterableType() FilterableType {
@@ -43,6 +46,12 @@ func (f *fakeFilterable) GetName() string {
 func (f *fakeFilterable) GetLabels() []string {
 	return f.labels
 }
+func (f *fakeFilterable) GetDigest() string {
+	return f.digest
+}
+func (f *fakeFilterable) GetPushTime() time.Time {
+	return f.pushTime
+}
 
 func TestFilterOfResourceTypeFilter(t *testing.T) {
 	filterable := &fakeFilterable{
@@ -168,3 +177,93 @@ func TestDoFilter(t *testing.T) {
 		assert.True(t, reflect.DeepEqual(tag1, filterables[0]))
 	}
 }
+
+func TestFilterOfExcludeFilters(t *testing.T) {
+	filterables := []Filterable{
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "1.0",
+			labels:         []string{"prod"},
+		},
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "1.0-dev",
+			labels:         []string{"dev"},
+		},
+	}
+
+	result, err := NewVTagNameExcludeFilter("*-dev").Filter(filterables...)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "1.0", result[0].GetName())
+
+	result, err = NewVTagLabelExcludeFilter([]string{"prod", "test"}).Filter(filterables...)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "1.0-dev", result[0].GetName())
+
+	repository := &fakeFilterable{
+		filterableType: FilterableTypeRepository,
+		name:           "library/hello-world",
+	}
+	result, err = NewRepositoryNameExcludeFilter("library/*").Filter(repository)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(result))
+}
+
+func TestFilterOfRegexFilter(t *testing.T) {
+	filterables := []Filterable{
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "v1.2.3",
+		},
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "latest",
+		},
+	}
+	result, err := NewVTagRegexFilter(`^v\d+\.\d+\.\d+$`).Filter(filterables...)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "v1.2.3", result[0].GetName())
+
+	_, err = NewVTagRegexFilter("[").Filter(filterables...)
+	assert.NotNil(t, err)
+}
+
+func TestFilterOfDigestAndPushedAfterFilters(t *testing.T) {
+	now := time.Now()
+	filterables := []Filterable{
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "new",
+			digest:         "sha256:1",
+			pushTime:       now,
+		},
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "old",
+			digest:         "sha256:2",
+			pushTime:       now.Add(-10 * 24 * time.Hour),
+		},
+		// the digest and push time are unknown
+		&fakeFilterable{
+			filterableType: FilterableTypeVTag,
+			name:           "unknown",
+		},
+	}
+
+	result, err := NewVTagDigestFilter("sha256:2").Filter(filterables...)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "old", result[0].GetName())
+
+	result, err = NewVTagPushedAfterFilter(now.Add(-7 * 24 * time.Hour)).Filter(filterables...)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(result))
+	assert.Equal(t, "new", result[0].GetName())
+
+	assert.False(t, NewVTagPushedAfterFilter(now).ApplyTo(&fakeFilterable{
+		filterableType: FilterableTypeRepository,
+	}))
+}
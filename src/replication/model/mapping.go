@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+)
+
+// the characters allowed in the prefix and suffix of the repository name
+var affixRegexp = regexp.MustCompile(`^[a-z0-9._-]*$`)
+
+// NamespaceMapping defines how the repositories of the source registry are mapped to the destination registry.
+// The mapping is applied in order: replacing the leading levels of the namespace with the "DestNamespace" of
+// the policy, substituting the path with the regular expression and adding the prefix and suffix to the name
+type NamespaceMapping struct {
+	// ReplaceCount is the number of the leading levels of the source namespace replaced by the "DestNamespace"
+	// (or removed when it's empty), e.g. replacing 1 level maps "library/team/app" to "<dest_namespace>/team/app".
+	// -1 replaces all the levels of the namespace, i.e. only the name of the repository is kept
+	ReplaceCount int `json:"replace_count"`
+	// Pattern is the regular expression applied to the path after the replacement
+	Pattern string `json:"pattern,omitempty"`
+	// Replacement replaces the matches of the pattern, "$1" can be used to refer to the submatch
+	Replacement string `json:"replacement,omitempty"`
+	// Prefix and Suffix are added to the last level of the path, i.e. the name of the repository
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// Valid the namespace mapping
+func (n *NamespaceMapping) Valid() error {
+	if n.ReplaceCount < -1 {
+		return fmt.Errorf("invalid replace count: %d", n.ReplaceCount)
+	}
+	if len(n.Pattern) > 0 {
+		if _, err := regexp.Compile(n.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %s: %v", n.Pattern, err)
+		}
+	} else if len(n.Replacement) > 0 {
+		return errors.New("the pattern cannot be empty when the replacement is set")
+	}
+	if !affixRegexp.MatchString(n.Prefix) {
+		return fmt.Errorf("invalid prefix: %s", n.Prefix)
+	}
+	if !affixRegexp.MatchString(n.Suffix) {
+		return fmt.Errorf("invalid suffix: %s", n.Suffix)
+	}
+	return nil
+}
+
+// Map the source repository to the destination repository, an error is returned if the result
+// isn't a valid repository name, e.g. the pattern replaces the whole path with an empty string
+func (n *NamespaceMapping) Map(repository, destNamespace string) (string, error) {
+	levels := strings.Split(repository, "/")
+	namespaces, name := levels[:len(levels)-1], levels[len(levels)-1]
+
+	count := n.ReplaceCount
+	if count == -1 || count > len(namespaces) {
+		count = len(namespaces)
+	}
+	levels = namespaces[count:]
+	if len(destNamespace) > 0 {
+		levels = append([]string{destNamespace}, levels...)
+	}
+	path := strings.Join(append(levels, name), "/")
+
+	if len(n.Pattern) > 0 {
+		// the pattern is validated when creating the policy
+		if re, err := regexp.Compile(n.Pattern); err == nil {
+			path = re.ReplaceAllString(path, n.Replacement)
+		}
+	}
+
+	if len(n.Prefix) > 0 || len(n.Suffix) > 0 {
+		i := strings.LastIndex(path, "/")
+		path = path[:i+1] + n.Prefix + path[i+1:] + n.Suffix
+	}
+	if len(path) == 0 || !utils.ValidateRepo(path) {
+		return "", fmt.Errorf("the repository %s is mapped to the invalid repository name %q", repository, path)
+	}
+	return path, nil
+}
@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidOfNamespaceMapping(t *testing.T) {
+	cases := []struct {
+		mapping *NamespaceMapping
+		pass    bool
+	}{
+		{mapping: &NamespaceMapping{ReplaceCount: -1}, pass: true},
+		{mapping: &NamespaceMapping{ReplaceCount: -2}, pass: false},
+		{mapping: &NamespaceMapping{Pattern: "^team-(.*)$", Replacement: "$1"}, pass: true},
+		{mapping: &NamespaceMapping{Pattern: "(", Replacement: "$1"}, pass: false},
+		{mapping: &NamespaceMapping{Replacement: "$1"}, pass: false},
+		{mapping: &NamespaceMapping{Prefix: "mirror-", Suffix: "_dr"}, pass: true},
+		{mapping: &NamespaceMapping{Prefix: "Mirror/"}, pass: false},
+		{mapping: &NamespaceMapping{Suffix: ":latest"}, pass: false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.pass, c.mapping.Valid() == nil, "%+v", c.mapping)
+	}
+}
+
+func TestMapOfNamespaceMapping(t *testing.T) {
+	cases := []struct {
+		mapping       *NamespaceMapping
+		repository    string
+		destNamespace string
+		expected      string
+	}{
+		// keep the path
+		{mapping: &NamespaceMapping{}, repository: "library/team/app", expected: "library/team/app"},
+		// prefix the path with the destination namespace
+		{mapping: &NamespaceMapping{}, repository: "library/team/app", destNamespace: "dr", expected: "dr/library/team/app"},
+		// replace the first level
+		{mapping: &NamespaceMapping{ReplaceCount: 1}, repository: "library/team/app", destNamespace: "dr", expected: "dr/team/app"},
+		// remove the first level
+		{mapping: &NamespaceMapping{ReplaceCount: 1}, repository: "library/team/app", expected: "team/app"},
+		// replace all the levels
+		{mapping: &NamespaceMapping{ReplaceCount: -1}, repository: "library/team/app", destNamespace: "dr", expected: "dr/app"},
+		// the count is larger than the levels
+		{mapping: &NamespaceMapping{ReplaceCount: 5}, repository: "library/team/app", destNamespace: "dr", expected: "dr/app"},
+		// no namespace
+		{mapping: &NamespaceMapping{ReplaceCount: -1}, repository: "app", destNamespace: "dr", expected: "dr/app"},
+		// flatten the path with the regular expression
+		{
+			mapping:       &NamespaceMapping{ReplaceCount: 1, Pattern: "^([^/]+)/([^/]+)/([^/]+)$", Replacement: "$1/$2-$3"},
+			repository:    "library/team/app",
+			destNamespace: "dr",
+			expected:      "dr/team-app",
+		},
+		// prefix and suffix
+		{
+			mapping:       &NamespaceMapping{ReplaceCount: -1, Prefix: "mirror-", Suffix: "-dr"},
+			repository:    "library/team/app",
+			destNamespace: "dr",
+			expected:      "dr/mirror-app-dr",
+		},
+		{mapping: &NamespaceMapping{Prefix: "mirror-"}, repository: "app", expected: "mirror-app"},
+	}
+	for _, c := range cases {
+		repository, err := c.mapping.Map(c.repository, c.destNamespace)
+		require.Nil(t, err, "%+v", c.mapping)
+		assert.Equal(t, c.expected, repository, "%+v", c.mapping)
+	}
+
+	// mapped to the invalid repository names
+	invalids := []*NamespaceMapping{
+		// empty
+		{Pattern: ".*"},
+		// the empty levels
+		{Pattern: "team", Replacement: ""},
+		// the upper case
+		{Pattern: "app", Replacement: "App"},
+	}
+	for _, mapping := range invalids {
+		_, err := mapping.Map("library/team/app", "dr")
+		assert.NotNil(t, err, "%+v", mapping)
+	}
+}
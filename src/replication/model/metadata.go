@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "encoding/json"
+
+// MetadataReplication specifies which metadata of the artifacts is replicated along with
+// the artifacts, it's only supported between Harbor registries
+type MetadataReplication struct {
+	// the labels of the artifacts, the missing labels are created on the destination registry
+	Labels bool `json:"labels"`
+	// the latest vulnerability report of each mime type
+	ScanReports bool `json:"scan_reports"`
+}
+
+// Enabled returns whether any metadata is replicated
+func (m *MetadataReplication) Enabled() bool {
+	return m != nil && (m.Labels || m.ScanReports)
+}
+
+// Label is the label attached to the artifact
+type Label struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	// "g" for the global labels and "p" for the project labels
+	Scope string `json:"scope"`
+}
+
+// ArtifactMetadata is the metadata of one artifact replicated along with it
+type ArtifactMetadata struct {
+	Labels []*Label `json:"labels"`
+	// the vulnerability reports keyed by the mime types of the reports
+	ScanReports map[string]json.RawMessage `json:"scan_reports"`
+}
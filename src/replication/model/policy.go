@@ -16,6 +16,7 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/goharbor/harbor/src/replication/filter"
@@ -31,10 +32,24 @@ const (
 	FilterTypeName     FilterType = "name"
 	FilterTypeTag      FilterType = "tag"
 	FilterTypeLabel    FilterType = "label"
+	// the repositories/tags whose names match the doublestar pattern are excluded
+	FilterTypeNameExclude FilterType = "name_exclude"
+	FilterTypeTagExclude  FilterType = "tag_exclude"
+	// the tags whose names match the regular expression
+	FilterTypeTagRegex FilterType = "tag_regex"
+	// the tags whose digests are the same with the value
+	FilterTypeDigest FilterType = "digest"
+	// the tags pushed after the time in RFC3339 format or in the last N days
+	FilterTypePushedAfter FilterType = "pushed_after"
+	// the tags which have any of the labels are excluded
+	FilterTypeLabelExclude FilterType = "label_exclude"
 
 	TriggerTypeManual     TriggerType = "manual"
 	TriggerTypeScheduled  TriggerType = "scheduled"
 	TriggerTypeEventBased TriggerType = "event_based"
+
+	// MaxCopyConcurrency is the max number of the layers of one image copied concurrently
+	MaxCopyConcurrency = 10
 )
 
 // Policy defines the structure of a replication policy
@@ -52,6 +67,9 @@ type Policy struct {
 	// or keep namespaces same with the source ones (under this case,
 	// the DestNamespace should be set to empty)
 	DestNamespace string `json:"dest_namespace"`
+	// NamespaceMapping customizes how the source repositories are mapped to the destination,
+	// the modes described above are used if it isn't set
+	NamespaceMapping *NamespaceMapping `json:"namespace_mapping,omitempty"`
 	// Filters
 	Filters []*Filter `json:"filters"`
 	// Trigger
@@ -61,6 +79,15 @@ type Policy struct {
 	Deletion bool `json:"deletion"`
 	// If override the image tag
 	Override bool `json:"override"`
+	// The number of the layers of one image copied concurrently, 0 means copying them one by one
+	CopyConcurrency int `json:"copy_concurrency"`
+	// The max bandwidth in bytes per second used by the replication of the policy, 0 means no limit
+	BandwidthLimit int64 `json:"bandwidth_limit"`
+	// The time windows in which the replication is allowed to run, the replication is
+	// paused out of them and resumed when the next window starts. Empty means always allowed
+	TimeWindows []*TimeWindow `json:"time_windows"`
+	// The metadata replicated along with the artifacts
+	ReplicateMetadata *MetadataReplication `json:"replicate_metadata,omitempty"`
 	// Operations
 	Enabled      bool      `json:"enabled"`
 	CreationTime time.Time `json:"creation_time"`
@@ -89,7 +116,8 @@ func (p *Policy) Valid(v *validation.Validation) {
 	// valid the filters
 	for _, filter := range p.Filters {
 		switch filter.Type {
-		case FilterTypeResource, FilterTypeName, FilterTypeTag:
+		case FilterTypeResource, FilterTypeName, FilterTypeTag,
+			FilterTypeNameExclude, FilterTypeTagExclude, FilterTypeTagRegex, FilterTypeDigest:
 			value, ok := filter.Value.(string)
 			if !ok {
 				v.SetError("filters", "the type of filter value isn't string")
@@ -102,25 +130,46 @@ func (p *Policy) Valid(v *validation.Validation) {
 					break
 				}
 			}
-		case FilterTypeLabel:
-			labels, ok := filter.Value.([]interface{})
-			if !ok {
-				v.SetError("filters", "the type of label filter value isn't string slice")
-				break
-			}
-			for _, label := range labels {
-				_, ok := label.(string)
-				if !ok {
-					v.SetError("filters", "the type of label filter value isn't string slice")
+			if filter.Type == FilterTypeTagRegex {
+				if _, err := regexp.Compile(value); err != nil {
+					v.SetError("filters", fmt.Sprintf("invalid regular expression of tag filter: %s", value))
 					break
 				}
 			}
+		case FilterTypePushedAfter:
+			if _, err := pushedAfter(filter.Value, time.Now()); err != nil {
+				v.SetError("filters", err.Error())
+			}
+		case FilterTypeLabel, FilterTypeLabelExclude:
+			if _, err := LabelsOf(filter.Value); err != nil {
+				v.SetError("filters", err.Error())
+			}
 		default:
 			v.SetError("filters", "invalid filter type")
 			break
 		}
 	}
 
+	if p.NamespaceMapping != nil {
+		if err := p.NamespaceMapping.Valid(); err != nil {
+			v.SetError("namespace_mapping", err.Error())
+		}
+	}
+
+	if p.CopyConcurrency < 0 || p.CopyConcurrency > MaxCopyConcurrency {
+		v.SetError("copy_concurrency", fmt.Sprintf("should be in the range of 0 to %d", MaxCopyConcurrency))
+	}
+
+	if p.BandwidthLimit < 0 {
+		v.SetError("bandwidth_limit", "cannot be negative")
+	}
+	for _, window := range p.TimeWindows {
+		if err := window.Valid(); err != nil {
+			v.SetError("time_windows", err.Error())
+			break
+		}
+	}
+
 	// valid trigger
 	if p.Trigger != nil {
 		switch p.Trigger.Type {
@@ -162,12 +211,33 @@ func (f *Filter) DoFilter(filterables interface{}) error {
 	case FilterTypeTag:
 		ft = filter.NewVTagNameFilter(f.Value.(string))
 	case FilterTypeLabel:
-		labels, ok := f.Value.([]string)
-		if ok {
-			ft = filter.NewVTagLabelFilter(labels)
+		labels, err := LabelsOf(f.Value)
+		if err != nil {
+			return err
 		}
+		ft = filter.NewVTagLabelFilter(labels)
 	case FilterTypeResource:
 		ft = filter.NewResourceTypeFilter(f.Value.(string))
+	case FilterTypeNameExclude:
+		ft = filter.NewRepositoryNameExcludeFilter(f.Value.(string))
+	case FilterTypeTagExclude:
+		ft = filter.NewVTagNameExcludeFilter(f.Value.(string))
+	case FilterTypeTagRegex:
+		ft = filter.NewVTagRegexFilter(f.Value.(string))
+	case FilterTypeDigest:
+		ft = filter.NewVTagDigestFilter(f.Value.(string))
+	case FilterTypePushedAfter:
+		t, err := pushedAfter(f.Value, time.Now())
+		if err != nil {
+			return err
+		}
+		ft = filter.NewVTagPushedAfterFilter(t)
+	case FilterTypeLabelExclude:
+		labels, err := LabelsOf(f.Value)
+		if err != nil {
+			return err
+		}
+		ft = filter.NewVTagLabelExcludeFilter(labels)
 	default:
 		return fmt.Errorf("unsupported filter type: %s", f.Type)
 	}
@@ -175,6 +245,51 @@ func (f *Filter) DoFilter(filterables interface{}) error {
 	return filter.DoFilter(filterables, ft)
 }
 
+// LabelsOf returns the labels of the label filters, the value is []string for the policies loaded
+// from the database and []interface{} for the ones decoded from the request body
+func LabelsOf(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		labels := []string{}
+		for _, item := range v {
+			label, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("the value of label filter should be string slice: %v", value)
+			}
+			labels = append(labels, label)
+		}
+		return labels, nil
+	default:
+		return nil, fmt.Errorf("the value of label filter should be string slice: %v", value)
+	}
+}
+
+// pushedAfter returns the time of the pushed after filter, the value is either a time
+// in RFC3339 format or the number of days before now
+func pushedAfter(value interface{}, now time.Time) (time.Time, error) {
+	var days float64
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time of pushed after filter: %s", v)
+		}
+		return t, nil
+	case float64:
+		days = v
+	case int:
+		days = float64(v)
+	default:
+		return time.Time{}, fmt.Errorf("the value of pushed after filter should be a time or the number of days: %v", value)
+	}
+	if days < 0 {
+		return time.Time{}, fmt.Errorf("the days of pushed after filter cannot be negative: %v", days)
+	}
+	return now.Add(-time.Duration(days * float64(24*time.Hour))), nil
+}
+
 // TriggerType represents the type of trigger.
 type TriggerType string
 
@@ -189,6 +304,98 @@ type TriggerSettings struct {
 	Cron string `json:"cron"`
 }
 
+// the layout of the start and end time of the time windows
+const timeWindowLayout = "15:04"
+
+// TimeWindow is the daily time window in UTC, e.g. from "20:00" to "06:00".
+// The window ends on the next day if the end time is earlier than the start time
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Valid the time window
+func (w *TimeWindow) Valid() error {
+	start, err := time.Parse(timeWindowLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time of the time window: %s", w.Start)
+	}
+	end, err := time.Parse(timeWindowLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("invalid end time of the time window: %s", w.End)
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("the start time and end time of the time window are the same: %s", w.Start)
+	}
+	return nil
+}
+
+// Contains returns whether the time is in the time window
+func (w *TimeWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(timeWindowLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(timeWindowLayout, w.End)
+	if err != nil {
+		return false
+	}
+	t = t.UTC()
+	// the minutes of the day
+	now := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	if from < to {
+		return from <= now && now < to
+	}
+	return now >= from || now < to
+}
+
+// InTimeWindows returns whether the time is in any of the time windows,
+// it's always true if no time windows are specified
+func InTimeWindows(windows []*TimeWindow, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, window := range windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// UntilTimeWindows returns how long it takes from the time to the start of the nearest time window,
+// it's 0 if the time is in the time windows already
+func UntilTimeWindows(windows []*TimeWindow, t time.Time) time.Duration {
+	if InTimeWindows(windows, t) {
+		return 0
+	}
+	t = t.UTC()
+	var until time.Duration
+	for _, window := range windows {
+		start, err := time.Parse(timeWindowLayout, window.Start)
+		if err != nil {
+			continue
+		}
+		next := time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if next.Before(t) {
+			next = next.Add(24 * time.Hour)
+		}
+		if d := next.Sub(t); until == 0 || d < until {
+			until = d
+		}
+	}
+	return until
+}
+
+// Throttle limits the bandwidth and the running time of the replication of one policy
+type Throttle struct {
+	PolicyID       int64         `json:"policy_id"`
+	BandwidthLimit int64         `json:"bandwidth_limit"`
+	TimeWindows    []*TimeWindow `json:"time_windows"`
+}
+
 // PolicyQuery defines the query conditions for listing policies
 type PolicyQuery struct {
 	Name string
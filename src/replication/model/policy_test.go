@@ -17,9 +17,12 @@ package model
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/astaxie/beego/validation"
+	"github.com/goharbor/harbor/src/replication/filter"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidOfPolicy(t *testing.T) {
@@ -185,6 +188,77 @@ func TestValidOfPolicy(t *testing.T) {
 			},
 			pass: false,
 		},
+		// invalid time window
+		{
+			policy: &Policy{
+				Name: "policy01",
+				SrcRegistry: &Registry{
+					ID: 0,
+				},
+				DestRegistry: &Registry{
+					ID: 1,
+				},
+				TimeWindows: []*TimeWindow{
+					{
+						Start: "25:00",
+						End:   "06:00",
+					},
+				},
+			},
+			pass: false,
+		},
+		// invalid regular expression of tag filter
+		{
+			policy: &Policy{
+				Name: "policy01",
+				SrcRegistry: &Registry{
+					ID: 0,
+				},
+				DestRegistry: &Registry{
+					ID: 1,
+				},
+				Filters: []*Filter{
+					{
+						Type:  FilterTypeTagRegex,
+						Value: "[",
+					},
+				},
+			},
+			pass: false,
+		},
+		// invalid pushed after filter
+		{
+			policy: &Policy{
+				Name: "policy01",
+				SrcRegistry: &Registry{
+					ID: 0,
+				},
+				DestRegistry: &Registry{
+					ID: 1,
+				},
+				Filters: []*Filter{
+					{
+						Type:  FilterTypePushedAfter,
+						Value: "7 days",
+					},
+				},
+			},
+			pass: false,
+		},
+		// negative bandwidth limit
+		{
+			policy: &Policy{
+				Name: "policy01",
+				SrcRegistry: &Registry{
+					ID: 0,
+				},
+				DestRegistry: &Registry{
+					ID: 1,
+				},
+				BandwidthLimit: -1,
+			},
+			pass: false,
+		},
 		// pass
 		{
 			policy: &Policy{
@@ -204,6 +278,22 @@ func TestValidOfPolicy(t *testing.T) {
 						Type:  FilterTypeName,
 						Value: "library/**",
 					},
+					{
+						Type:  FilterTypeTagExclude,
+						Value: "*-dev",
+					},
+					{
+						Type:  FilterTypeTagRegex,
+						Value: `^v\d+\.\d+\.\d+$`,
+					},
+					{
+						Type:  FilterTypePushedAfter,
+						Value: float64(7),
+					},
+					{
+						Type:  FilterTypeLabelExclude,
+						Value: []interface{}{"dev"},
+					},
 				},
 				Trigger: &Trigger{
 					Type: TriggerTypeScheduled,
@@ -223,3 +313,112 @@ func TestValidOfPolicy(t *testing.T) {
 		assert.Equal(t, c.pass, len(v.Errors) == 0)
 	}
 }
+
+func TestInTimeWindows(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2019, 10, 1, hour, minute, 0, 0, time.UTC)
+	}
+	daytime := &TimeWindow{Start: "09:00", End: "18:00"}
+	night := &TimeWindow{Start: "20:00", End: "06:00"}
+
+	assert.True(t, InTimeWindows(nil, at(12, 0)))
+
+	assert.True(t, InTimeWindows([]*TimeWindow{daytime}, at(9, 0)))
+	assert.True(t, InTimeWindows([]*TimeWindow{daytime}, at(17, 59)))
+	assert.False(t, InTimeWindows([]*TimeWindow{daytime}, at(18, 0)))
+
+	assert.True(t, InTimeWindows([]*TimeWindow{night}, at(23, 0)))
+	assert.True(t, InTimeWindows([]*TimeWindow{night}, at(5, 0)))
+	assert.False(t, InTimeWindows([]*TimeWindow{night}, at(12, 0)))
+
+	assert.True(t, InTimeWindows([]*TimeWindow{daytime, night}, at(12, 0)))
+	assert.False(t, InTimeWindows([]*TimeWindow{daytime, night}, at(19, 0)))
+
+	// the time in other time zones is converted to UTC
+	assert.True(t, InTimeWindows([]*TimeWindow{daytime}, at(12, 0).In(time.FixedZone("UTC+8", 8*3600))))
+}
+
+func TestUntilTimeWindows(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2019, 10, 1, hour, minute, 0, 0, time.UTC)
+	}
+	daytime := &TimeWindow{Start: "09:00", End: "18:00"}
+	night := &TimeWindow{Start: "20:00", End: "06:00"}
+
+	assert.Equal(t, time.Duration(0), UntilTimeWindows(nil, at(12, 0)))
+	assert.Equal(t, time.Duration(0), UntilTimeWindows([]*TimeWindow{daytime}, at(12, 0)))
+
+	assert.Equal(t, 3*time.Hour, UntilTimeWindows([]*TimeWindow{daytime}, at(6, 0)))
+	// starts on the next day
+	assert.Equal(t, 15*time.Hour, UntilTimeWindows([]*TimeWindow{daytime}, at(18, 0)))
+	// the nearest one
+	assert.Equal(t, 90*time.Minute, UntilTimeWindows([]*TimeWindow{daytime, night}, at(18, 30)))
+	assert.Equal(t, 3*time.Hour, UntilTimeWindows([]*TimeWindow{daytime, night}, at(6, 0)))
+}
+
+type fakeVTag struct {
+	labels []string
+}
+
+func (f *fakeVTag) GetFilterableType() filter.FilterableType {
+	return filter.FilterableTypeVTag
+}
+
+func (f *fakeVTag) GetResourceType() string {
+	return string(ResourceTypeImage)
+}
+
+func (f *fakeVTag) GetName() string {
+	return "latest"
+}
+
+func (f *fakeVTag) GetLabels() []string {
+	return f.labels
+}
+
+func TestDoFilterOfLabels(t *testing.T) {
+	newVTags := func() []*fakeVTag {
+		return []*fakeVTag{
+			{labels: []string{"prod"}},
+			{labels: []string{"dev"}},
+		}
+	}
+
+	// loaded from the database
+	vTags := newVTags()
+	f := &Filter{Type: FilterTypeLabel, Value: []string{"prod"}}
+	require.Nil(t, f.DoFilter(&vTags))
+	require.Equal(t, 1, len(vTags))
+	assert.Equal(t, []string{"prod"}, vTags[0].labels)
+
+	// decoded from the request body
+	vTags = newVTags()
+	f = &Filter{Type: FilterTypeLabelExclude, Value: []interface{}{"prod"}}
+	require.Nil(t, f.DoFilter(&vTags))
+	require.Equal(t, 1, len(vTags))
+	assert.Equal(t, []string{"dev"}, vTags[0].labels)
+
+	// invalid values
+	f = &Filter{Type: FilterTypeLabel, Value: []interface{}{1}}
+	assert.NotNil(t, f.DoFilter(&vTags))
+	f = &Filter{Type: FilterTypeLabelExclude, Value: "prod"}
+	assert.NotNil(t, f.DoFilter(&vTags))
+}
+
+func TestPushedAfter(t *testing.T) {
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	ti, err := pushedAfter(float64(7), now)
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), ti)
+
+	ti, err = pushedAfter("2020-01-01T00:00:00Z", now)
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), ti)
+
+	_, err = pushedAfter(float64(-1), now)
+	assert.NotNil(t, err)
+	_, err = pushedAfter("last week", now)
+	assert.NotNil(t, err)
+	_, err = pushedAfter(true, now)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,39 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// the status of the previewed item on the destination registry
+const (
+	PreviewStatusNew       = "new"
+	PreviewStatusExist     = "exist"
+	PreviewStatusDifferent = "different"
+)
+
+// PreviewSizeUnknown is the size of the previewed image whose size can't be resolved
+const PreviewSizeUnknown int64 = -1
+
+// PreviewItem is one tag/version that the replication policy would copy, it's resolved
+// without creating an execution
+type PreviewItem struct {
+	Type        ResourceType `json:"type"`
+	Source      string       `json:"source"`
+	Destination string       `json:"destination"`
+	// the total size in bytes of the layers of the image, it's 0 for charts and
+	// PreviewSizeUnknown for the images whose manifests don't carry the sizes(schema1)
+	Size int64 `json:"size"`
+	// "new": doesn't exist on the destination registry, "exist": the same content exists
+	// on the destination registry, "different": the destination has different content with the same tag
+	Status string `json:"status"`
+}
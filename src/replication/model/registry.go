@@ -33,6 +33,9 @@ const (
 	RegistryTypeJfrogArtifactory RegistryType = "jfrog-artifactory"
 	RegistryTypeQuayio           RegistryType = "quay-io"
 	RegistryTypeGitLab           RegistryType = "gitlab"
+	RegistryTypeOCILayout        RegistryType = "oci-layout"
+	RegistryTypeNexus            RegistryType = "nexus"
+	RegistryTypeTencentTcr       RegistryType = "tencent-tcr"
 
 	RegistryTypeHelmHub RegistryType = "helm-hub"
 
@@ -34,6 +34,8 @@ type ResourceMetadata struct {
 	Vtags      []string    `json:"v_tags"`
 	// TODO the labels should be put into tag and repository level?
 	Labels []string `json:"labels"`
+	// the digest of the image, only carried by the resources of the image events
+	Digest string `json:"digest,omitempty"`
 }
 
 // GetResourceName returns the name of the resource
@@ -61,4 +63,10 @@ type Resource struct {
 	Deleted bool `json:"deleted"`
 	// indicate whether the resource can be overridden
 	Override bool `json:"override"`
+	// the number of the layers of one image copied concurrently
+	CopyConcurrency int `json:"copy_concurrency,omitempty"`
+	// the bandwidth limit and time windows of the policy
+	Throttle *Throttle `json:"throttle,omitempty"`
+	// the metadata replicated along with the resource
+	ReplicateMetadata *MetadataReplication `json:"replicate_metadata,omitempty"`
 }
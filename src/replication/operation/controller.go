@@ -35,12 +35,17 @@ type Controller interface {
 	// trigger is used to specify what this replication is triggered by
 	StartReplication(policy *model.Policy, resource *model.Resource, trigger model.TriggerType) (int64, error)
 	StopReplication(int64) error
+	// re-schedule the failed and stopped tasks of the execution, returns the count of the tasks re-scheduled
+	RetryReplication(policy *model.Policy, executionID int64) (int, error)
 	ListExecutions(...*models.ExecutionQuery) (int64, []*models.Execution, error)
 	GetExecution(int64) (*models.Execution, error)
 	ListTasks(...*models.TaskQuery) (int64, []*models.Task, error)
 	GetTask(int64) (*models.Task, error)
 	UpdateTaskStatus(id int64, status string, statusRevision int64, statusCondition ...string) error
 	GetTaskLog(int64) ([]byte, error)
+	// resolve the resources that the policy would replicate without creating an execution,
+	// returns the total count and the items in the specified page
+	PreviewReplication(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error)
 }
 
 const (
@@ -200,6 +205,20 @@ func (c *controller) StopReplication(executionID int64) error {
 	return nil
 }
 
+func (c *controller) RetryReplication(policy *model.Policy, executionID int64) (int, error) {
+	execution, err := c.executionMgr.Get(executionID)
+	if err != nil {
+		return 0, err
+	}
+	if execution == nil {
+		return 0, fmt.Errorf("the execution %d not found", executionID)
+	}
+	if execution.Status == models.ExecutionStatusInProgress {
+		return 0, fmt.Errorf("the execution %d is in progress", executionID)
+	}
+	return c.flowCtl.Start(flow.NewRetryFlow(c.executionMgr, c.scheduler, executionID, policy))
+}
+
 func isTaskInFinalStatus(task *models.Task) bool {
 	if task == nil {
 		return false
@@ -239,6 +258,10 @@ func (c *controller) GetTaskLog(taskID int64) ([]byte, error) {
 	return c.executionMgr.GetTaskLog(taskID)
 }
 
+func (c *controller) PreviewReplication(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error) {
+	return flow.Preview(policy, page, size)
+}
+
 // create the execution record in database
 func createExecution(mgr execution.Manager, policyID int64, trigger model.TriggerType) (int64, error) {
 	id, err := mgr.Create(&models.Execution{
@@ -312,6 +312,12 @@ func TestStopReplication(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestRetryReplication(t *testing.T) {
+	// the faked task doesn't persist the resources, so it can't be retried
+	_, err := ctl.RetryReplication(&model.Policy{}, 1)
+	require.NotNil(t, err)
+}
+
 func TestListExecutions(t *testing.T) {
 	n, executions, err := ctl.ListExecutions()
 	require.Nil(t, err)
@@ -77,7 +77,10 @@ func (c *copyFlow) Run(interface{}) (int, error) {
 	}
 
 	srcResources = assembleSourceResources(srcResources, c.policy)
-	dstResources := assembleDestinationResources(srcResources, c.policy)
+	dstResources, err := assembleDestinationResources(srcResources, c.policy)
+	if err != nil {
+		return 0, err
+	}
 
 	if err = prepareForPush(dstAdapter, dstResources); err != nil {
 		return 0, err
@@ -54,7 +54,10 @@ func (d *deletionFlow) Run(interface{}) (int, error) {
 	}
 
 	srcResources = assembleSourceResources(srcResources, d.policy)
-	dstResources := assembleDestinationResources(srcResources, d.policy)
+	dstResources, err := assembleDestinationResources(srcResources, d.policy)
+	if err != nil {
+		return 0, err
+	}
 
 	items, err := preprocess(d.scheduler, srcResources, dstResources)
 	if err != nil {
@@ -0,0 +1,199 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	adp "github.com/goharbor/harbor/src/replication/adapter"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/goharbor/harbor/src/replication/transfer/image"
+)
+
+// the tag/version of the resource that the policy would replicate
+type previewCandidate struct {
+	src  *model.Resource
+	dst  *model.Resource
+	vtag string
+}
+
+// Preview resolves the resources that the policy would replicate and the existence of them
+// on the destination registry, nothing is pushed and no execution is created. Only the items
+// in the specified page are checked against the registries, the total count is returned as well
+func Preview(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error) {
+	srcAdapter, dstAdapter, err := initialize(policy)
+	if err != nil {
+		return 0, nil, err
+	}
+	srcResources, err := fetchResources(srcAdapter, policy)
+	if err != nil {
+		return 0, nil, err
+	}
+	srcResources = assembleSourceResources(srcResources, policy)
+	dstResources, err := assembleDestinationResources(srcResources, policy)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	candidates := []*previewCandidate{}
+	for i, srcResource := range srcResources {
+		for _, vtag := range srcResource.Metadata.Vtags {
+			candidates = append(candidates, &previewCandidate{
+				src:  srcResource,
+				dst:  dstResources[i],
+				vtag: vtag,
+			})
+		}
+	}
+	total := int64(len(candidates))
+	from, to := (page-1)*size, page*size
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+
+	items := []*model.PreviewItem{}
+	for _, candidate := range candidates[from:to] {
+		srcRepository := candidate.src.Metadata.Repository.Name
+		dstRepository := candidate.dst.Metadata.Repository.Name
+		item := &model.PreviewItem{
+			Type:        candidate.src.Type,
+			Source:      fmt.Sprintf("%s:%s", srcRepository, candidate.vtag),
+			Destination: fmt.Sprintf("%s:%s", dstRepository, candidate.vtag),
+		}
+		switch candidate.src.Type {
+		case model.ResourceTypeImage:
+			err = previewImage(srcAdapter, dstAdapter, srcRepository, dstRepository, candidate.vtag, item)
+		case model.ResourceTypeChart:
+			err = previewChart(dstAdapter, dstRepository, candidate.vtag, item)
+		default:
+			err = fmt.Errorf("unsupported resource type %s", candidate.src.Type)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		items = append(items, item)
+	}
+	return total, items, nil
+}
+
+func previewImage(src, dst adp.Adapter, srcRepository, dstRepository, tag string, item *model.PreviewItem) error {
+	srcReg, ok := src.(adp.ImageRegistry)
+	if !ok {
+		return fmt.Errorf("the adapter doesn't implement the ImageRegistry interface")
+	}
+	dstReg, ok := dst.(adp.ImageRegistry)
+	if !ok {
+		return fmt.Errorf("the adapter doesn't implement the ImageRegistry interface")
+	}
+	manifest, digest, err := pullManifest(srcReg, dstReg, srcRepository, tag)
+	if err != nil {
+		return fmt.Errorf("failed to pull the manifest of image %s:%s: %v", srcRepository, tag, err)
+	}
+	item.Size, err = manifestSize(srcReg, srcRepository, manifest)
+	if err != nil {
+		return fmt.Errorf("failed to get the size of image %s:%s: %v", srcRepository, tag, err)
+	}
+	exist, dstDigest, err := dstReg.ManifestExist(dstRepository, tag)
+	if err != nil {
+		return fmt.Errorf("failed to check the existence of the manifest of image %s:%s: %v", dstRepository, tag, err)
+	}
+	item.Status = status(exist, digest == dstDigest)
+	return nil
+}
+
+// pull the manifest that the transfer copies: the manifest list is kept only when the destination registry
+// keeps the manifest lists, otherwise the manifest selected from the list by the transfer is pulled
+func pullManifest(src, dst adp.ImageRegistry, repository, reference string) (distribution.Manifest, string, error) {
+	manifest, digest, err := src.PullManifest(repository, reference, registry.ManifestMediaTypes)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType, _, err := manifest.Payload()
+	if err != nil {
+		return nil, "", err
+	}
+	if !registry.IsManifestList(mediaType) || image.KeepManifestList(dst) {
+		return manifest, digest, nil
+	}
+	list, ok := manifest.(*manifestlist.DeserializedManifestList)
+	if !ok {
+		return nil, "", fmt.Errorf("the object isn't a DeserializedManifestList")
+	}
+	digest, err = image.SelectManifest(list)
+	if err != nil {
+		return nil, "", err
+	}
+	return pullManifest(src, dst, repository, digest)
+}
+
+// the total size of the content referenced by the manifest, the platform manifests
+// of the manifest list are counted with the content they reference
+func manifestSize(reg adp.ImageRegistry, repository string, manifest distribution.Manifest) (int64, error) {
+	if _, ok := manifest.(*schema1.SignedManifest); ok {
+		// the schema1 manifests don't carry the sizes of the layers
+		return model.PreviewSizeUnknown, nil
+	}
+	var size int64
+	for _, reference := range manifest.References() {
+		size += reference.Size
+		if !registry.IsSupportedManifest(reference.MediaType) {
+			continue
+		}
+		m, _, err := reg.PullManifest(repository, reference.Digest.String(), registry.ManifestMediaTypes)
+		if err != nil {
+			return 0, err
+		}
+		s, err := manifestSize(reg, repository, m)
+		if err != nil {
+			return 0, err
+		}
+		if s == model.PreviewSizeUnknown {
+			return model.PreviewSizeUnknown, nil
+		}
+		size += s
+	}
+	return size, nil
+}
+
+func previewChart(dst adp.Adapter, name, version string, item *model.PreviewItem) error {
+	reg, ok := dst.(adp.ChartRegistry)
+	if !ok {
+		return fmt.Errorf("the adapter doesn't implement the ChartRegistry interface")
+	}
+	exist, err := reg.ChartExist(name, version)
+	if err != nil {
+		return fmt.Errorf("failed to check the existence of the chart %s:%s: %v", name, version, err)
+	}
+	// the content of the charts isn't compared, the existing ones are overridden or skipped as a whole
+	item.Status = status(exist, true)
+	return nil
+}
+
+func status(exist, same bool) string {
+	if !exist {
+		return model.PreviewStatusNew
+	}
+	if same {
+		return model.PreviewStatusExist
+	}
+	return model.PreviewStatusDifferent
+}
@@ -0,0 +1,164 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreview(t *testing.T) {
+	policy := &model.Policy{
+		SrcRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+		DestRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+		DestNamespace: "dst",
+	}
+	total, items, err := Preview(policy, 1, 10)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Equal(t, 2, len(items))
+
+	assert.Equal(t, model.ResourceTypeImage, items[0].Type)
+	assert.Equal(t, "library/hello-world:latest", items[0].Source)
+	assert.Equal(t, "dst/hello-world:latest", items[0].Destination)
+	assert.Equal(t, int64(1100), items[0].Size)
+	assert.Equal(t, model.PreviewStatusNew, items[0].Status)
+
+	assert.Equal(t, model.ResourceTypeChart, items[1].Type)
+	assert.Equal(t, "library/harbor:0.2.0", items[1].Source)
+	assert.Equal(t, "dst/harbor:0.2.0", items[1].Destination)
+	assert.Equal(t, int64(0), items[1].Size)
+	assert.Equal(t, model.PreviewStatusNew, items[1].Status)
+}
+
+func TestPreviewPagination(t *testing.T) {
+	policy := &model.Policy{
+		SrcRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+		DestRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+	}
+	total, items, err := Preview(policy, 2, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Equal(t, 1, len(items))
+	assert.Equal(t, "library/harbor:0.2.0", items[0].Source)
+
+	// out of range
+	total, items, err = Preview(policy, 3, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, 0, len(items))
+}
+
+type schema1Adapter struct {
+	fakedAdapter
+}
+
+func (s *schema1Adapter) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
+	return &schema1.SignedManifest{
+		Manifest: schema1.Manifest{
+			FSLayers: []schema1.FSLayer{
+				{BlobSum: "sha256:beef"},
+			},
+		},
+	}, "sha256:schema1", nil
+}
+
+func TestPreviewImageOfSchema1(t *testing.T) {
+	item := &model.PreviewItem{}
+	err := previewImage(&schema1Adapter{}, &fakedAdapter{}, "library/hello-world", "library/hello-world", "latest", item)
+	require.Nil(t, err)
+	assert.Equal(t, model.PreviewSizeUnknown, item.Size)
+	assert.Equal(t, model.PreviewStatusNew, item.Status)
+}
+
+type manifestListAdapter struct {
+	fakedAdapter
+}
+
+func (m *manifestListAdapter) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
+	if reference != "latest" {
+		return m.fakedAdapter.PullManifest(repository, reference, accepttedMediaTypes)
+	}
+	list, err := manifestlist.FromDescriptors([]manifestlist.ManifestDescriptor{
+		{
+			Descriptor: distribution.Descriptor{
+				MediaType: schema2.MediaTypeManifest,
+				Size:      10,
+				Digest:    "sha256:arm",
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: "arm",
+				OS:           "linux",
+			},
+		},
+		{
+			Descriptor: distribution.Descriptor{
+				MediaType: schema2.MediaTypeManifest,
+				Size:      10,
+				Digest:    "sha256:amd64",
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: "amd64",
+				OS:           "linux",
+			},
+		},
+	})
+	return list, "sha256:list", err
+}
+
+type manifestListKeeperAdapter struct {
+	fakedAdapter
+}
+
+func (m *manifestListKeeperAdapter) KeepManifestLists() bool {
+	return true
+}
+
+func TestPreviewImageOfManifestList(t *testing.T) {
+	// the manifest selected from the list is replicated
+	item := &model.PreviewItem{}
+	err := previewImage(&manifestListAdapter{}, &fakedAdapter{}, "library/hello-world", "library/hello-world", "latest", item)
+	require.Nil(t, err)
+	assert.Equal(t, int64(1100), item.Size)
+	assert.Equal(t, model.PreviewStatusNew, item.Status)
+
+	// the whole manifest list is replicated
+	item = &model.PreviewItem{}
+	err = previewImage(&manifestListAdapter{}, &manifestListKeeperAdapter{}, "library/hello-world", "library/hello-world", "latest", item)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2220), item.Size)
+	assert.Equal(t, model.PreviewStatusNew, item.Status)
+}
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, model.PreviewStatusNew, status(false, false))
+	assert.Equal(t, model.PreviewStatusExist, status(true, true))
+	assert.Equal(t, model.PreviewStatusDifferent, status(true, false))
+}
@@ -0,0 +1,161 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication/dao/models"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/goharbor/harbor/src/replication/operation/execution"
+	"github.com/goharbor/harbor/src/replication/operation/scheduler"
+)
+
+type retryFlow struct {
+	executionID  int64
+	policy       *model.Policy
+	executionMgr execution.Manager
+	scheduler    scheduler.Scheduler
+}
+
+// NewRetryFlow returns an instance of the retry flow which re-schedules the failed
+// and stopped tasks of the execution
+func NewRetryFlow(executionMgr execution.Manager, scheduler scheduler.Scheduler,
+	executionID int64, policy *model.Policy) Flow {
+	return &retryFlow{
+		executionMgr: executionMgr,
+		scheduler:    scheduler,
+		executionID:  executionID,
+		policy:       policy,
+	}
+}
+
+func (r *retryFlow) Run(interface{}) (int, error) {
+	_, tasks, err := r.executionMgr.ListTasks(&models.TaskQuery{
+		ExecutionID: r.executionID,
+		Statuses:    []string{models.TaskStatusFailed, models.TaskStatusStopped},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the tasks of the execution %d: %v", r.executionID, err)
+	}
+
+	var items []*scheduler.ScheduleItem
+	var retried []*models.Task
+	var dstResources []*model.Resource
+	for _, task := range tasks {
+		item, err := unmarshalTaskResources(task.Resources)
+		if err != nil {
+			// the tasks created by the old versions don't persist the resources
+			log.Warningf("the task %d of the execution %d can not be retried: %v", task.ID, r.executionID, err)
+			continue
+		}
+		item.TaskID = task.ID
+		item.SrcResource.Registry = r.policy.SrcRegistry
+		item.DstResource.Registry = r.policy.DestRegistry
+		if !item.DstResource.Deleted {
+			dstResources = append(dstResources, item.DstResource)
+		}
+		items = append(items, item)
+		retried = append(retried, task)
+	}
+	if len(items) == 0 {
+		return 0, fmt.Errorf("no failed or stopped tasks of the execution %d can be retried", r.executionID)
+	}
+
+	if len(dstResources) > 0 {
+		_, dstAdapter, err := initialize(r.policy)
+		if err != nil {
+			return 0, err
+		}
+		if err = prepareForPush(dstAdapter, dstResources); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, task := range retried {
+		// the status revision is reset as the statuses of the new job are reported with the revisions of its own
+		if err = r.executionMgr.UpdateTask(&models.Task{
+			ID:             task.ID,
+			Status:         models.TaskStatusInitialized,
+			StatusRevision: 0,
+			JobID:          "",
+			EndTime:        time.Time{},
+			RetryCount:     task.RetryCount + 1,
+		}, "Status", "StatusRevision", "JobID", "EndTime", "RetryCount"); err != nil {
+			return 0, fmt.Errorf("failed to reset the task %d: %v", task.ID, err)
+		}
+	}
+	// the statistics are reset and aggregated from the tasks again as the execution is in progress
+	if err = r.executionMgr.Update(&models.Execution{
+		ID:     r.executionID,
+		Status: models.ExecutionStatusInProgress,
+	}, models.ExecutionPropsName.Status, models.ExecutionPropsName.StatusText,
+		models.ExecutionPropsName.Total, models.ExecutionPropsName.Failed,
+		models.ExecutionPropsName.Succeed, models.ExecutionPropsName.InProgress,
+		models.ExecutionPropsName.Stopped, models.ExecutionPropsName.EndTime); err != nil {
+		return 0, fmt.Errorf("failed to reset the execution %d: %v", r.executionID, err)
+	}
+	log.Debugf("%d tasks of the execution %d are reset for retrying", len(retried), r.executionID)
+
+	return schedule(r.scheduler, r.executionMgr, items)
+}
+
+// taskResources are the resources of the task persisted for retrying
+type taskResources struct {
+	SrcResource *model.Resource `json:"src_resource"`
+	DstResource *model.Resource `json:"dst_resource"`
+}
+
+// the registries are removed as they contain the credentials, they're populated
+// from the policy when retrying
+func marshalTaskResources(item *scheduler.ScheduleItem) (string, error) {
+	resources := &taskResources{}
+	if item.SrcResource != nil {
+		src := *item.SrcResource
+		src.Registry = nil
+		resources.SrcResource = &src
+	}
+	if item.DstResource != nil {
+		dst := *item.DstResource
+		dst.Registry = nil
+		resources.DstResource = &dst
+	}
+	data, err := json.Marshal(resources)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func unmarshalTaskResources(data string) (*scheduler.ScheduleItem, error) {
+	if len(data) == 0 {
+		return nil, errors.New("the resources of the task aren't persisted")
+	}
+	resources := &taskResources{}
+	if err := json.Unmarshal([]byte(data), resources); err != nil {
+		return nil, err
+	}
+	if resources.SrcResource == nil || resources.DstResource == nil {
+		return nil, errors.New("the resources of the task are incomplete")
+	}
+	return &scheduler.ScheduleItem{
+		SrcResource: resources.SrcResource,
+		DstResource: resources.DstResource,
+	}, nil
+}
@@ -0,0 +1,160 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication/dao/models"
+	"github.com/goharbor/harbor/src/replication/model"
+	"github.com/goharbor/harbor/src/replication/operation/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type retryExecutionManager struct {
+	fakedExecutionManager
+	tasks     []*models.Task
+	updated   []*models.Task
+	execution *models.Execution
+}
+
+func (r *retryExecutionManager) ListTasks(...*models.TaskQuery) (int64, []*models.Task, error) {
+	return int64(len(r.tasks)), r.tasks, nil
+}
+func (r *retryExecutionManager) UpdateTask(task *models.Task, props ...string) error {
+	r.updated = append(r.updated, task)
+	return nil
+}
+func (r *retryExecutionManager) Update(execution *models.Execution, props ...string) error {
+	r.execution = execution
+	return nil
+}
+
+func TestTaskResources(t *testing.T) {
+	item := &scheduler.ScheduleItem{
+		SrcResource: &model.Resource{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"1.0", "2.0"},
+			},
+			Registry: &model.Registry{
+				URL: "https://registry.harbor.local",
+				Credential: &model.Credential{
+					AccessSecret: "secret",
+				},
+			},
+		},
+		DstResource: &model.Resource{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "dst/hello-world",
+				},
+				Vtags: []string{"1.0", "2.0"},
+			},
+			Override: true,
+		},
+	}
+	data, err := marshalTaskResources(item)
+	require.Nil(t, err)
+	assert.NotContains(t, data, "secret")
+	// the registry of the item isn't changed
+	assert.NotNil(t, item.SrcResource.Registry)
+
+	it, err := unmarshalTaskResources(data)
+	require.Nil(t, err)
+	assert.Nil(t, it.SrcResource.Registry)
+	assert.Equal(t, "library/hello-world", it.SrcResource.Metadata.Repository.Name)
+	assert.Equal(t, []string{"1.0", "2.0"}, it.SrcResource.Metadata.Vtags)
+	assert.Equal(t, "dst/hello-world", it.DstResource.Metadata.Repository.Name)
+	assert.True(t, it.DstResource.Override)
+
+	_, err = unmarshalTaskResources("")
+	assert.NotNil(t, err)
+}
+
+func TestRunOfRetryFlow(t *testing.T) {
+	item := &scheduler.ScheduleItem{
+		SrcResource: &model.Resource{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"latest"},
+			},
+		},
+		DstResource: &model.Resource{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"latest"},
+			},
+		},
+	}
+	resources, err := marshalTaskResources(item)
+	require.Nil(t, err)
+	policy := &model.Policy{
+		SrcRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+		DestRegistry: &model.Registry{
+			Type: model.RegistryTypeHarbor,
+		},
+	}
+
+	// no tasks can be retried
+	executionMgr := &retryExecutionManager{
+		tasks: []*models.Task{
+			{
+				ID:     1,
+				Status: models.TaskStatusFailed,
+			},
+		},
+	}
+	_, err = NewRetryFlow(executionMgr, &fakedScheduler{}, 1, policy).Run(nil)
+	assert.NotNil(t, err)
+
+	executionMgr = &retryExecutionManager{
+		tasks: []*models.Task{
+			{
+				ID:         1,
+				Status:     models.TaskStatusFailed,
+				Resources:  resources,
+				RetryCount: 1,
+			},
+			{
+				ID:     2,
+				Status: models.TaskStatusStopped,
+			},
+		},
+	}
+	n, err := NewRetryFlow(executionMgr, &fakedScheduler{}, 1, policy).Run(nil)
+	require.Nil(t, err)
+	assert.Equal(t, 1, n)
+	// the task is reset and then updated with the job ID after scheduled
+	require.Equal(t, 2, len(executionMgr.updated))
+	assert.Equal(t, int64(1), executionMgr.updated[0].ID)
+	assert.Equal(t, models.TaskStatusInitialized, executionMgr.updated[0].Status)
+	assert.Equal(t, 2, executionMgr.updated[0].RetryCount)
+	require.NotNil(t, executionMgr.execution)
+	assert.Equal(t, models.ExecutionStatusInProgress, executionMgr.execution.Status)
+}
@@ -17,6 +17,7 @@ package flow
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
@@ -167,8 +168,99 @@ func filterResources(resources []*model.Resource, filters []*model.Filter) ([]*m
 				}
 				// NOTE: the property "Vtags" of the origin resource struct is overrided here
 				resource.Metadata.Vtags = versions
-			case model.FilterTypeLabel:
-				// TODO add support to label
+			case model.FilterTypeNameExclude:
+				pattern, ok := filter.Value.(string)
+				if !ok {
+					return nil, fmt.Errorf("%v is not a valid string", filter.Value)
+				}
+				if resource.Metadata == nil {
+					match = false
+					break FILTER_LOOP
+				}
+				m, err := util.Match(pattern, resource.Metadata.Repository.Name)
+				if err != nil {
+					return nil, err
+				}
+				if m {
+					match = false
+					break FILTER_LOOP
+				}
+			case model.FilterTypeTagExclude, model.FilterTypeTagRegex:
+				pattern, ok := filter.Value.(string)
+				if !ok {
+					return nil, fmt.Errorf("%v is not a valid string", filter.Value)
+				}
+				if resource.Metadata == nil {
+					match = false
+					break FILTER_LOOP
+				}
+				var re *regexp.Regexp
+				if filter.Type == model.FilterTypeTagRegex {
+					var err error
+					if re, err = regexp.Compile(pattern); err != nil {
+						return nil, err
+					}
+				}
+				var versions []string
+				for _, version := range resource.Metadata.Vtags {
+					var m bool
+					if re != nil {
+						m = re.MatchString(version)
+					} else {
+						excluded, err := util.Match(pattern, version)
+						if err != nil {
+							return nil, err
+						}
+						m = !excluded
+					}
+					if m {
+						versions = append(versions, version)
+					}
+				}
+				if len(versions) == 0 {
+					match = false
+					break FILTER_LOOP
+				}
+				resource.Metadata.Vtags = versions
+			case model.FilterTypeLabel, model.FilterTypeLabelExclude:
+				// only the deletion and label events carry the labels of the tags,
+				// the images just pushed have no labels
+				if resource.Metadata == nil || resource.Metadata.Labels == nil {
+					continue
+				}
+				vTags := []*adp.VTag{
+					{
+						Labels: resource.Metadata.Labels,
+					},
+				}
+				if err := filter.DoFilter(&vTags); err != nil {
+					return nil, err
+				}
+				if len(vTags) == 0 {
+					match = false
+					break FILTER_LOOP
+				}
+			case model.FilterTypeDigest:
+				// the image events carry the digests, the resources whose digests are unknown are filtered out
+				if resource.Metadata == nil {
+					match = false
+					break FILTER_LOOP
+				}
+				vTags := []*adp.VTag{
+					{
+						Digest: resource.Metadata.Digest,
+					},
+				}
+				if err := filter.DoFilter(&vTags); err != nil {
+					return nil, err
+				}
+				if len(vTags) == 0 {
+					match = false
+					break FILTER_LOOP
+				}
+			case model.FilterTypePushedAfter:
+				// the resources here come from the events which don't carry the push
+				// time(they are just pushed), so the filter isn't applied
 			default:
 				return nil, fmt.Errorf("unsupportted filter type: %v", filter.Type)
 			}
@@ -193,19 +285,34 @@ func assembleSourceResources(resources []*model.Resource,
 
 // assemble the destination resources by filling the metadata, registry and override properties
 func assembleDestinationResources(resources []*model.Resource,
-	policy *model.Policy) []*model.Resource {
+	policy *model.Policy) ([]*model.Resource, error) {
 	var result []*model.Resource
 	for _, resource := range resources {
+		name, err := mapRepository(resource.Metadata.Repository.Name, policy)
+		if err != nil {
+			return nil, err
+		}
 		res := &model.Resource{
-			Type:         resource.Type,
-			Registry:     policy.DestRegistry,
-			ExtendedInfo: resource.ExtendedInfo,
-			Deleted:      resource.Deleted,
-			Override:     policy.Override,
+			Type:            resource.Type,
+			Registry:        policy.DestRegistry,
+			ExtendedInfo:    resource.ExtendedInfo,
+			Deleted:         resource.Deleted,
+			Override:        policy.Override,
+			CopyConcurrency: policy.CopyConcurrency,
+		}
+		if policy.ReplicateMetadata.Enabled() {
+			res.ReplicateMetadata = policy.ReplicateMetadata
+		}
+		if policy.BandwidthLimit > 0 || len(policy.TimeWindows) > 0 {
+			res.Throttle = &model.Throttle{
+				PolicyID:       policy.ID,
+				BandwidthLimit: policy.BandwidthLimit,
+				TimeWindows:    policy.TimeWindows,
+			}
 		}
 		res.Metadata = &model.ResourceMetadata{
 			Repository: &model.Repository{
-				Name:     replaceNamespace(resource.Metadata.Repository.Name, policy.DestNamespace),
+				Name:     name,
 				Metadata: resource.Metadata.Repository.Metadata,
 			},
 			Vtags: resource.Metadata.Vtags,
@@ -213,7 +320,7 @@ func assembleDestinationResources(resources []*model.Resource,
 		result = append(result, res)
 	}
 	log.Debug("assemble the destination resources completed")
-	return result
+	return result, nil
 }
 
 // do the prepare work for pushing/uploading the resources: create the namespace or repository
@@ -251,6 +358,11 @@ func createTasks(mgr execution.Manager, executionID int64, items []*scheduler.Sc
 			DstResource:  getResourceName(item.DstResource),
 			Operation:    operation,
 		}
+		resources, err := marshalTaskResources(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the resources of the task for the execution %d: %v", executionID, err)
+		}
+		task.Resources = resources
 
 		id, err := mgr.CreateTask(task)
 		if err != nil {
@@ -356,3 +468,11 @@ func replaceNamespace(repository string, namespace string) string {
 	_, rest := util.ParseRepository(repository)
 	return fmt.Sprintf("%s/%s", namespace, rest)
 }
+
+// map the source repository to the destination one with the namespace mapping of the policy if it's set
+func mapRepository(repository string, policy *model.Policy) (string, error) {
+	if policy.NamespaceMapping == nil {
+		return replaceNamespace(repository, policy.DestNamespace), nil
+	}
+	return policy.NamespaceMapping.Map(repository, policy.DestNamespace)
+}
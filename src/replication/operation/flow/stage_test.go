@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/config"
 	"github.com/goharbor/harbor/src/replication/dao/models"
@@ -78,7 +79,22 @@ func (f *fakedAdapter) ManifestExist(repository, reference string) (exist bool,
 	return false, "", nil
 }
 func (f *fakedAdapter) PullManifest(repository, reference string, accepttedMediaTypes []string) (manifest distribution.Manifest, digest string, err error) {
-	return nil, "", nil
+	manifest, err = schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Size:      100,
+			Digest:    "sha256:c0ffee",
+		},
+		Layers: []distribution.Descriptor{
+			{
+				MediaType: schema2.MediaTypeLayer,
+				Size:      1000,
+				Digest:    "sha256:beef",
+			},
+		},
+	})
+	return manifest, "sha256:abc", err
 }
 func (f *fakedAdapter) PushManifest(repository, reference, mediaType string, payload []byte) error {
 	return nil
@@ -283,6 +299,160 @@ func TestFilterResources(t *testing.T) {
 	assert.Equal(t, "0.2.0", res[0].Metadata.Vtags[0])
 }
 
+func TestFilterResourcesWithExclusionAndRegex(t *testing.T) {
+	resources := []*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"v1.0.0", "v1.0.0-dev", "latest"},
+			},
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+				Vtags: []string{"v1.0.0"},
+			},
+		},
+	}
+	filters := []*model.Filter{
+		{
+			Type:  model.FilterTypeNameExclude,
+			Value: "library/busy*",
+		},
+		{
+			Type:  model.FilterTypeTagExclude,
+			Value: "*-dev",
+		},
+		{
+			Type:  model.FilterTypeTagRegex,
+			Value: `^v\d+\.\d+\.\d+`,
+		},
+		{
+			Type:  model.FilterTypePushedAfter,
+			Value: 7,
+		},
+	}
+	res, err := filterResources(resources, filters)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(res))
+	assert.Equal(t, "library/hello-world", res[0].Metadata.Repository.Name)
+	assert.Equal(t, []string{"v1.0.0"}, res[0].Metadata.Vtags)
+}
+
+func TestFilterResourcesWithLabels(t *testing.T) {
+	resources := []*model.Resource{
+		// just pushed, no labels carried
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags: []string{"latest"},
+			},
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+				Vtags:  []string{"latest"},
+				Labels: []string{"prod"},
+			},
+			Deleted: true,
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/alpine",
+				},
+				Vtags:  []string{"latest"},
+				Labels: []string{"prod", "deprecated"},
+			},
+			Deleted: true,
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/nginx",
+				},
+				Vtags:  []string{"latest"},
+				Labels: []string{},
+			},
+			Deleted: true,
+		},
+	}
+	filters := []*model.Filter{
+		{
+			Type:  model.FilterTypeLabel,
+			Value: []string{"prod"},
+		},
+		{
+			Type:  model.FilterTypeLabelExclude,
+			Value: []string{"deprecated"},
+		},
+	}
+	res, err := filterResources(resources, filters)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(res))
+	assert.Equal(t, "library/hello-world", res[0].Metadata.Repository.Name)
+	assert.Equal(t, "library/busybox", res[1].Metadata.Repository.Name)
+}
+
+func TestFilterResourcesWithDigest(t *testing.T) {
+	resources := []*model.Resource{
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Vtags:  []string{"latest"},
+				Digest: "sha256:c0ffee",
+			},
+		},
+		{
+			Type: model.ResourceTypeImage,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/busybox",
+				},
+				Vtags:  []string{"latest"},
+				Digest: "sha256:beef",
+			},
+		},
+		// the digest is unknown
+		{
+			Type: model.ResourceTypeChart,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/harbor",
+				},
+				Vtags: []string{"0.2.0"},
+			},
+		},
+	}
+	filters := []*model.Filter{
+		{
+			Type:  model.FilterTypeDigest,
+			Value: "sha256:c0ffee",
+		},
+	}
+	res, err := filterResources(resources, filters)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(res))
+	assert.Equal(t, "library/hello-world", res[0].Metadata.Repository.Name)
+}
+
 func TestAssembleSourceResources(t *testing.T) {
 	resources := []*model.Resource{
 		{
@@ -324,12 +494,23 @@ func TestAssembleDestinationResources(t *testing.T) {
 		DestNamespace: "test",
 		Override:      true,
 	}
-	res := assembleDestinationResources(resources, policy)
+	res, err := assembleDestinationResources(resources, policy)
+	require.Nil(t, err)
 	assert.Equal(t, 1, len(res))
 	assert.Equal(t, model.ResourceTypeChart, res[0].Type)
 	assert.Equal(t, "test/hello-world", res[0].Metadata.Repository.Name)
 	assert.Equal(t, 1, len(res[0].Metadata.Vtags))
 	assert.Equal(t, "latest", res[0].Metadata.Vtags[0])
+	assert.Nil(t, res[0].ReplicateMetadata)
+
+	policy.ReplicateMetadata = &model.MetadataReplication{
+		Labels: true,
+	}
+	res, err = assembleDestinationResources(resources, policy)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(res))
+	require.NotNil(t, res[0].ReplicateMetadata)
+	assert.True(t, res[0].ReplicateMetadata.Labels)
 }
 
 func TestPreprocess(t *testing.T) {
@@ -413,3 +594,28 @@ func TestReplaceNamespace(t *testing.T) {
 	result = replaceNamespace(repository, namespace)
 	assert.Equal(t, "n/c", result)
 }
+
+func TestMapRepository(t *testing.T) {
+	// without the namespace mapping
+	policy := &model.Policy{
+		DestNamespace: "n",
+	}
+	repository, err := mapRepository("a/b/c", policy)
+	require.Nil(t, err)
+	assert.Equal(t, "n/c", repository)
+
+	// with the namespace mapping
+	policy.NamespaceMapping = &model.NamespaceMapping{
+		ReplaceCount: 1,
+	}
+	repository, err = mapRepository("a/b/c", policy)
+	require.Nil(t, err)
+	assert.Equal(t, "n/b/c", repository)
+
+	// mapped to an invalid repository name
+	policy.NamespaceMapping = &model.NamespaceMapping{
+		Pattern: ".*",
+	}
+	_, err = mapRepository("a/b/c", policy)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,47 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"github.com/goharbor/harbor/src/replication/dao"
+	"github.com/goharbor/harbor/src/replication/dao/models"
+	"github.com/goharbor/harbor/src/replication/operation"
+)
+
+// finishExecution is used to persist the final status of the execution, it's replaced in testing
+var finishExecution = dao.FinishExecution
+
+// FinishExecution persists the final status of the execution which the task belongs to if all tasks
+// of the execution are finished, the execution is returned only to the caller which finishes it,
+// so the execution finished event is published once
+func FinishExecution(ctl operation.Controller, taskID int64) (*models.Execution, error) {
+	task, err := ctl.GetTask(taskID)
+	if err != nil || task == nil {
+		return nil, err
+	}
+	execution, err := ctl.GetExecution(task.ExecutionID)
+	if err != nil || execution == nil {
+		return nil, err
+	}
+	if execution.Status == models.ExecutionStatusInProgress {
+		return nil, nil
+	}
+
+	finished, err := finishExecution(execution)
+	if err != nil || !finished {
+		return nil, err
+	}
+	return execution, nil
+}
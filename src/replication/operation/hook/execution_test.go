@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hook
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/replication/dao/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakedExecutionController struct {
+	fakedOperationController
+	execution *models.Execution
+}
+
+func (f *fakedExecutionController) GetTask(id int64) (*models.Task, error) {
+	return &models.Task{ID: id, ExecutionID: f.execution.ID}, nil
+}
+func (f *fakedExecutionController) GetExecution(int64) (*models.Execution, error) {
+	return f.execution, nil
+}
+
+func TestFinishExecution(t *testing.T) {
+	finished := map[int64]bool{}
+	origin := finishExecution
+	finishExecution = func(execution *models.Execution) (bool, error) {
+		if finished[execution.ID] {
+			return false, nil
+		}
+		finished[execution.ID] = true
+		return true, nil
+	}
+	defer func() {
+		finishExecution = origin
+	}()
+
+	// in progress
+	ctl := &fakedExecutionController{
+		execution: &models.Execution{ID: 1, Status: models.ExecutionStatusInProgress},
+	}
+	execution, err := FinishExecution(ctl, 1)
+	require.Nil(t, err)
+	assert.Nil(t, execution)
+
+	// finished, only the first caller gets the execution
+	ctl.execution.Status = models.ExecutionStatusSucceed
+	execution, err = FinishExecution(ctl, 1)
+	require.Nil(t, err)
+	require.NotNil(t, execution)
+	assert.Equal(t, int64(1), execution.ID)
+
+	execution, err = FinishExecution(ctl, 2)
+	require.Nil(t, err)
+	assert.Nil(t, execution)
+}
@@ -34,6 +34,9 @@ func (f *fakedOperationController) StartReplication(*model.Policy, *model.Resour
 func (f *fakedOperationController) StopReplication(int64) error {
 	return nil
 }
+func (f *fakedOperationController) RetryReplication(policy *model.Policy, executionID int64) (int, error) {
+	return 1, nil
+}
 func (f *fakedOperationController) ListExecutions(...*models.ExecutionQuery) (int64, []*models.Execution, error) {
 	return 0, nil, nil
 }
@@ -53,6 +56,9 @@ func (f *fakedOperationController) UpdateTaskStatus(id int64, status string, sta
 func (f *fakedOperationController) GetTaskLog(int64) ([]byte, error) {
 	return nil, nil
 }
+func (f *fakedOperationController) PreviewReplication(policy *model.Policy, page, size int64) (int64, []*model.PreviewItem, error) {
+	return 0, nil, nil
+}
 
 func TestUpdateTask(t *testing.T) {
 	mgr := &fakedOperationController{}
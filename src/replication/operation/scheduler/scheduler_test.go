@@ -1,7 +1,9 @@
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"testing"
 
 	"github.com/goharbor/harbor/src/common/job/models"
@@ -22,6 +24,9 @@ func (client TestClient) SubmitJob(*models.JobData) (string, error) {
 func (client TestClient) GetJobLog(uuid string) ([]byte, error) {
 	return []byte("job log"), nil
 }
+func (client TestClient) FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error {
+	return nil
+}
 func (client TestClient) PostAction(uuid, action string) error {
 	return nil
 }
@@ -36,16 +36,18 @@ func convertFromPersistModel(policy *persist_models.RepPolicy) (*model.Policy, e
 	}
 
 	ply := model.Policy{
-		ID:            policy.ID,
-		Name:          policy.Name,
-		Description:   policy.Description,
-		Creator:       policy.Creator,
-		DestNamespace: policy.DestNamespace,
-		Deletion:      policy.ReplicateDeletion,
-		Override:      policy.Override,
-		Enabled:       policy.Enabled,
-		CreationTime:  policy.CreationTime,
-		UpdateTime:    policy.UpdateTime,
+		ID:              policy.ID,
+		Name:            policy.Name,
+		Description:     policy.Description,
+		Creator:         policy.Creator,
+		DestNamespace:   policy.DestNamespace,
+		Deletion:        policy.ReplicateDeletion,
+		Override:        policy.Override,
+		CopyConcurrency: policy.CopyConcurrency,
+		BandwidthLimit:  policy.BandwidthLimit,
+		Enabled:         policy.Enabled,
+		CreationTime:    policy.CreationTime,
+		UpdateTime:      policy.UpdateTime,
 	}
 	if policy.SrcRegistryID > 0 {
 		ply.SrcRegistry = &model.Registry{
@@ -72,6 +74,33 @@ func convertFromPersistModel(policy *persist_models.RepPolicy) (*model.Policy, e
 	}
 	ply.Trigger = trigger
 
+	// parse NamespaceMapping
+	if len(policy.NamespaceMapping) > 0 {
+		mapping := &model.NamespaceMapping{}
+		if err := json.Unmarshal([]byte(policy.NamespaceMapping), mapping); err != nil {
+			return nil, err
+		}
+		ply.NamespaceMapping = mapping
+	}
+
+	// parse TimeWindows
+	if len(policy.TimeWindows) > 0 {
+		windows := []*model.TimeWindow{}
+		if err := json.Unmarshal([]byte(policy.TimeWindows), &windows); err != nil {
+			return nil, err
+		}
+		ply.TimeWindows = windows
+	}
+
+	// parse ReplicateMetadata
+	if len(policy.ReplicateMetadata) > 0 {
+		metadata := &model.MetadataReplication{}
+		if err := json.Unmarshal([]byte(policy.ReplicateMetadata), metadata); err != nil {
+			return nil, err
+		}
+		ply.ReplicateMetadata = metadata
+	}
+
 	return &ply, nil
 }
 
@@ -87,6 +116,8 @@ func convertToPersistModel(policy *model.Policy) (*persist_models.RepPolicy, err
 		Creator:           policy.Creator,
 		DestNamespace:     policy.DestNamespace,
 		Override:          policy.Override,
+		CopyConcurrency:   policy.CopyConcurrency,
+		BandwidthLimit:    policy.BandwidthLimit,
 		Enabled:           policy.Enabled,
 		ReplicateDeletion: policy.Deletion,
 		CreationTime:      policy.CreationTime,
@@ -115,6 +146,30 @@ func convertToPersistModel(policy *model.Policy) (*persist_models.RepPolicy, err
 		ply.Filters = string(filters)
 	}
 
+	if policy.NamespaceMapping != nil {
+		mapping, err := json.Marshal(policy.NamespaceMapping)
+		if err != nil {
+			return nil, err
+		}
+		ply.NamespaceMapping = string(mapping)
+	}
+
+	if len(policy.TimeWindows) > 0 {
+		windows, err := json.Marshal(policy.TimeWindows)
+		if err != nil {
+			return nil, err
+		}
+		ply.TimeWindows = string(windows)
+	}
+
+	if policy.ReplicateMetadata != nil {
+		metadata, err := json.Marshal(policy.ReplicateMetadata)
+		if err != nil {
+			return nil, err
+		}
+		ply.ReplicateMetadata = string(metadata)
+	}
+
 	return ply, nil
 }
 
@@ -271,10 +326,10 @@ func parseFilters(str string) ([]*model.Filter, error) {
 		if filter.Type == model.FilterTypeResource {
 			filter.Value = (model.ResourceType)(filter.Value.(string))
 		}
-		if filter.Type == model.FilterTypeLabel {
-			labels := []string{}
-			for _, label := range filter.Value.([]interface{}) {
-				labels = append(labels, label.(string))
+		if filter.Type == model.FilterTypeLabel || filter.Type == model.FilterTypeLabelExclude {
+			labels, err := model.LabelsOf(filter.Value)
+			if err != nil {
+				return nil, err
 			}
 			filter.Value = labels
 		}
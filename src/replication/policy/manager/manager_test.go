@@ -61,6 +61,9 @@ func Test_convertFromPersistModel(t *testing.T) {
 				Enabled:           true,
 				Trigger:           "",
 				Filters:           "[]",
+				BandwidthLimit:    1024,
+				TimeWindows:       `[{"start":"20:00","end":"06:00"}]`,
+				ReplicateMetadata: `{"labels":true,"scan_reports":false}`,
 			}, want: &model.Policy{
 				ID:          999,
 				Name:        "Policy Test",
@@ -72,12 +75,22 @@ func Test_convertFromPersistModel(t *testing.T) {
 				DestRegistry: &model.Registry{
 					ID: 456,
 				},
-				DestNamespace: "target_ns",
-				Deletion:      true,
-				Override:      true,
-				Enabled:       true,
-				Trigger:       nil,
-				Filters:       []*model.Filter{},
+				DestNamespace:  "target_ns",
+				Deletion:       true,
+				Override:       true,
+				Enabled:        true,
+				Trigger:        nil,
+				Filters:        []*model.Filter{},
+				BandwidthLimit: 1024,
+				TimeWindows: []*model.TimeWindow{
+					{
+						Start: "20:00",
+						End:   "06:00",
+					},
+				},
+				ReplicateMetadata: &model.MetadataReplication{
+					Labels: true,
+				},
 			},
 		},
 	}
@@ -108,6 +121,9 @@ func Test_convertFromPersistModel(t *testing.T) {
 			assert.Equal(t, tt.want.Enabled, got.Enabled)
 			assert.Equal(t, tt.want.Trigger, got.Trigger)
 			assert.Equal(t, tt.want.Filters, got.Filters)
+			assert.Equal(t, tt.want.BandwidthLimit, got.BandwidthLimit)
+			assert.Equal(t, tt.want.TimeWindows, got.TimeWindows)
+			assert.Equal(t, tt.want.ReplicateMetadata, got.ReplicateMetadata)
 
 		})
 	}
@@ -139,6 +155,9 @@ func Test_convertToPersistModel(t *testing.T) {
 				Enabled:       true,
 				Trigger:       &model.Trigger{},
 				Filters:       []*model.Filter{{Type: "registry", Value: "abc"}},
+				ReplicateMetadata: &model.MetadataReplication{
+					ScanReports: true,
+				},
 			}, want: &persist_models.RepPolicy{
 				ID:                999,
 				Name:              "Policy Test",
@@ -152,6 +171,7 @@ func Test_convertToPersistModel(t *testing.T) {
 				Enabled:           true,
 				Trigger:           "{\"type\":\"\",\"trigger_settings\":null}",
 				Filters:           "[{\"type\":\"registry\",\"value\":\"abc\"}]",
+				ReplicateMetadata: "{\"labels\":false,\"scan_reports\":true}",
 			},
 		},
 	}
@@ -177,6 +197,7 @@ func Test_convertToPersistModel(t *testing.T) {
 			assert.Equal(t, tt.want.Enabled, got.Enabled)
 			assert.Equal(t, tt.want.Trigger, got.Trigger)
 			assert.Equal(t, tt.want.Filters, got.Filters)
+			assert.Equal(t, tt.want.ReplicateMetadata, got.ReplicateMetadata)
 
 		})
 	}
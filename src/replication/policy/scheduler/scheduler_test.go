@@ -15,7 +15,9 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/goharbor/harbor/src/common/job/models"
@@ -48,6 +50,9 @@ func (f *fakedJobserviceClient) GetJobLog(uuid string) ([]byte, error) {
 	f.stopped = true
 	return nil, nil
 }
+func (f *fakedJobserviceClient) FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error {
+	return nil
+}
 func (f *fakedJobserviceClient) PostAction(uuid, action string) error {
 	f.stopped = true
 	return nil
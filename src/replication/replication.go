@@ -51,6 +51,12 @@ import (
 	_ "github.com/goharbor/harbor/src/replication/adapter/helmhub"
 	// register the GitLab adapter
 	_ "github.com/goharbor/harbor/src/replication/adapter/gitlab"
+	// register the OCI image layout adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/ocilayout"
+	// register the Nexus adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/nexus"
+	// register the Tencent TCR adapter
+	_ "github.com/goharbor/harbor/src/replication/adapter/tencentcr"
 )
 
 var (
@@ -16,8 +16,10 @@ package chart
 
 import (
 	"errors"
+	"io"
 
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/model"
 	trans "github.com/goharbor/harbor/src/replication/transfer"
@@ -158,10 +160,12 @@ func (t *transfer) copy(src, dst *chart, override bool) error {
 	}
 	defer chart.Close()
 
-	if err = t.dst.UploadChart(dst.name, dst.version, chart); err != nil {
+	reader := &countingReader{Reader: chart}
+	if err = t.dst.UploadChart(dst.name, dst.version, reader); err != nil {
 		t.logger.Errorf("failed to upload the chart %s:%s: %v", dst.name, dst.version, err)
 		return err
 	}
+	metrics.ReplicationTransferredBytesTotal.WithLabelValues(string(model.ResourceTypeChart)).Add(float64(reader.count))
 
 	t.logger.Infof("copy %s:%s(source registry) to %s:%s(destination registry) completed",
 		src.name, src.version, dst.name, dst.version)
@@ -189,3 +193,15 @@ func (t *transfer) delete(chart *chart) error {
 	t.logger.Infof("delete the chart %s:%s on the destination registry completed", chart.name, chart.version)
 	return nil
 }
+
+// countingReader counts the bytes read from the chart
+type countingReader struct {
+	io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
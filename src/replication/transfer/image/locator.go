@@ -0,0 +1,84 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"container/list"
+	"sync"
+)
+
+// the max number of the blobs whose repositories are recorded
+const maxLocatedBlobs = 10000
+
+// locator records the repositories the blobs are pushed to on the destination registries,
+// so the same blobs replicated to other repositories later can be mounted rather than uploaded.
+// It's shared by all the replication jobs running in the same process
+var locator = newBlobLocator(maxLocatedBlobs)
+
+type blobLocation struct {
+	key        string
+	repository string
+}
+
+// blobLocator is a LRU cache of the repositories keyed by the registry and the digest of the blobs
+type blobLocator struct {
+	sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func newBlobLocator(capacity int) *blobLocator {
+	return &blobLocator{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// locate returns the repository of the registry where the blob exists
+func (b *blobLocator) locate(registry, digest string) (string, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	elem, ok := b.entries[registry+"@"+digest]
+	if !ok {
+		return "", false
+	}
+	b.order.MoveToFront(elem)
+	return elem.Value.(*blobLocation).repository, true
+}
+
+// record that the blob exists in the repository of the registry
+func (b *blobLocator) record(registry, digest, repository string) {
+	b.Lock()
+	defer b.Unlock()
+
+	key := registry + "@" + digest
+	if elem, ok := b.entries[key]; ok {
+		elem.Value.(*blobLocation).repository = repository
+		b.order.MoveToFront(elem)
+		return
+	}
+	b.entries[key] = b.order.PushFront(&blobLocation{
+		key:        key,
+		repository: repository,
+	})
+	if b.order.Len() > b.capacity {
+		oldest := b.order.Back()
+		b.order.Remove(oldest)
+		delete(b.entries, oldest.Value.(*blobLocation).key)
+	}
+}
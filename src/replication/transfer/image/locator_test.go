@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobLocator(t *testing.T) {
+	l := newBlobLocator(2)
+
+	_, ok := l.locate("https://registry", "sha256:1")
+	assert.False(t, ok)
+
+	l.record("https://registry", "sha256:1", "library/a")
+	l.record("https://registry", "sha256:2", "library/b")
+	repository, ok := l.locate("https://registry", "sha256:1")
+	assert.True(t, ok)
+	assert.Equal(t, "library/a", repository)
+
+	// the same digest on another registry
+	_, ok = l.locate("https://another", "sha256:1")
+	assert.False(t, ok)
+
+	// the least recently used one is evicted
+	l.record("https://registry", "sha256:3", "library/c")
+	_, ok = l.locate("https://registry", "sha256:2")
+	assert.False(t, ok)
+	_, ok = l.locate("https://registry", "sha256:1")
+	assert.True(t, ok)
+
+	// update the repository
+	l.record("https://registry", "sha256:1", "library/d")
+	repository, _ = l.locate("https://registry", "sha256:1")
+	assert.Equal(t, "library/d", repository)
+}
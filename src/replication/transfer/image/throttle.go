@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// the max size of the data read from the blob stream at one time when the bandwidth is limited,
+// it's also the burst of the limiters
+const maxReadSize = 32 * 1024
+
+// the bandwidth limiters of the policies, they're shared by the tasks of the same policy
+// running in the same process so that the bandwidth used by the policy is limited as a whole
+var limiters = &bandwidthLimiters{
+	limiters: map[int64]*sharedLimiter{},
+}
+
+type bandwidthLimiters struct {
+	sync.Mutex
+	limiters map[int64]*sharedLimiter
+}
+
+// sharedLimiter is the limiter referenced by the running tasks of one policy
+type sharedLimiter struct {
+	*rate.Limiter
+	refs int
+}
+
+// acquire the limiter of the policy, the limit is updated if the limit of the policy changes.
+// The limiter must be released when the transfer completes
+func (b *bandwidthLimiters) acquire(policyID, limit int64) *rate.Limiter {
+	b.Lock()
+	defer b.Unlock()
+
+	limiter, ok := b.limiters[policyID]
+	if !ok {
+		limiter = &sharedLimiter{
+			Limiter: rate.NewLimiter(rate.Limit(limit), maxReadSize),
+		}
+		b.limiters[policyID] = limiter
+	} else {
+		limiter.SetLimit(rate.Limit(limit))
+	}
+	limiter.refs++
+	return limiter.Limiter
+}
+
+// release the limiter of the policy, it's removed once no running tasks reference it
+func (b *bandwidthLimiters) release(policyID int64) {
+	b.Lock()
+	defer b.Unlock()
+
+	limiter, ok := b.limiters[policyID]
+	if !ok {
+		return
+	}
+	limiter.refs--
+	if limiter.refs <= 0 {
+		delete(b.limiters, policyID)
+	}
+}
+
+// limitedReadCloser limits the speed of reading the underlying stream
+type limitedReadCloser struct {
+	io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	if len(p) > maxReadSize {
+		p = p[:maxReadSize]
+	}
+	n, err := l.ReadCloser.Read(p)
+	if n > 0 {
+		// never fails as the n isn't larger than the burst and the context has no deadline
+		_ = l.limiter.WaitN(context.Background(), n)
+	}
+	return n, err
+}
@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/replication/model"
+	trans "github.com/goharbor/harbor/src/replication/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestLimitedReadCloser(t *testing.T) {
+	data := bytes.Repeat([]byte{'a'}, 3*maxReadSize)
+	reader := &limitedReadCloser{
+		ReadCloser: ioutil.NopCloser(bytes.NewReader(data)),
+		// the burst is available at the beginning, the left data takes half a second
+		limiter: rate.NewLimiter(rate.Limit(4*maxReadSize), maxReadSize),
+	}
+	start := time.Now()
+	result, err := ioutil.ReadAll(reader)
+	require.Nil(t, err)
+	assert.Equal(t, data, result)
+	assert.True(t, time.Since(start) > 400*time.Millisecond)
+}
+
+func TestBandwidthLimiters(t *testing.T) {
+	l1 := limiters.acquire(1, 100)
+	l2 := limiters.acquire(1, 200)
+	assert.True(t, l1 == l2)
+	assert.Equal(t, rate.Limit(200), l2.Limit())
+
+	l3 := limiters.acquire(2, 100)
+	assert.False(t, l1 == l3)
+	limiters.release(2)
+	_, exist := limiters.limiters[2]
+	assert.False(t, exist)
+
+	// removed when all the tasks release it
+	limiters.release(1)
+	assert.True(t, l1 == limiters.acquire(1, 100))
+	limiters.release(1)
+	limiters.release(1)
+	_, exist = limiters.limiters[1]
+	assert.False(t, exist)
+}
+
+func TestInTimeWindows(t *testing.T) {
+	now := time.Now().UTC()
+	in := &model.TimeWindow{
+		Start: now.Add(-time.Hour).Format("15:04"),
+		End:   now.Add(time.Hour).Format("15:04"),
+	}
+	out := &model.TimeWindow{
+		Start: now.Add(2 * time.Hour).Format("15:04"),
+		End:   now.Add(3 * time.Hour).Format("15:04"),
+	}
+
+	// no time windows
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+	}
+	assert.True(t, tr.inTimeWindows())
+
+	// in the time windows
+	tr.throttle = &model.Throttle{
+		TimeWindows: []*model.TimeWindow{in},
+	}
+	assert.True(t, tr.inTimeWindows())
+
+	// out of the time windows, the replication stops before copying the next image
+	tr.throttle = &model.Throttle{
+		TimeWindows: []*model.TimeWindow{out},
+	}
+	assert.False(t, tr.inTimeWindows())
+	err := tr.copy(&repository{
+		repository: "library/hello-world",
+		tags:       []string{"latest"},
+	}, &repository{
+		repository: "library/hello-world",
+		tags:       []string{"latest"},
+	}, true)
+	assert.Equal(t, trans.ErrOutOfTimeWindows, err)
+}
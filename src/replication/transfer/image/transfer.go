@@ -20,6 +20,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/manifest/manifestlist"
@@ -28,9 +29,13 @@ import (
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common/utils/log"
+	"github.com/goharbor/harbor/src/common/utils/registry"
+	"github.com/goharbor/harbor/src/pkg/metrics"
 	"github.com/goharbor/harbor/src/replication/adapter"
 	"github.com/goharbor/harbor/src/replication/model"
 	trans "github.com/goharbor/harbor/src/replication/transfer"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -64,13 +69,37 @@ type transfer struct {
 	isStopped trans.StopFunc
 	src       adapter.ImageRegistry
 	dst       adapter.ImageRegistry
+	// the URL of the destination registry, used to locate the blobs which can be mounted
+	dstURL string
+	// the number of the layers of one image copied concurrently
+	concurrency int
+	// the bandwidth limit and time windows of the policy
+	throttle *model.Throttle
+	limiter  *rate.Limiter
+	// the metadata replicated along with the images
+	metadata *model.MetadataReplication
 }
 
-func (t *transfer) Transfer(src *model.Resource, dst *model.Resource) error {
+func (t *transfer) Transfer(src *model.Resource, dst *model.Resource) (err error) {
 	// initialize
 	if err := t.initialize(src, dst); err != nil {
 		return err
 	}
+	if t.limiter != nil {
+		defer limiters.release(t.throttle.PolicyID)
+	}
+	// flush the content buffered by the destination registry even if the transfer fails,
+	// so the images copied are kept
+	if flusher, ok := t.dst.(adapter.Flusher); ok {
+		defer func() {
+			if e := flusher.Flush(); e != nil {
+				t.logger.Errorf("failed to flush the content to the destination registry: %v", e)
+				if err == nil {
+					err = e
+				}
+			}
+		}()
+	}
 
 	// delete the repository on destination registry
 	if dst.Deleted {
@@ -113,6 +142,14 @@ func (t *transfer) initialize(src *model.Resource, dst *model.Resource) error {
 		return err
 	}
 	t.dst = dstReg
+	t.dstURL = dst.Registry.URL
+	t.concurrency = dst.CopyConcurrency
+	t.throttle = dst.Throttle
+	t.metadata = dst.ReplicateMetadata
+	if t.throttle != nil && t.throttle.BandwidthLimit > 0 {
+		t.limiter = limiters.acquire(t.throttle.PolicyID, t.throttle.BandwidthLimit)
+		t.logger.Infof("the bandwidth is limited to %d bytes per second", t.throttle.BandwidthLimit)
+	}
 	t.logger.Infof("client for destination registry [type: %s, URL: %s, insecure: %v] created",
 		dst.Registry.Type, dst.Registry.URL, dst.Registry.Insecure)
 
@@ -135,6 +172,20 @@ func createRegistry(reg *model.Registry) (adapter.ImageRegistry, error) {
 	return registry, nil
 }
 
+// inTimeWindows returns whether the current time is in the time windows of the policy, the
+// replication stops before copying the next image or blob once it's out of the time windows
+// and the blobs being copied aren't interrupted
+func (t *transfer) inTimeWindows() bool {
+	if t.throttle == nil {
+		return true
+	}
+	if !model.InTimeWindows(t.throttle.TimeWindows, time.Now()) {
+		t.logger.Info("out of the time windows of the policy, the replication is stopped")
+		return false
+	}
+	return true
+}
+
 func (t *transfer) shouldStop() bool {
 	isStopped := t.isStopped()
 	if isStopped {
@@ -150,7 +201,20 @@ func (t *transfer) copy(src *repository, dst *repository, override bool) error {
 		srcRepo, strings.Join(src.tags, ","), dstRepo, strings.Join(dst.tags, ","))
 	var err error
 	for i := range src.tags {
-		if e := t.copyImage(srcRepo, src.tags[i], dstRepo, dst.tags[i], override); e != nil {
+		synced, e := t.copyImage(srcRepo, src.tags[i], dstRepo, dst.tags[i], override)
+		if e == trans.ErrOutOfTimeWindows {
+			return e
+		}
+		if e != nil {
+			t.logger.Errorf(e.Error())
+			err = e
+			continue
+		}
+		// the metadata mustn't be attached to a different image which isn't overridden
+		if !synced {
+			continue
+		}
+		if e := t.copyMetadata(srcRepo, src.tags[i], dstRepo, dst.tags[i]); e != nil {
 			t.logger.Errorf(e.Error())
 			err = e
 		}
@@ -164,32 +228,37 @@ func (t *transfer) copy(src *repository, dst *repository, override bool) error {
 	return nil
 }
 
-func (t *transfer) copyImage(srcRepo, srcRef, dstRepo, dstRef string, override bool) error {
+// copyImage copies the image and returns whether the destination reference refers to the
+// same image as the source one after copying
+func (t *transfer) copyImage(srcRepo, srcRef, dstRepo, dstRef string, override bool) (bool, error) {
 	t.logger.Infof("copying %s:%s(source registry) to %s:%s(destination registry)...",
 		srcRepo, srcRef, dstRepo, dstRef)
+	if !t.inTimeWindows() {
+		return false, trans.ErrOutOfTimeWindows
+	}
 	// pull the manifest from the source registry
 	manifest, digest, err := t.pullManifest(srcRepo, srcRef)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// check the existence of the image on the destination registry
 	exist, digest2, err := t.exist(dstRepo, dstRef)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if exist {
 		// the same image already exists
 		if digest == digest2 {
 			t.logger.Infof("the image %s:%s already exists on the destination registry, skip",
 				dstRepo, dstRef)
-			return nil
+			return true, nil
 		}
 		// the same name image exists, but not allowed to override
 		if !override {
 			t.logger.Warningf("the same name image %s:%s exists on the destination registry, but the \"override\" is set to false, skip",
 				dstRepo, dstRef)
-			return nil
+			return false, nil
 		}
 		// the same name image exists, but allowed to override
 		t.logger.Warningf("the same name image %s:%s exists on the destination registry and the \"override\" is set to true, continue...",
@@ -197,31 +266,93 @@ func (t *transfer) copyImage(srcRepo, srcRef, dstRepo, dstRef string, override b
 	}
 
 	// copy contents between the source and destination registries
-	for _, content := range manifest.References() {
-		if err = t.copyContent(content, srcRepo, dstRepo); err != nil {
-			return err
-		}
+	if err = t.copyContents(manifest.References(), srcRepo, dstRepo); err != nil {
+		return false, err
 	}
 
 	// push the manifest to the destination registry
 	if err := t.pushManifest(manifest, dstRepo, dstRef); err != nil {
-		return err
+		return false, err
 	}
 
 	t.logger.Infof("copy %s:%s(source registry) to %s:%s(destination registry) completed",
 		srcRepo, srcRef, dstRepo, dstRef)
+	return true, nil
+}
+
+// copy the labels and scan reports of the image if the policy replicates them, both the source
+// and destination registries must implement the "ArtifactMetadataRegistry" interface
+func (t *transfer) copyMetadata(srcRepo, srcRef, dstRepo, dstRef string) error {
+	if !t.metadata.Enabled() || t.isStopped() {
+		return nil
+	}
+	src, ok := t.src.(adapter.ArtifactMetadataRegistry)
+	if !ok {
+		t.logger.Warning("the source registry doesn't support replicating the metadata of images, skip")
+		return nil
+	}
+	dst, ok := t.dst.(adapter.ArtifactMetadataRegistry)
+	if !ok {
+		t.logger.Warning("the destination registry doesn't support replicating the metadata of images, skip")
+		return nil
+	}
+	metadata, err := src.PullArtifactMetadata(srcRepo, srcRef, t.metadata)
+	if err != nil {
+		return fmt.Errorf("failed to pull the metadata of %s:%s from the source registry: %v", srcRepo, srcRef, err)
+	}
+	if err = dst.PushArtifactMetadata(dstRepo, dstRef, metadata); err != nil {
+		return fmt.Errorf("failed to push the metadata of %s:%s to the destination registry: %v", dstRepo, dstRef, err)
+	}
+	t.logger.Infof("copy the metadata of %s:%s(source registry) to %s:%s(destination registry) completed: %d labels, %d scan reports",
+		srcRepo, srcRef, dstRepo, dstRef, len(metadata.Labels), len(metadata.ScanReports))
 	return nil
 }
 
+// copy the contents referenced by one manifest, they're copied concurrently if the concurrency is set
+func (t *transfer) copyContents(contents []distribution.Descriptor, srcRepo, dstRepo string) error {
+	if t.concurrency <= 1 {
+		for _, content := range contents {
+			if err := t.copyContent(content, srcRepo, dstRepo); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		tokens   = make(chan struct{}, t.concurrency)
+	)
+	for _, content := range contents {
+		tokens <- struct{}{}
+		wg.Add(1)
+		go func(content distribution.Descriptor) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			if err := t.copyContent(content, srcRepo, dstRepo); err != nil {
+				errOnce.Do(func() { firstErr = err })
+			}
+		}(content)
+	}
+	wg.Wait()
+	return firstErr
+}
+
 // copy the content from source registry to destination according to its media type
 func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo string) error {
 	digest := content.Digest.String()
 	switch content.MediaType {
-	// when the media type of pulled manifest is manifest list,
-	// the contents it contains are a few manifests
-	case schema2.MediaTypeManifest:
+	// when the media type of pulled manifest is manifest list or OCI image index,
+	// the contents it contains are a few manifests or nested lists
+	case schema2.MediaTypeManifest, v1.MediaTypeImageManifest,
+		manifestlist.MediaTypeManifestList, v1.MediaTypeImageIndex:
 		// as using digest as the reference, so set the override to true directly
-		return t.copyImage(srcRepo, digest, dstRepo, digest, true)
+		_, err := t.copyImage(srcRepo, digest, dstRepo, digest, true)
+		return err
 	// handle foreign layer
 	case schema2.MediaTypeForeignLayer:
 		t.logger.Infof("the layer %s is a foreign layer, skip", digest)
@@ -242,6 +373,9 @@ func (t *transfer) copyBlobWithRetry(srcRepo, dstRepo, digest string) error {
 			t.logger.Infof("copy the blob %s completed", digest)
 			return nil
 		}
+		if err == trans.ErrOutOfTimeWindows {
+			return err
+		}
 		t.logger.Errorf("failed to copy the blob %s: %v", digest, err)
 		if i == retry {
 			break
@@ -257,6 +391,9 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string) error {
 	if t.shouldStop() {
 		return nil
 	}
+	if !t.inTimeWindows() {
+		return trans.ErrOutOfTimeWindows
+	}
 	exist, err := t.dst.BlobExist(dstRepo, digest)
 	if err != nil {
 		t.logger.Errorf("failed to check the existence of blob %s on the destination registry: %v", digest, err)
@@ -264,6 +401,12 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string) error {
 	}
 	if exist {
 		t.logger.Infof("the blob %s already exists on the destination registry, skip", digest)
+		locator.record(t.dstURL, digest, dstRepo)
+		return nil
+	}
+
+	if t.mountBlob(dstRepo, digest) {
+		locator.record(t.dstURL, digest, dstRepo)
 		return nil
 	}
 
@@ -272,15 +415,48 @@ func (t *transfer) copyBlob(srcRepo, dstRepo, digest string) error {
 		t.logger.Errorf("failed to pulling the blob %s: %v", digest, err)
 		return err
 	}
+	// the data pulled is pushed as it's read, so limiting the reading limits both
+	if t.limiter != nil {
+		data = &limitedReadCloser{
+			ReadCloser: data,
+			limiter:    t.limiter,
+		}
+	}
 	defer data.Close()
 
 	if err = t.dst.PushBlob(dstRepo, digest, size, data); err != nil {
 		t.logger.Errorf("failed to pushing the blob %s, size %d: %v", digest, size, err)
 		return err
 	}
+	metrics.ReplicationTransferredBytesTotal.WithLabelValues(string(model.ResourceTypeImage)).Add(float64(size))
+	locator.record(t.dstURL, digest, dstRepo)
 	return nil
 }
 
+// mountBlob tries to mount the blob from the repository where it was replicated to before,
+// the blob is uploaded as usual if it cannot be mounted
+func (t *transfer) mountBlob(dstRepo, digest string) bool {
+	mounter, ok := t.dst.(adapter.BlobMounter)
+	if !ok {
+		return false
+	}
+	from, ok := locator.locate(t.dstURL, digest)
+	if !ok || from == dstRepo {
+		return false
+	}
+	mounted, err := mounter.MountBlob(dstRepo, digest, from)
+	if err != nil {
+		t.logger.Warningf("failed to mount the blob %s from %s on the destination registry, upload it: %v", digest, from, err)
+		return false
+	}
+	if !mounted {
+		t.logger.Infof("the blob %s cannot be mounted from %s on the destination registry, upload it", digest, from)
+		return false
+	}
+	t.logger.Infof("the blob %s is mounted from %s on the destination registry", digest, from)
+	return true
+}
+
 func (t *transfer) pullManifest(repository, reference string) (
 	distribution.Manifest, string, error) {
 	if t.shouldStop() {
@@ -291,7 +467,9 @@ func (t *transfer) pullManifest(repository, reference string) (
 		schema1.MediaTypeManifest,
 		schema1.MediaTypeSignedManifest,
 		schema2.MediaTypeManifest,
+		v1.MediaTypeImageManifest,
 		manifestlist.MediaTypeManifestList,
+		v1.MediaTypeImageIndex,
 	})
 	if err != nil {
 		t.logger.Errorf("failed to pull the manifest of image %s:%s: %v", repository, reference, err)
@@ -303,8 +481,9 @@ func (t *transfer) pullManifest(repository, reference string) (
 	return t.handleManifest(manifest, repository, digest)
 }
 
-// if the media type of the specified manifest is manifest list, just abstract one
-// manifest from the list and return it
+// if the media type of the specified manifest is manifest list or OCI image index, just
+// abstract one manifest from the list and return it unless the destination registry keeps
+// the manifest lists
 func (t *transfer) handleManifest(manifest distribution.Manifest, repository, digest string) (
 	distribution.Manifest, string, error) {
 	mediaType, _, err := manifest.Payload()
@@ -313,12 +492,14 @@ func (t *transfer) handleManifest(manifest distribution.Manifest, repository, di
 		return nil, "", err
 	}
 	// manifest
-	if mediaType == schema1.MediaTypeManifest ||
-		mediaType == schema1.MediaTypeSignedManifest ||
-		mediaType == schema2.MediaTypeManifest {
+	if !registry.IsManifestList(mediaType) {
 		return manifest, digest, nil
 	}
 	// manifest list
+	if KeepManifestList(t.dst) {
+		t.logger.Info("the manifest list is copied as the destination registry keeps the manifest lists")
+		return manifest, digest, nil
+	}
 	t.logger.Info("trying abstract a manifest from the manifest list...")
 	manifestlist, ok := manifest.(*manifestlist.DeserializedManifestList)
 	if !ok {
@@ -326,22 +507,37 @@ func (t *transfer) handleManifest(manifest distribution.Manifest, repository, di
 		t.logger.Errorf(err.Error())
 		return nil, "", err
 	}
-	digest = ""
-	for _, reference := range manifestlist.Manifests {
-		if strings.ToLower(reference.Platform.Architecture) == "amd64" &&
-			strings.ToLower(reference.Platform.OS) == "linux" {
-			digest = reference.Digest.String()
-			t.logger.Infof("a manifest(architecture: amd64, os: linux) found, using this one: %s", digest)
-			break
-		}
-	}
-	if len(digest) == 0 {
-		digest = manifest.References()[0].Digest.String()
-		t.logger.Infof("no manifest(architecture: amd64, os: linux) found, using the first one: %s", digest)
+	digest, err = SelectManifest(manifestlist)
+	if err != nil {
+		t.logger.Errorf(err.Error())
+		return nil, "", err
 	}
+	t.logger.Infof("the manifest %s is abstracted from the manifest list", digest)
 	return t.pullManifest(repository, digest)
 }
 
+// KeepManifestList returns whether the manifest lists are copied to the destination registry as they are,
+// otherwise the manifest returned by SelectManifest is copied instead
+func KeepManifestList(dst adapter.ImageRegistry) bool {
+	keeper, ok := dst.(adapter.ManifestListKeeper)
+	return ok && keeper.KeepManifestLists()
+}
+
+// SelectManifest returns the digest of the manifest in the manifest list that the transfer copies:
+// the one of linux/amd64 or the first one if there is no such one
+func SelectManifest(list *manifestlist.DeserializedManifestList) (string, error) {
+	if len(list.Manifests) == 0 {
+		return "", errors.New("the manifest list is empty")
+	}
+	for _, m := range list.Manifests {
+		if strings.ToLower(m.Platform.Architecture) == "amd64" &&
+			strings.ToLower(m.Platform.OS) == "linux" {
+			return m.Digest.String(), nil
+		}
+	}
+	return list.Manifests[0].Digest.String(), nil
+}
+
 func (t *transfer) exist(repository, tag string) (bool, string, error) {
 	exist, digest, err := t.dst.ManifestExist(repository, tag)
 	if err != nil {
@@ -18,14 +18,17 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"sync"
 	"testing"
 
 	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/goharbor/harbor/src/common/utils/log"
 	pkg_registry "github.com/goharbor/harbor/src/common/utils/registry"
 	"github.com/goharbor/harbor/src/replication/model"
 	trans "github.com/goharbor/harbor/src/replication/transfer"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +43,9 @@ func (f *fakeRegistry) ManifestExist(repository, reference string) (bool, string
 	if repository == "destination" && reference == "b1" {
 		return true, "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7", nil
 	}
+	if repository == "destination" && reference == "b3" {
+		return true, "sha256:d6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7", nil
+	}
 	return false, "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7", nil
 }
 func (f *fakeRegistry) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
@@ -140,6 +146,288 @@ func TestCopy(t *testing.T) {
 	require.Nil(t, err)
 }
 
+type fakeMounter struct {
+	fakeRegistry
+	sync.Mutex
+	mounted []string
+	pushed  []string
+}
+
+func (f *fakeMounter) MountBlob(repository, digest, from string) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.mounted = append(f.mounted, digest)
+	return true, nil
+}
+
+func (f *fakeMounter) PushBlob(repository, digest string, size int64, blob io.Reader) error {
+	f.Lock()
+	defer f.Unlock()
+	f.pushed = append(f.pushed, digest)
+	return nil
+}
+
+func TestCopyWithMountAndConcurrency(t *testing.T) {
+	locator = newBlobLocator(maxLocatedBlobs)
+	defer func() {
+		locator = newBlobLocator(maxLocatedBlobs)
+	}()
+
+	dst := &fakeMounter{}
+	tr := &transfer{
+		logger:      log.DefaultLogger(),
+		isStopped:   func() bool { return false },
+		src:         &fakeRegistry{},
+		dst:         dst,
+		dstURL:      "https://destination",
+		concurrency: 2,
+	}
+
+	// the first repository: all the blobs are uploaded
+	synced, err := tr.copyImage("source", "a1", "destination1", "b2", true)
+	require.Nil(t, err)
+	assert.True(t, synced)
+	assert.Equal(t, 4, len(dst.pushed))
+	assert.Equal(t, 0, len(dst.mounted))
+
+	// the second repository: all the blobs are mounted from the first one
+	synced, err = tr.copyImage("source", "a1", "destination2", "b2", true)
+	require.Nil(t, err)
+	assert.True(t, synced)
+	assert.Equal(t, 4, len(dst.pushed))
+	assert.Equal(t, 4, len(dst.mounted))
+}
+
+type fakeMetadataRegistry struct {
+	fakeRegistry
+	pushed map[string]*model.ArtifactMetadata
+}
+
+func (f *fakeMetadataRegistry) PullArtifactMetadata(repository, tag string, replication *model.MetadataReplication) (*model.ArtifactMetadata, error) {
+	metadata := &model.ArtifactMetadata{}
+	if replication.Labels {
+		metadata.Labels = []*model.Label{
+			{
+				Name:  tag,
+				Scope: "g",
+			},
+		}
+	}
+	return metadata, nil
+}
+
+func (f *fakeMetadataRegistry) PushArtifactMetadata(repository, tag string, metadata *model.ArtifactMetadata) error {
+	if f.pushed == nil {
+		f.pushed = map[string]*model.ArtifactMetadata{}
+	}
+	f.pushed[repository+":"+tag] = metadata
+	return nil
+}
+
+func TestCopyMetadata(t *testing.T) {
+	dst := &fakeMetadataRegistry{}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+		src:       &fakeMetadataRegistry{},
+		dst:       dst,
+		metadata: &model.MetadataReplication{
+			Labels: true,
+		},
+	}
+	src := &repository{
+		repository: "source",
+		tags:       []string{"a1"},
+	}
+	err := tr.copy(src, &repository{
+		repository: "destination",
+		tags:       []string{"b1"},
+	}, true)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(dst.pushed))
+	require.Equal(t, 1, len(dst.pushed["destination:b1"].Labels))
+	assert.Equal(t, "a1", dst.pushed["destination:b1"].Labels[0].Name)
+
+	// the source registry doesn't support replicating metadata
+	dst.pushed = nil
+	tr.src = &fakeRegistry{}
+	err = tr.copy(src, &repository{
+		repository: "destination",
+		tags:       []string{"b1"},
+	}, true)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(dst.pushed))
+
+	// a different image exists on the destination registry and isn't overridden
+	tr.src = &fakeMetadataRegistry{}
+	err = tr.copy(src, &repository{
+		repository: "destination",
+		tags:       []string{"b3"},
+	}, false)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(dst.pushed))
+
+	// the image is overridden
+	err = tr.copy(src, &repository{
+		repository: "destination",
+		tags:       []string{"b3"},
+	}, true)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(dst.pushed))
+}
+
+func TestSelectManifest(t *testing.T) {
+	_, err := SelectManifest(&manifestlist.DeserializedManifestList{})
+	assert.NotNil(t, err)
+
+	list := &manifestlist.DeserializedManifestList{
+		ManifestList: manifestlist.ManifestList{
+			Manifests: []manifestlist.ManifestDescriptor{
+				{
+					Descriptor: distribution.Descriptor{Digest: "sha256:arm64"},
+					Platform:   manifestlist.PlatformSpec{Architecture: "arm64", OS: "linux"},
+				},
+				{
+					Descriptor: distribution.Descriptor{Digest: "sha256:amd64"},
+					Platform:   manifestlist.PlatformSpec{Architecture: "amd64", OS: "linux"},
+				},
+			},
+		},
+	}
+	digest, err := SelectManifest(list)
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:amd64", digest)
+
+	// the first one if no linux/amd64 manifest
+	list.Manifests = list.Manifests[:1]
+	digest, err = SelectManifest(list)
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:arm64", digest)
+}
+
+type fakeListKeeper struct {
+	fakeRegistry
+}
+
+func (f *fakeListKeeper) KeepManifestLists() bool {
+	return true
+}
+
+func TestHandleManifestList(t *testing.T) {
+	list, err := manifestlist.FromDescriptors([]manifestlist.ManifestDescriptor{
+		{
+			Descriptor: distribution.Descriptor{
+				MediaType: schema2.MediaTypeManifest,
+				Digest:    "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7",
+			},
+			Platform: manifestlist.PlatformSpec{Architecture: "amd64", OS: "linux"},
+		},
+	})
+	require.Nil(t, err)
+
+	// one manifest is abstracted from the list
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+		src:       &fakeRegistry{},
+		dst:       &fakeRegistry{},
+	}
+	manifest, digest, err := tr.handleManifest(list, "source", "sha256:list")
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7", digest)
+	mediaType, _, err := manifest.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, schema2.MediaTypeManifest, mediaType)
+
+	// the list is kept
+	tr.dst = &fakeListKeeper{}
+	manifest, digest, err = tr.handleManifest(list, "source", "sha256:list")
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:list", digest)
+	mediaType, _, err = manifest.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, manifestlist.MediaTypeManifestList, mediaType)
+
+	// the OCI image index is kept too
+	index, err := manifestlist.FromDescriptors([]manifestlist.ManifestDescriptor{
+		{
+			Descriptor: distribution.Descriptor{
+				MediaType: v1.MediaTypeImageManifest,
+				Digest:    "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7",
+			},
+			Platform: manifestlist.PlatformSpec{Architecture: "amd64", OS: "linux"},
+		},
+	})
+	require.Nil(t, err)
+	manifest, digest, err = tr.handleManifest(index, "source", "sha256:index")
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:index", digest)
+	mediaType, _, err = manifest.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, v1.MediaTypeImageIndex, mediaType)
+
+	// the OCI image manifest is returned as it is
+	oci, _, err := pkg_registry.UnMarshal(v1.MediaTypeImageManifest, []byte(`{
+		"schemaVersion": 2,
+		"config": {
+			"mediaType": "application/vnd.oci.image.config.v1+json",
+			"size": 7023,
+			"digest": "sha256:b5b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7"
+		},
+		"layers": []
+	}`))
+	require.Nil(t, err)
+	manifest, digest, err = tr.handleManifest(oci, "source", "sha256:oci")
+	require.Nil(t, err)
+	assert.Equal(t, "sha256:oci", digest)
+	assert.Equal(t, oci, manifest)
+}
+
+type pullRecorder struct {
+	fakeRegistry
+	sync.Mutex
+	manifests []string
+	blobs     []string
+}
+
+func (p *pullRecorder) PullManifest(repository, reference string, accepttedMediaTypes []string) (distribution.Manifest, string, error) {
+	p.Lock()
+	p.manifests = append(p.manifests, reference)
+	p.Unlock()
+	return p.fakeRegistry.PullManifest(repository, reference, accepttedMediaTypes)
+}
+
+func (p *pullRecorder) PullBlob(repository, digest string) (int64, io.ReadCloser, error) {
+	p.Lock()
+	p.blobs = append(p.blobs, digest)
+	p.Unlock()
+	return p.fakeRegistry.PullBlob(repository, digest)
+}
+
+func TestCopyContentOfIndex(t *testing.T) {
+	src := &pullRecorder{}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: func() bool { return false },
+		src:       src,
+		dst:       &fakeListKeeper{},
+	}
+
+	// the children of the OCI image index are copied as manifests rather than blobs
+	for _, mediaType := range []string{v1.MediaTypeImageManifest, v1.MediaTypeImageIndex} {
+		require.Nil(t, tr.copyContent(distribution.Descriptor{
+			MediaType: mediaType,
+			Digest:    "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7",
+		}, "source", "destination"))
+	}
+	assert.Equal(t, []string{
+		"sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7",
+		"sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7",
+	}, src.manifests)
+	// the config and layers of the pulled manifests
+	assert.Len(t, src.blobs, 8)
+}
+
 func TestDelete(t *testing.T) {
 	stopFunc := func() bool { return false }
 	tr := &transfer{
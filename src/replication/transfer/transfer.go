@@ -23,6 +23,8 @@ import (
 
 var (
 	registry = map[model.ResourceType]Factory{}
+	// ErrOutOfTimeWindows is returned by the transfer which stops as it's out of the time windows of the policy
+	ErrOutOfTimeWindows = errors.New("out of the time windows of the policy")
 )
 
 // Factory creates a specific Transfer. The "Logger" is used
@@ -119,3 +119,21 @@ func IsSpecificPathComponent(component string) ([]string, bool) {
 	}
 	return components, true
 }
+
+// WildcardPattern converts the pattern into the simple wildcard pattern which only contains "*"
+// matching any characters, it's used to push the filters down to the registries whose search
+// APIs support such wildcards. The converted pattern matches more than the original one
+// as the "*" matches "/" as well, so the results still need to be filtered by the original
+// pattern. Returns false if the pattern contains other special characters
+// "library/*" is converted to "library/*"
+// "library/**" is converted to "library/*"
+// "library/{test,busybox}" cannot be converted
+func WildcardPattern(pattern string) (string, bool) {
+	if len(pattern) == 0 || strings.ContainsAny(pattern, "?[\\]^{},") {
+		return "", false
+	}
+	for strings.Contains(pattern, "**") {
+		pattern = strings.Replace(pattern, "**", "*", -1)
+	}
+	return pattern, true
+}
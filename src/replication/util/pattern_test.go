@@ -176,3 +176,49 @@ func TestIsSpecificPath(t *testing.T) {
 		}
 	}
 }
+
+func TestWildcardPattern(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		ok       bool
+		wildcard string
+	}{
+		{
+			pattern: "",
+			ok:      false,
+		},
+		{
+			pattern:  "library/hello-world",
+			ok:       true,
+			wildcard: "library/hello-world",
+		},
+		{
+			pattern:  "library/*",
+			ok:       true,
+			wildcard: "library/*",
+		},
+		{
+			pattern:  "library/**",
+			ok:       true,
+			wildcard: "library/*",
+		},
+		{
+			pattern:  "***/hello*",
+			ok:       true,
+			wildcard: "*/hello*",
+		},
+		{
+			pattern: "library/{hello-world,busybox}",
+			ok:      false,
+		},
+		{
+			pattern: "library/hello?world",
+			ok:      false,
+		},
+	}
+	for _, c := range cases {
+		wildcard, ok := WildcardPattern(c.pattern)
+		require.Equal(t, c.ok, ok, c.pattern)
+		assert.Equal(t, c.wildcard, wildcard, c.pattern)
+	}
+}
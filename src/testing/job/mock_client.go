@@ -1,7 +1,9 @@
 package job
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"math/rand"
 
 	"github.com/goharbor/harbor/src/common/http"
@@ -25,6 +27,18 @@ func (mjc *MockJobClient) GetJobLog(uuid string) ([]byte, error) {
 	return nil, &http.Error{404, "not Found"}
 }
 
+// FollowJobLog ...
+func (mjc *MockJobClient) FollowJobLog(ctx context.Context, uuid string, offset int64, w io.Writer) error {
+	data, err := mjc.GetJobLog(uuid)
+	if err != nil {
+		return err
+	}
+	if offset < int64(len(data)) {
+		_, err = w.Write(data[offset:])
+	}
+	return err
+}
+
 // SubmitJob ...
 func (mjc *MockJobClient) SubmitJob(data *models.JobData) (string, error) {
 	uuid := fmt.Sprintf("u-%d", rand.Int())